@@ -4,6 +4,9 @@ import (
 	"medication-scheduler/internal/app"
 	"medication-scheduler/internal/config"
 	"medication-scheduler/pkg/logger"
+
+	// База часовых поясов встраивается в бинарник: образ собирается FROM scratch
+	_ "time/tzdata"
 )
 
 func main() {
@@ -5,14 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"medication-scheduler/internal/auth"
 	"medication-scheduler/internal/config"
 	"medication-scheduler/internal/database"
+	"medication-scheduler/internal/domain"
 	"medication-scheduler/internal/handlers"
+	"medication-scheduler/internal/reminder"
 	"medication-scheduler/internal/repository"
 	"medication-scheduler/internal/service"
+	"medication-scheduler/internal/stream"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,12 +27,31 @@ import (
 )
 
 type App struct {
-	cfg     *config.Config
-	logger  *slog.Logger
-	router  *gin.Engine
-	server  *http.Server
-	dbPool  *pgxpool.Pool
-	handler *handlers.ScheduleHandler
+	cfg       *config.Config
+	logger    *slog.Logger
+	router    *gin.Engine
+	server    *http.Server
+	dbPool    *pgxpool.Pool
+	handler   *handlers.ScheduleHandler
+	settings  *handlers.SettingsHandler
+	doses     *handlers.DoseHandler
+	adherence *handlers.AdherenceHandler
+	stream    *handlers.StreamHandler
+	auth      *handlers.AuthHandler
+	sharing   *handlers.SharingHandler
+	members   *handlers.MembershipHandler
+	invites   *handlers.InviteHandler
+	calendar  *handlers.CalendarFeedHandler
+	feeds     handlers.FeedResolver
+	roles     handlers.RoleLookup
+	tokens    *auth.Tokens
+	hub       *stream.Hub
+	watcher   *stream.Watcher
+	reminders *reminder.Dispatcher
+	relay     *reminder.Relay
+
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
 }
 
 func New(cfg *config.Config, logger *slog.Logger) (*App, error) {
@@ -36,23 +60,86 @@ func New(cfg *config.Config, logger *slog.Logger) (*App, error) {
 	router.Use(sloggin.New(logger))
 	router.Use(gin.Recovery())
 
+	tokens, err := auth.NewTokens(cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure authentication: %w", err)
+	}
+
 	dbPool, err := database.NewPostgresDB(cfg.DBConfig)
 	if err != nil {
 		logger.Error("Failed to connect to database", "error", err)
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	hub := stream.NewHub(cfg.Stream)
+
+	grantRepo := repository.NewGrantRepository(dbPool)
+	membershipRepo := repository.NewMembershipRepository(dbPool)
+	access := service.NewAccess(grantRepo, membershipRepo)
+
 	repo := repository.New(dbPool)
-	service := service.New(repo, cfg.NextTakingsPeriod)
+	scheduleService := service.New(repo, cfg.NextTakingsPeriod, cfg.Rounding, hub, access)
+
+	handler := handlers.New(scheduleService, logger)
+
+	settingsRepo := repository.NewSettingsRepository(dbPool)
+	settings := handlers.NewSettingsHandler(service.NewSettingsService(settingsRepo, access), logger)
 
-	handler := handlers.New(service, logger)
+	doseRepo := repository.NewDoseRepository(dbPool)
+	doses := handlers.NewDoseHandler(service.NewDoseService(doseRepo, repo, hub, access), logger)
+	adherence := handlers.NewAdherenceHandler(service.NewAdherenceService(repo, doseRepo, access), logger)
+	streamHandler := handlers.NewStreamHandler(hub, cfg.Stream.Heartbeat, logger)
+	watcher := stream.NewWatcher(scheduleService, hub, cfg.Stream, logger)
+	userRepo := repository.NewUserRepository(dbPool)
+	organizationRepo := repository.NewOrganizationRepository(dbPool)
+	authHandler := handlers.NewAuthHandler(service.NewUserService(userRepo, tokens), logger)
+	sharing := handlers.NewSharingHandler(service.NewSharingService(grantRepo, userRepo, scheduleService), logger)
+	membershipService := service.NewMembershipService(membershipRepo, userRepo)
+	members := handlers.NewMembershipHandler(membershipService, logger)
+	invites := handlers.NewInviteHandler(service.NewInviteService(repository.NewInviteRepository(dbPool)), logger)
+	feedService := service.NewCalendarFeedService(repository.NewCalendarFeedRepository(dbPool))
+	calendar := handlers.NewCalendarFeedHandler(feedService, logger)
+
+	var notifiers []reminder.Notifier
+	if cfg.Reminder.WebhookURL != "" {
+		notifiers = append(notifiers, reminder.NewWebhookNotifier(cfg.Reminder.WebhookURL))
+	}
+	if cfg.Reminder.SMTP.Addr != "" {
+		notifiers = append(notifiers, reminder.NewSMTPNotifier(cfg.Reminder.SMTP))
+	}
+
+	var (
+		reminders *reminder.Dispatcher
+		relay     *reminder.Relay
+	)
+	if len(notifiers) > 0 {
+		outbox := repository.NewOutboxRepository(dbPool)
+		reminders = reminder.NewDispatcher(repo, organizationRepo, outbox, cfg.Reminder, logger)
+		relay = reminder.NewRelay(outbox, notifiers, cfg.Reminder, logger)
+	}
 
 	return &App{
-		cfg:     cfg,
-		logger:  logger,
-		router:  router,
-		dbPool:  dbPool,
-		handler: handler,
+		cfg:       cfg,
+		logger:    logger,
+		router:    router,
+		dbPool:    dbPool,
+		handler:   handler,
+		settings:  settings,
+		doses:     doses,
+		adherence: adherence,
+		stream:    streamHandler,
+		auth:      authHandler,
+		sharing:   sharing,
+		members:   members,
+		invites:   invites,
+		calendar:  calendar,
+		feeds:     feedService,
+		roles:     membershipService,
+		tokens:    tokens,
+		hub:       hub,
+		watcher:   watcher,
+		reminders: reminders,
+		relay:     relay,
 	}, nil
 }
 
@@ -64,10 +151,59 @@ func (a *App) setupRouters() {
 		})
 	})
 
-	a.router.POST("schedule", a.handler.CreateSchedule)
-	a.router.GET("schedules", a.handler.GetSchedules)
-	a.router.GET("schedule", a.handler.GetExactSchedule)
-	a.router.GET("next_takings", a.handler.GetNextTakings)
+	a.router.POST("auth/register", a.auth.Register)
+	a.router.POST("auth/login", a.auth.Login)
+	a.router.POST("auth/logout", a.auth.Logout)
+
+	can := func(perm domain.Permission) gin.HandlerFunc {
+		return handlers.Require(a.roles, perm, a.logger)
+	}
+
+	// Календарные приложения не передают заголовков: календарь отдаётся по
+	// ссылке подписки с токеном, а не по JWT.
+	a.router.GET("schedules.ics", handlers.AuthenticateFeed(a.feeds, a.logger), can(domain.PermView), a.handler.ExportCalendar)
+
+	// Остальные маршруты — только с токеном; пользователь берётся из него,
+	// а допустимые действия — из его роли в клинике.
+	api := a.router.Group("", handlers.Authenticate(a.tokens, a.logger))
+
+	api.POST("schedule", can(domain.PermPrescribe), a.handler.CreateSchedule)
+	api.GET("schedules", can(domain.PermView), a.handler.GetSchedules)
+	api.POST("calendar/feed", can(domain.PermView), a.calendar.IssueFeed)
+	api.DELETE("calendar/feed", can(domain.PermView), a.calendar.RevokeFeed)
+	api.GET("schedule", can(domain.PermView), a.handler.GetExactSchedule)
+	api.PATCH("schedule", can(domain.PermPrescribe), a.handler.UpdateSchedule)
+	api.DELETE("schedule", can(domain.PermPrescribe), a.handler.DeleteSchedule)
+	api.POST("schedule/pause", can(domain.PermPrescribe), a.handler.PauseSchedule)
+	api.POST("schedule/resume", can(domain.PermPrescribe), a.handler.ResumeSchedule)
+	api.GET("schedule/history", can(domain.PermView), a.handler.GetScheduleHistory)
+	api.GET("next_takings", can(domain.PermView), a.handler.GetNextTakings)
+	api.GET("household", can(domain.PermView), a.handler.GetHouseholdTakings)
+	api.GET("takings", can(domain.PermView), a.handler.GetTakings)
+
+	api.GET("settings", can(domain.PermView), a.settings.GetSettings)
+	api.PUT("settings", can(domain.PermPrescribe), a.settings.UpdateSettings)
+
+	api.POST("doses", can(domain.PermRecordDoses), a.doses.RecordDose)
+	api.GET("doses", can(domain.PermView), a.doses.GetDoses)
+	api.GET("doses/can_take", can(domain.PermView), a.doses.CheckIntake)
+	api.GET("adherence", can(domain.PermView), a.adherence.GetAdherence)
+
+	api.GET("stream", can(domain.PermView), a.stream.Stream)
+
+	// Выдаёт доступ пациент; принять его, отказаться и смотреть пациентов
+	// может любое доверенное лицо.
+	api.POST("grants", can(domain.PermShare), a.sharing.Invite)
+	api.GET("grants", can(domain.PermShare), a.sharing.GetGranted)
+	api.DELETE("grants", can(domain.PermView), a.sharing.Revoke)
+	api.GET("grants/received", can(domain.PermView), a.sharing.GetReceived)
+	api.POST("grants/accept", can(domain.PermView), a.sharing.Accept)
+	api.GET("patients", can(domain.PermView), a.sharing.GetPatients)
+
+	api.GET("members", can(domain.PermManageMembers), a.members.GetMembers)
+	api.PUT("members", can(domain.PermManageMembers), a.members.AssignMember)
+	api.DELETE("members", can(domain.PermManageMembers), a.members.RemoveMember)
+	api.POST("invites", can(domain.PermManageMembers), a.invites.CreateInvite)
 }
 
 func (a *App) Run() error {
@@ -77,6 +213,8 @@ func (a *App) Run() error {
 		Addr:    ":" + a.cfg.ServerPort,
 		Handler: a.router,
 	}
+	// Потоки SSE не завершаются сами: закрываем их, чтобы Shutdown не ждал клиентов.
+	a.server.RegisterOnShutdown(a.hub.Close)
 
 	errChan := make(chan error)
 	shutdownErrChan := make(chan error)
@@ -90,6 +228,8 @@ func (a *App) Run() error {
 
 	a.logger.Info("Server started on: " + a.cfg.ServerPort)
 
+	a.startWorkers()
+
 	go func() {
 		shutdownErrChan <- a.waitForShutdown()
 	}()
@@ -103,6 +243,31 @@ func (a *App) Run() error {
 
 }
 
+// startWorkers запускает фоновые задачи; они останавливаются в
+// waitForShutdown до закрытия пула соединений.
+func (a *App) startWorkers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		a.watcher.Run(ctx)
+	}()
+
+	if a.reminders != nil {
+		a.workers.Add(2)
+		go func() {
+			defer a.workers.Done()
+			a.reminders.Run(ctx)
+		}()
+		go func() {
+			defer a.workers.Done()
+			a.relay.Run(ctx)
+		}()
+	}
+}
+
 func (a *App) waitForShutdown() error {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -114,6 +279,10 @@ func (a *App) waitForShutdown() error {
 	defer cancel()
 
 	defer func() {
+		a.stopWorkers()
+		a.workers.Wait()
+		a.logger.Info("Background workers stopped")
+
 		a.dbPool.Close()
 		a.logger.Info("Database connection pool closed")
 	}()
@@ -0,0 +1,37 @@
+package auth
+
+import "context"
+
+type (
+	userIDKey         struct{}
+	organizationIDKey struct{}
+)
+
+// WithUserID сохраняет в ctx пользователя, от имени которого выполняется
+// запрос. Сервисы сверяют с ним, к чьим данным запрошен доступ.
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFrom возвращает пользователя, сохранённого WithUserID.
+func UserIDFrom(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int)
+	return userID, ok && userID > 0
+}
+
+// WithOrganizationID сохраняет в ctx организацию, в которой выполняется
+// запрос. Репозиторий расписаний без неё не работает.
+func WithOrganizationID(ctx context.Context, organizationID int) context.Context {
+	return context.WithValue(ctx, organizationIDKey{}, organizationID)
+}
+
+// OrganizationIDFrom возвращает организацию, сохранённую WithOrganizationID.
+func OrganizationIDFrom(ctx context.Context) (int, bool) {
+	organizationID, ok := ctx.Value(organizationIDKey{}).(int)
+	return organizationID, ok && organizationID > 0
+}
+
+// WithIdentity сохраняет в ctx и пользователя, и его организацию.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return WithOrganizationID(WithUserID(ctx, identity.UserID), identity.OrganizationID)
+}
@@ -0,0 +1,23 @@
+package auth_test
+
+import (
+	"testing"
+
+	"medication-scheduler/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecrets(t *testing.T) {
+	first, err := auth.NewSecret()
+	require.NoError(t, err)
+	second, err := auth.NewSecret()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 43)
+	assert.Equal(t, auth.HashSecret(first), auth.HashSecret(first))
+	assert.NotEqual(t, auth.HashSecret(first), auth.HashSecret(second))
+	assert.NotContains(t, auth.HashSecret(first), first)
+}
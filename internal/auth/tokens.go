@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingSecret = errors.New("auth secret is not configured")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+)
+
+type Config struct {
+	// Secret — ключ подписи токенов (HS256); без него сервер не запускается.
+	Secret string
+	// TokenTTL — срок действия токена, выданного при входе.
+	TokenTTL time.Duration
+}
+
+// Identity — пользователь и организация (клиника), в которой он
+// зарегистрирован.
+type Identity struct {
+	UserID         int
+	OrganizationID int
+}
+
+// claims — поля токена: ID пользователя в sub, организация в org.
+type claims struct {
+	jwt.RegisteredClaims
+	OrganizationID int `json:"org"`
+}
+
+// Tokens выдаёт и проверяет JWT пользователя.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokens(cfg Config) (*Tokens, error) {
+	if cfg.Secret == "" {
+		return nil, ErrMissingSecret
+	}
+	return &Tokens{secret: []byte(cfg.Secret), ttl: cfg.TokenTTL}, nil
+}
+
+// Issue выдаёт токен пользователя и возвращает момент, до которого он
+// действует.
+func (t *Tokens) Issue(identity Identity, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(t.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(identity.UserID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		OrganizationID: identity.OrganizationID,
+	})
+
+	signed, err := token.SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает его
+// владельца. Токены без организации, выданные до её появления,
+// недействительны.
+func (t *Tokens) Verify(token string) (Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.Atoi(c.Subject)
+	if err != nil || userID <= 0 || c.OrganizationID <= 0 {
+		return Identity{}, ErrInvalidToken
+	}
+	return Identity{UserID: userID, OrganizationID: c.OrganizationID}, nil
+}
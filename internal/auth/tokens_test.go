@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokens(t *testing.T, secret string) *auth.Tokens {
+	tokens, err := auth.NewTokens(auth.Config{Secret: secret, TokenTTL: time.Hour})
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestTokens(t *testing.T) {
+	tokens := newTokens(t, "secret")
+
+	t.Run("round trip", func(t *testing.T) {
+		now := time.Now()
+		token, expiresAt, err := tokens.Issue(auth.Identity{UserID: 42, OrganizationID: 3}, now)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(time.Hour), expiresAt)
+
+		identity, err := tokens.Verify(token)
+		require.NoError(t, err)
+		assert.Equal(t, auth.Identity{UserID: 42, OrganizationID: 3}, identity)
+	})
+
+	t.Run("without organization", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   "42",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = tokens.Verify(token)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, _, err := tokens.Issue(auth.Identity{UserID: 42, OrganizationID: 1}, time.Now().Add(-2*time.Hour))
+		require.NoError(t, err)
+
+		_, err = tokens.Verify(token)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("foreign signature", func(t *testing.T) {
+		token, _, err := newTokens(t, "other").Issue(auth.Identity{UserID: 42, OrganizationID: 1}, time.Now())
+		require.NoError(t, err)
+
+		_, err = tokens.Verify(token)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("unsigned token", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+			Subject:   "42",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = tokens.Verify(token)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := tokens.Verify("not-a-token")
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+}
+
+func TestNewTokens_RequiresSecret(t *testing.T) {
+	_, err := auth.NewTokens(auth.Config{TokenTTL: time.Hour})
+	assert.ErrorIs(t, err, auth.ErrMissingSecret)
+}
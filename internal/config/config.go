@@ -2,8 +2,13 @@ package config
 
 import (
 	"log"
+	"medication-scheduler/internal/auth"
 	"medication-scheduler/internal/database"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/reminder"
+	"medication-scheduler/internal/stream"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -12,6 +17,11 @@ type Config struct {
 	ServerPort        string
 	LogLevel          string
 	NextTakingsPeriod time.Duration
+	// Rounding — округление приёмов для новых расписаний без своей политики.
+	Rounding domain.Rounding
+	Reminder reminder.Config
+	Stream   stream.Config
+	Auth     auth.Config
 }
 
 func LoadConfig() *Config {
@@ -26,6 +36,30 @@ func LoadConfig() *Config {
 		ServerPort:        getEnv("SERVER_PORT", "8080"),
 		LogLevel:          getEnv("LOG_LEVEL", "info"),
 		NextTakingsPeriod: ParseDuration(getEnv("NEXT_TAKINGS_PERIOD", "1h")),
+		Rounding:          parseRounding(getEnv("ROUNDING_MODE", "up"), getEnv("ROUNDING_STEP", "15m")),
+		Reminder: reminder.Config{
+			Interval:     ParseDuration(getEnv("REMINDER_INTERVAL", "1m")),
+			Lead:         ParseDuration(getEnv("REMINDER_LEAD", "15m")),
+			MaxAttempts:  parseInt(getEnv("REMINDER_MAX_ATTEMPTS", "5")),
+			RetryBackoff: ParseDuration(getEnv("REMINDER_RETRY_BACKOFF", "30s")),
+			MaxAge:       ParseDuration(getEnv("REMINDER_MAX_AGE", "1h")),
+			WebhookURL:   getEnv("REMINDER_WEBHOOK_URL", ""),
+			SMTP: reminder.SMTPConfig{
+				Addr:     getEnv("SMTP_ADDR", ""),
+				Username: getEnv("SMTP_USER", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+				From:     getEnv("SMTP_FROM", ""),
+			},
+		},
+		Stream: stream.Config{
+			Interval:  ParseDuration(getEnv("STREAM_INTERVAL", "30s")),
+			Heartbeat: ParseDuration(getEnv("STREAM_HEARTBEAT", "15s")),
+			History:   parseInt(getEnv("STREAM_HISTORY", "100")),
+		},
+		Auth: auth.Config{
+			Secret:   getEnv("AUTH_SECRET", ""),
+			TokenTTL: ParseDuration(getEnv("AUTH_TOKEN_TTL", "24h")),
+		},
 	}
 }
 
@@ -43,3 +77,19 @@ func ParseDuration(s string) time.Duration {
 	}
 	return d
 }
+
+func parseRounding(mode, step string) domain.Rounding {
+	rounding := domain.Rounding{Mode: domain.RoundingMode(mode), Step: ParseDuration(step)}
+	if err := rounding.Validate(); err != nil {
+		log.Panicf("invalid rounding: %v", err)
+	}
+	return rounding
+}
+
+func parseInt(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		log.Panicf("invalid integer format: %v", err)
+	}
+	return n
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"medication-scheduler/internal/config"
+	"medication-scheduler/internal/domain"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,18 +19,33 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, "8080", cfg.ServerPort)
 		assert.Equal(t, "info", cfg.LogLevel)
 		assert.Equal(t, time.Hour, cfg.NextTakingsPeriod)
+		assert.Equal(t, domain.DefaultRounding, cfg.Rounding)
+		assert.Equal(t, time.Minute, cfg.Reminder.Interval)
+		assert.Equal(t, 15*time.Minute, cfg.Reminder.Lead)
+		assert.Equal(t, 5, cfg.Reminder.MaxAttempts)
+		assert.Equal(t, 30*time.Second, cfg.Reminder.RetryBackoff)
+		assert.Equal(t, time.Hour, cfg.Reminder.MaxAge)
+		assert.Empty(t, cfg.Reminder.WebhookURL)
+		assert.Equal(t, 30*time.Second, cfg.Stream.Interval)
+		assert.Equal(t, 15*time.Second, cfg.Stream.Heartbeat)
+		assert.Equal(t, 100, cfg.Stream.History)
+		assert.Empty(t, cfg.Auth.Secret)
+		assert.Equal(t, 24*time.Hour, cfg.Auth.TokenTTL)
 	})
 
 	t.Run("Environment variables", func(t *testing.T) {
 		os.Setenv("SERVER_PORT", "3000")
 		os.Setenv("LOG_LEVEL", "debug")
 		os.Setenv("NEXT_TAKINGS_PERIOD", "2h")
+		os.Setenv("ROUNDING_MODE", "nearest")
+		os.Setenv("ROUNDING_STEP", "5m")
 
 		cfg := config.LoadConfig()
 
 		assert.Equal(t, "3000", cfg.ServerPort)
 		assert.Equal(t, "debug", cfg.LogLevel)
 		assert.Equal(t, 2*time.Hour, cfg.NextTakingsPeriod)
+		assert.Equal(t, domain.Rounding{Mode: domain.RoundNearest, Step: 5 * time.Minute}, cfg.Rounding)
 
 		os.Clearenv()
 	})
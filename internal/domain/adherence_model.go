@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+var ErrInvalidGrouping = errors.New("group must be day or week")
+
+// LateTolerance — насколько позже плана приём ещё считается своевременным.
+const LateTolerance = 15 * time.Minute
+
+type Grouping string
+
+const (
+	GroupByDay  Grouping = "day"
+	GroupByWeek Grouping = "week"
+)
+
+// AdherenceStats — сводка по приёмам, срок которых уже наступил.
+// Пропущенный (Missed) приём — наступивший приём без отметки о приёме или
+// пропуске; отложенный и так и не принятый приём тоже считается пропущенным.
+type AdherenceStats struct {
+	Due            int
+	Taken          int
+	Late           int
+	Skipped        int
+	Missed         int
+	Upcoming       int
+	LateMinutes    int
+	AdherencePct   float64
+	AvgLateMinutes float64
+	CurrentStreak  int
+	LongestStreak  int
+}
+
+type AdherencePeriod struct {
+	Start time.Time
+	End   time.Time
+	Stats AdherenceStats
+}
+
+type ScheduleAdherence struct {
+	ScheduleID int
+	Medication string
+	Stats      AdherenceStats
+}
+
+type AdherenceReport struct {
+	From      time.Time
+	To        time.Time
+	Grouping  Grouping
+	Summary   AdherenceStats
+	Periods   []AdherencePeriod
+	Schedules []ScheduleAdherence
+}
+
+type doseKey struct {
+	scheduleID int
+	plannedAt  time.Time
+}
+
+type plannedDose struct {
+	scheduleID int
+	plannedAt  time.Time
+	event      *DoseEvent
+}
+
+// BuildAdherence сравнивает запланированные в [from, to) приёмы с
+// отметками и агрегирует их по периодам group в часовом поясе loc.
+// Приёмы позже now считаются предстоящими и в процент не входят.
+// Серии (streaks) считаются в днях, в которые все наступившие приёмы
+// были приняты.
+func BuildAdherence(schedules []Schedule, events []DoseEvent, from, to, now time.Time, group Grouping, loc *time.Location) (*AdherenceReport, error) {
+	if group != GroupByDay && group != GroupByWeek {
+		return nil, ErrInvalidGrouping
+	}
+
+	byKey := make(map[doseKey]*DoseEvent, len(events))
+	for i := range events {
+		byKey[doseKey{events[i].ScheduleID, events[i].PlannedAt.UTC()}] = &events[i]
+	}
+
+	var doses []plannedDose
+	for _, schedule := range schedules {
+		for _, taking := range schedule.PlannedTakings(from, to) {
+			doses = append(doses, plannedDose{
+				scheduleID: schedule.ID,
+				plannedAt:  taking,
+				event:      byKey[doseKey{schedule.ID, taking.UTC()}],
+			})
+		}
+	}
+	slices.SortFunc(doses, func(a, b plannedDose) int { return a.plannedAt.Compare(b.plannedAt) })
+
+	report := &AdherenceReport{From: from, To: to, Grouping: group}
+	report.Summary = aggregate(doses, now)
+	report.Summary.CurrentStreak, report.Summary.LongestStreak = streaks(doses, now, loc)
+
+	for start := periodStart(from.In(loc), group); start.Before(to); {
+		end := nextPeriod(start, group)
+		var inPeriod []plannedDose
+		for _, dose := range doses {
+			if !dose.plannedAt.Before(start) && dose.plannedAt.Before(end) {
+				inPeriod = append(inPeriod, dose)
+			}
+		}
+		report.Periods = append(report.Periods, AdherencePeriod{Start: start, End: end, Stats: aggregate(inPeriod, now)})
+		start = end
+	}
+
+	for _, schedule := range schedules {
+		var own []plannedDose
+		for _, dose := range doses {
+			if dose.scheduleID == schedule.ID {
+				own = append(own, dose)
+			}
+		}
+		stats := aggregate(own, now)
+		stats.CurrentStreak, stats.LongestStreak = streaks(own, now, loc)
+		report.Schedules = append(report.Schedules, ScheduleAdherence{
+			ScheduleID: schedule.ID,
+			Medication: schedule.Medication,
+			Stats:      stats,
+		})
+	}
+
+	return report, nil
+}
+
+func aggregate(doses []plannedDose, now time.Time) AdherenceStats {
+	var stats AdherenceStats
+	for _, dose := range doses {
+		if dose.plannedAt.After(now) {
+			stats.Upcoming++
+			continue
+		}
+
+		stats.Due++
+		switch {
+		case dose.event != nil && dose.event.Status == DoseTaken:
+			stats.Taken++
+			if late := dose.event.TakenAt.Sub(dose.plannedAt); late > LateTolerance {
+				stats.Late++
+				stats.LateMinutes += int(late / time.Minute)
+			}
+		case dose.event != nil && dose.event.Status == DoseSkipped:
+			stats.Skipped++
+		default:
+			stats.Missed++
+		}
+	}
+
+	if stats.Due > 0 {
+		stats.AdherencePct = roundTo1(float64(stats.Taken) * 100 / float64(stats.Due))
+	}
+	if stats.Late > 0 {
+		stats.AvgLateMinutes = roundTo1(float64(stats.LateMinutes) / float64(stats.Late))
+	}
+	return stats
+}
+
+// streaks возвращает текущую и самую длинную серию дней без пропусков.
+// Дни без наступивших приёмов серию не прерывают.
+func streaks(doses []plannedDose, now time.Time, loc *time.Location) (int, int) {
+	type day struct{ due, taken int }
+
+	var (
+		order []time.Time
+		days  = make(map[time.Time]*day)
+	)
+	for _, dose := range doses {
+		if dose.plannedAt.After(now) {
+			continue
+		}
+		key := periodStart(dose.plannedAt.In(loc), GroupByDay)
+		if days[key] == nil {
+			days[key] = &day{}
+			order = append(order, key)
+		}
+		days[key].due++
+		if dose.event != nil && dose.event.Status == DoseTaken {
+			days[key].taken++
+		}
+	}
+
+	var current, longest int
+	for _, key := range order {
+		if days[key].taken == days[key].due {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return current, longest
+}
+
+func periodStart(t time.Time, group Grouping) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if group == GroupByWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // неделя начинается с понедельника
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func nextPeriod(start time.Time, group Grouping) time.Time {
+	if group == GroupByWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func roundTo1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestBuildAdherence(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	schedules := []domain.Schedule{
+		{
+			ID:         1,
+			Medication: "Aspirin",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		},
+	}
+
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+	events := []domain.DoseEvent{
+		{ScheduleID: 1, PlannedAt: at(1, 9), Status: domain.DoseTaken, TakenAt: at(1, 9)},
+		{ScheduleID: 1, PlannedAt: at(1, 21), Status: domain.DoseTaken, TakenAt: at(1, 21).Add(40 * time.Minute)},
+		{ScheduleID: 1, PlannedAt: at(2, 9), Status: domain.DoseSkipped, Reason: "nausea"},
+		{ScheduleID: 1, PlannedAt: at(3, 9), Status: domain.DoseTaken, TakenAt: at(3, 9).Add(5 * time.Minute)},
+	}
+
+	report, err := domain.BuildAdherence(schedules, events, from, to, now, domain.GroupByDay, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := report.Summary
+	// 6 приёмов за три дня: 21:00 3 января ещё не наступил, 21:00 2 января пропущен без отметки
+	expected := domain.AdherenceStats{
+		Due:            5,
+		Taken:          3,
+		Late:           1,
+		Skipped:        1,
+		Missed:         1,
+		Upcoming:       1,
+		LateMinutes:    40,
+		AdherencePct:   60,
+		AvgLateMinutes: 40,
+		CurrentStreak:  1,
+		LongestStreak:  1,
+	}
+	if summary != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summary)
+	}
+
+	if len(report.Periods) != 3 {
+		t.Fatalf("Expected 3 daily periods, got %d", len(report.Periods))
+	}
+	if report.Periods[0].Stats.AdherencePct != 100 || report.Periods[1].Stats.Taken != 0 {
+		t.Errorf("Unexpected daily stats: %+v", report.Periods)
+	}
+
+	if len(report.Schedules) != 1 || report.Schedules[0].Stats.Due != 5 {
+		t.Errorf("Unexpected per-schedule stats: %+v", report.Schedules)
+	}
+}
+
+func TestBuildAdherence_Weekly(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) // среда
+	to := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	schedules := []domain.Schedule{
+		{ID: 1, Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{9 * time.Hour}},
+	}
+
+	report, err := domain.BuildAdherence(schedules, nil, from, to, to, domain.GroupByWeek, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Periods) != 3 {
+		t.Fatalf("Expected 3 weekly periods, got %d", len(report.Periods))
+	}
+	if report.Periods[0].Start.Weekday() != time.Monday {
+		t.Errorf("Expected weeks to start on Monday, got %v", report.Periods[0].Start)
+	}
+	if report.Periods[0].Stats.Missed != 5 || report.Periods[1].Stats.Missed != 7 || report.Periods[2].Stats.Missed != 2 {
+		t.Errorf("Unexpected weekly stats: %+v", report.Periods)
+	}
+
+	if _, err := domain.BuildAdherence(schedules, nil, from, to, to, "month", time.UTC); err != domain.ErrInvalidGrouping {
+		t.Errorf("Expected ErrInvalidGrouping, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidEvents  = errors.New("events must be breakfast, lunch, dinner or bedtime with a time of day")
+	ErrInvalidAnchors = errors.New("anchors must be unique, reference breakfast, lunch, dinner or bedtime and be offset by whole minutes within 12 hours")
+)
+
+// MaxAnchorOffset ограничивает смещение приёма от события.
+const MaxAnchorOffset = 12 * time.Hour
+
+// DailyEvent — повседневное событие пациента, к которому привязывается
+// приём: «за 30 минут до завтрака», «во время ужина».
+type DailyEvent string
+
+const (
+	EventBreakfast DailyEvent = "breakfast"
+	EventLunch     DailyEvent = "lunch"
+	EventDinner    DailyEvent = "dinner"
+	EventBedtime   DailyEvent = "bedtime"
+)
+
+var AllDailyEvents = []DailyEvent{EventBreakfast, EventLunch, EventDinner, EventBedtime}
+
+// DefaultDailyEvents — время событий, если пациент не указал своё.
+var DefaultDailyEvents = DailyEvents{
+	EventBreakfast: 8 * time.Hour,
+	EventLunch:     13 * time.Hour,
+	EventDinner:    19 * time.Hour,
+	EventBedtime:   22 * time.Hour,
+}
+
+// DailyEvents — время событий пациента от местной полуночи.
+type DailyEvents map[DailyEvent]time.Duration
+
+func (e DailyEvents) Validate() error {
+	for event, clock := range e {
+		if !slices.Contains(AllDailyEvents, event) || clock < 0 || clock >= 24*time.Hour {
+			return ErrInvalidEvents
+		}
+	}
+	return nil
+}
+
+// Clock возвращает время события: заданное пациентом или по умолчанию.
+func (e DailyEvents) Clock(event DailyEvent) time.Duration {
+	if clock, ok := e[event]; ok {
+		return clock
+	}
+	return DefaultDailyEvents[event]
+}
+
+// Anchor — приём относительно события: Offset < 0 — до события, > 0 — после.
+type Anchor struct {
+	Event  DailyEvent
+	Offset time.Duration
+}
+
+// anchorClocks переводит привязки в время суток по событиям пациента.
+// Время вне окна приёма отбрасывается, совпадающее — учитывается один раз.
+func (s *Schedule) anchorClocks() []time.Duration {
+	window := s.DayWindow()
+	clocks := make([]time.Duration, 0, len(s.Anchors))
+	for _, anchor := range s.Anchors {
+		clock := (s.Events.Clock(anchor.Event) + anchor.Offset + 24*time.Hour) % (24 * time.Hour)
+		if window.Contains(clock) && !slices.Contains(clocks, clock) {
+			clocks = append(clocks, clock)
+		}
+	}
+	return clocks
+}
+
+// validateAnchors проверяет только сами привязки: время событий и окно
+// приёма пациент может поменять в настройках позже, поэтому попадание в
+// окно проверяется при расчёте приёмов.
+func (s *Schedule) validateAnchors() error {
+	if len(s.Anchors) == 0 {
+		return ErrInvalidAnchors
+	}
+	for i, anchor := range s.Anchors {
+		if !slices.Contains(AllDailyEvents, anchor.Event) || anchor.Offset%time.Minute != 0 ||
+			anchor.Offset < -MaxAnchorOffset || anchor.Offset > MaxAnchorOffset {
+			return ErrInvalidAnchors
+		}
+		if slices.Contains(s.Anchors[:i], anchor) {
+			return ErrInvalidAnchors
+		}
+	}
+	return nil
+}
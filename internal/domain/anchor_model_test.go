@@ -0,0 +1,138 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestCalculateTakings_EventRelative(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	now := time.Date(2025, 3, 3, 6, 0, 0, 0, loc)
+	at := func(hour, minute int) time.Time { return time.Date(2025, 3, 3, hour, minute, 0, 0, loc) }
+
+	tests := []struct {
+		name     string
+		anchors  []domain.Anchor
+		events   domain.DailyEvents
+		window   domain.DayWindow
+		expected []time.Time
+	}{
+		{
+			name: "Default events",
+			anchors: []domain.Anchor{
+				{Event: domain.EventBreakfast, Offset: 30 * time.Minute},
+				{Event: domain.EventDinner, Offset: time.Hour},
+			},
+			expected: []time.Time{at(8, 30), at(20, 0)},
+		},
+		{
+			name:     "User events",
+			anchors:  []domain.Anchor{{Event: domain.EventBreakfast, Offset: -30 * time.Minute}, {Event: domain.EventBedtime}},
+			events:   domain.DailyEvents{domain.EventBreakfast: 9 * time.Hour, domain.EventBedtime: 21*time.Hour + 30*time.Minute},
+			expected: []time.Time{at(8, 30), at(21, 30)},
+		},
+		{
+			name:     "Outside the window dropped",
+			anchors:  []domain.Anchor{{Event: domain.EventBreakfast, Offset: -time.Hour}, {Event: domain.EventLunch}},
+			window:   domain.DayWindow{Start: 9 * time.Hour, End: 21 * time.Hour},
+			expected: []time.Time{at(13, 0)},
+		},
+		{
+			name:     "Same time counted once",
+			anchors:  []domain.Anchor{{Event: domain.EventLunch, Offset: -time.Hour}, {Event: domain.EventBreakfast, Offset: 4 * time.Hour}},
+			expected: []time.Time{at(12, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := domain.Schedule{
+				Kind:      domain.KindEventRelative,
+				Anchors:   tt.anchors,
+				Events:    tt.events,
+				Window:    tt.window,
+				Timezone:  "Europe/Moscow",
+				StartTime: now.Add(-time.Hour),
+				EndTime:   domain.PerpetualEndTime,
+			}
+			if err := s.Validate(); err != nil {
+				t.Fatalf("Unexpected validation error: %v", err)
+			}
+
+			takings := s.CalculateTakings(now)
+			if len(takings) != len(tt.expected) {
+				t.Fatalf("Expected %d takings, got %d: %v", len(tt.expected), len(takings), takings)
+			}
+			for i, taking := range takings {
+				if !taking.Equal(tt.expected[i]) {
+					t.Errorf("Taking %d: expected %v, got %v", i, tt.expected[i], taking)
+				}
+			}
+		})
+	}
+}
+
+func TestFindNextTaking_EventRelative(t *testing.T) {
+	now := time.Date(2025, 3, 3, 20, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:      domain.KindEventRelative,
+		Anchors:   []domain.Anchor{{Event: domain.EventDinner, Offset: -15 * time.Minute}, {Event: domain.EventBedtime}},
+		Events:    domain.DailyEvents{domain.EventBedtime: 23 * time.Hour},
+		Window:    domain.DayWindow{Start: 7 * time.Hour, End: 23*time.Hour + 30*time.Minute},
+		Timezone:  "UTC",
+		StartTime: now.Add(-24 * time.Hour),
+		EndTime:   domain.PerpetualEndTime,
+	}
+
+	next, ok := s.FindNextTaking(now, now.Add(24*time.Hour))
+	if !ok {
+		t.Fatal("Expected next taking")
+	}
+	if expected := time.Date(2025, 3, 3, 23, 0, 0, 0, time.UTC); !next.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, next)
+	}
+}
+
+func TestValidate_Anchors(t *testing.T) {
+	tests := []struct {
+		name    string
+		anchors []domain.Anchor
+	}{
+		{"No anchors", nil},
+		{"Unknown event", []domain.Anchor{{Event: "brunch"}}},
+		{"Offset too large", []domain.Anchor{{Event: domain.EventLunch, Offset: 13 * time.Hour}}},
+		{"Offset not in minutes", []domain.Anchor{{Event: domain.EventLunch, Offset: 30 * time.Second}}},
+		{"Duplicate", []domain.Anchor{{Event: domain.EventLunch}, {Event: domain.EventLunch}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := domain.Schedule{Kind: domain.KindEventRelative, Anchors: tt.anchors, Timezone: "UTC"}
+			if err := s.Validate(); !errors.Is(err, domain.ErrInvalidAnchors) {
+				t.Errorf("Expected ErrInvalidAnchors, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDailyEvents(t *testing.T) {
+	events := domain.DailyEvents{domain.EventLunch: 12 * time.Hour}
+	if clock := events.Clock(domain.EventLunch); clock != 12*time.Hour {
+		t.Errorf("Expected user lunch time, got %v", clock)
+	}
+	if clock := events.Clock(domain.EventDinner); clock != 19*time.Hour {
+		t.Errorf("Expected default dinner time, got %v", clock)
+	}
+
+	for _, invalid := range []domain.DailyEvents{
+		{"brunch": 11 * time.Hour},
+		{domain.EventBedtime: 24 * time.Hour},
+	} {
+		if err := invalid.Validate(); !errors.Is(err, domain.ErrInvalidEvents) {
+			t.Errorf("Expected ErrInvalidEvents for %v, got %v", invalid, err)
+		}
+	}
+}
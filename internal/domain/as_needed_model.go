@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidAsNeeded = errors.New("as-needed schedule requires a positive min_interval, a non-negative max_per_day and no phases")
+	ErrNotAsNeeded     = errors.New("schedule is not an as-needed schedule")
+	ErrAsNeededStatus  = errors.New("as-needed schedule only records taken doses")
+)
+
+// IntakeLimitPeriod — скользящий период, на который действует MaxPerDay.
+const IntakeLimitPeriod = 24 * time.Hour
+
+// IntakeDecision — ответ на вопрос «можно ли принять дозу сейчас».
+type IntakeDecision struct {
+	Allowed bool
+	// EarliestAt — самый ранний момент, когда приём разрешён; совпадает с
+	// now, если можно сейчас. Нулевое значение — расписание больше не
+	// действует и приём не разрешён.
+	EarliestAt time.Time
+	// Taken — число приёмов за последние IntakeLimitPeriod.
+	Taken int
+}
+
+func (s *Schedule) validateAsNeeded() error {
+	if s.MinInterval <= 0 || s.MaxPerDay < 0 || len(s.Phases) > 0 {
+		return ErrInvalidAsNeeded
+	}
+	return nil
+}
+
+// CheckIntake решает, можно ли принять дозу по требованию в момент now,
+// исходя из уже сделанных приёмов: между приёмами должно пройти не меньше
+// MinInterval, а за любые IntakeLimitPeriod — не больше MaxPerDay приёмов
+// (0 — без ограничения). Приёмы позже now не учитываются.
+func (s *Schedule) CheckIntake(intakes []time.Time, now time.Time) IntakeDecision {
+	past := make([]time.Time, 0, len(intakes))
+	for _, intake := range intakes {
+		if !intake.After(now) {
+			past = append(past, intake)
+		}
+	}
+	slices.SortFunc(past, func(a, b time.Time) int { return a.Compare(b) })
+
+	decision := IntakeDecision{}
+	for _, intake := range past {
+		if intake.After(now.Add(-IntakeLimitPeriod)) {
+			decision.Taken++
+		}
+	}
+
+	earliest := latest(now, s.StartTime)
+	if n := len(past); n > 0 {
+		earliest = latest(earliest, past[n-1].Add(s.MinInterval))
+		if s.MaxPerDay > 0 && n >= s.MaxPerDay {
+			// Приём станет возможен, когда MaxPerDay-й с конца выйдет из периода.
+			earliest = latest(earliest, past[n-s.MaxPerDay].Add(IntakeLimitPeriod))
+		}
+	}
+
+	if s.IsPaused() && !earliest.Before(s.PausedAt) {
+		return decision
+	}
+	if s.Duration > 0 && !earliest.Before(s.EndTime) {
+		return decision
+	}
+
+	decision.EarliestAt = earliest
+	decision.Allowed = !earliest.After(now)
+	return decision
+}
@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestCheckIntake(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:        domain.KindAsNeeded,
+		MinInterval: 4 * time.Hour,
+		MaxPerDay:   3,
+		Timezone:    "UTC",
+		StartTime:   start,
+		EndTime:     domain.PerpetualEndTime,
+	}
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		intakes  []time.Time
+		now      time.Time
+		allowed  bool
+		earliest time.Time
+		taken    int
+	}{
+		{"No intakes", nil, at(2, 10), true, at(2, 10), 0},
+		{"Interval passed", []time.Time{at(2, 6)}, at(2, 10), true, at(2, 10), 1},
+		{"Too soon", []time.Time{at(2, 8)}, at(2, 10), false, at(2, 12), 1},
+		{"Daily maximum reached", []time.Time{at(2, 6), at(2, 10), at(2, 14)}, at(2, 20), false, at(3, 6), 3},
+		{"Oldest intake left the period", []time.Time{at(1, 18), at(2, 10), at(2, 14)}, at(2, 20), true, at(2, 20), 2},
+		{"Unsorted intakes", []time.Time{at(2, 14), at(2, 6), at(2, 10)}, at(2, 16), false, at(3, 6), 3},
+		{"Future intakes ignored", []time.Time{at(3, 1)}, at(2, 20), true, at(2, 20), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := s.CheckIntake(tt.intakes, tt.now)
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.allowed, decision.Allowed)
+			}
+			if !decision.EarliestAt.Equal(tt.earliest) {
+				t.Errorf("Expected earliest %v, got %v", tt.earliest, decision.EarliestAt)
+			}
+			if decision.Taken != tt.taken {
+				t.Errorf("Expected %d intakes in the last day, got %d", tt.taken, decision.Taken)
+			}
+		})
+	}
+}
+
+func TestCheckIntake_Bounds(t *testing.T) {
+	start := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:        domain.KindAsNeeded,
+		MinInterval: 6 * time.Hour,
+		Duration:    48 * time.Hour,
+		StartTime:   start,
+	}
+	s.SetEndTime()
+
+	before := s.CheckIntake(nil, start.Add(-time.Hour))
+	if before.Allowed || !before.EarliestAt.Equal(start) {
+		t.Errorf("Expected first intake at start %v, got %+v", start, before)
+	}
+
+	last := s.EndTime.Add(-time.Hour)
+	if decision := s.CheckIntake([]time.Time{last}, last.Add(time.Minute)); decision.Allowed || !decision.EarliestAt.IsZero() {
+		t.Errorf("Expected no further intakes after the schedule ends, got %+v", decision)
+	}
+
+	s.PausedAt = start.Add(time.Hour)
+	if decision := s.CheckIntake(nil, start.Add(2*time.Hour)); decision.Allowed {
+		t.Errorf("Expected no intakes while paused, got %+v", decision)
+	}
+}
+
+func TestAsNeeded_NoPlannedTakings(t *testing.T) {
+	s := domain.Schedule{
+		Kind:        domain.KindAsNeeded,
+		MinInterval: 4 * time.Hour,
+		Timezone:    "UTC",
+		StartTime:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected valid as-needed schedule, got %v", err)
+	}
+
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	if takings := s.PlannedTakings(s.StartTime, now); len(takings) != 0 {
+		t.Errorf("Expected no planned takings, got %v", takings)
+	}
+	if _, found := s.FindNextTaking(now, now.Add(24*time.Hour)); found {
+		t.Errorf("Expected no next taking for an as-needed schedule")
+	}
+
+	s.MinInterval = 0
+	if err := s.Validate(); !errors.Is(err, domain.ErrInvalidAsNeeded) {
+		t.Errorf("Expected ErrInvalidAsNeeded, got %v", err)
+	}
+}
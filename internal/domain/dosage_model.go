@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDosage = errors.New("dose requires a positive amount and a known unit; form and route must be known if set")
+
+// DoseUnit — единица количества лекарства на один приём.
+type DoseUnit string
+
+const (
+	UnitMg     DoseUnit = "mg"
+	UnitMcg    DoseUnit = "mcg"
+	UnitG      DoseUnit = "g"
+	UnitMl     DoseUnit = "ml"
+	UnitIU     DoseUnit = "IU"
+	UnitPuff   DoseUnit = "puff"
+	UnitDrop   DoseUnit = "drop"
+	UnitTablet DoseUnit = "tablet"
+	UnitPiece  DoseUnit = "piece"
+)
+
+// DoseForm — лекарственная форма.
+type DoseForm string
+
+const (
+	FormTablet      DoseForm = "tablet"
+	FormCapsule     DoseForm = "capsule"
+	FormSolution    DoseForm = "solution"
+	FormSuspension  DoseForm = "suspension"
+	FormInjection   DoseForm = "injection"
+	FormInhaler     DoseForm = "inhaler"
+	FormSpray       DoseForm = "spray"
+	FormDrops       DoseForm = "drops"
+	FormCream       DoseForm = "cream"
+	FormPatch       DoseForm = "patch"
+	FormSuppository DoseForm = "suppository"
+	FormPowder      DoseForm = "powder"
+)
+
+// DoseRoute — путь введения.
+type DoseRoute string
+
+const (
+	RouteOral          DoseRoute = "oral"
+	RouteSublingual    DoseRoute = "sublingual"
+	RouteInhaled       DoseRoute = "inhaled"
+	RouteNasal         DoseRoute = "nasal"
+	RouteTopical       DoseRoute = "topical"
+	RouteTransdermal   DoseRoute = "transdermal"
+	RouteOphthalmic    DoseRoute = "ophthalmic"
+	RouteOtic          DoseRoute = "otic"
+	RouteRectal        DoseRoute = "rectal"
+	RouteSubcutaneous  DoseRoute = "subcutaneous"
+	RouteIntramuscular DoseRoute = "intramuscular"
+	RouteIntravenous   DoseRoute = "intravenous"
+)
+
+var (
+	doseUnits  = []DoseUnit{UnitMg, UnitMcg, UnitG, UnitMl, UnitIU, UnitPuff, UnitDrop, UnitTablet, UnitPiece}
+	doseForms  = []DoseForm{FormTablet, FormCapsule, FormSolution, FormSuspension, FormInjection, FormInhaler, FormSpray, FormDrops, FormCream, FormPatch, FormSuppository, FormPowder}
+	doseRoutes = []DoseRoute{RouteOral, RouteSublingual, RouteInhaled, RouteNasal, RouteTopical, RouteTransdermal, RouteOphthalmic, RouteOtic, RouteRectal, RouteSubcutaneous, RouteIntramuscular, RouteIntravenous}
+)
+
+// Dosage — сколько и как принимать за один приём, например 500 mg, таблетки,
+// внутрь. Нулевое значение — доза не указана (расписания, созданные до
+// появления дозировки).
+type Dosage struct {
+	Amount float64
+	Unit   DoseUnit
+	Form   DoseForm
+	Route  DoseRoute
+}
+
+func (d Dosage) IsZero() bool {
+	return d == Dosage{}
+}
+
+func (d Dosage) Validate() error {
+	if d.Amount <= 0 || !slices.Contains(doseUnits, d.Unit) {
+		return ErrInvalidDosage
+	}
+	if d.Form != "" && !slices.Contains(doseForms, d.Form) {
+		return ErrInvalidDosage
+	}
+	if d.Route != "" && !slices.Contains(doseRoutes, d.Route) {
+		return ErrInvalidDosage
+	}
+	return nil
+}
+
+// String возвращает дозу в читаемом виде: «500 mg tablet, oral».
+func (d Dosage) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	parts := []string{strconv.FormatFloat(d.Amount, 'f', -1, 64), string(d.Unit)}
+	if d.Form != "" {
+		parts = append(parts, string(d.Form))
+	}
+	s := strings.Join(parts, " ")
+	if d.Route != "" {
+		s += ", " + string(d.Route)
+	}
+	return s
+}
+
+// DosageAt возвращает дозу, положенную на приём в момент t: для приёмов до
+// последнего изменения — по действовавшей тогда редакции, у ступенчатого
+// расписания — по этапу.
+func (s *Schedule) DosageAt(t time.Time) Dosage {
+	if t.Before(s.RevisedAt()) {
+		for _, revision := range s.Revisions {
+			if revision.Contains(t) {
+				return revision.Schedule.DosageAt(t)
+			}
+		}
+	}
+	if phase, ok := s.PhaseAt(t); ok && !phase.Dosage.IsZero() {
+		return phase.Dosage
+	}
+	return s.Dosage
+}
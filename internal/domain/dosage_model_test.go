@@ -0,0 +1,79 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestDosage_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		dosage domain.Dosage
+		valid  bool
+	}{
+		{"Full", domain.Dosage{Amount: 500, Unit: domain.UnitMg, Form: domain.FormTablet, Route: domain.RouteOral}, true},
+		{"Amount and unit only", domain.Dosage{Amount: 2, Unit: domain.UnitPuff}, true},
+		{"Zero amount", domain.Dosage{Unit: domain.UnitMg}, false},
+		{"Negative amount", domain.Dosage{Amount: -1, Unit: domain.UnitMg}, false},
+		{"Missing unit", domain.Dosage{Amount: 1}, false},
+		{"Unknown unit", domain.Dosage{Amount: 1, Unit: "spoon"}, false},
+		{"Unknown form", domain.Dosage{Amount: 1, Unit: domain.UnitMg, Form: "gummy"}, false},
+		{"Unknown route", domain.Dosage{Amount: 1, Unit: domain.UnitMg, Route: "by mouth"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dosage.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid dosage, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, domain.ErrInvalidDosage) {
+				t.Errorf("Expected ErrInvalidDosage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDosage_String(t *testing.T) {
+	tests := []struct {
+		dosage   domain.Dosage
+		expected string
+	}{
+		{domain.Dosage{Amount: 500, Unit: domain.UnitMg, Form: domain.FormTablet, Route: domain.RouteOral}, "500 mg tablet, oral"},
+		{domain.Dosage{Amount: 0.25, Unit: domain.UnitMg}, "0.25 mg"},
+		{domain.Dosage{Amount: 10, Unit: domain.UnitIU, Route: domain.RouteSubcutaneous}, "10 IU, subcutaneous"},
+		{domain.Dosage{}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dosage.String(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestDosageAt_Revisions(t *testing.T) {
+	revisedAt := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	before := domain.Dosage{Amount: 40, Unit: domain.UnitMg}
+	after := domain.Dosage{Amount: 20, Unit: domain.UnitMg}
+
+	s := domain.Schedule{
+		Dosage: after,
+		Revisions: []domain.ScheduleRevision{{
+			Number:        1,
+			EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			EffectiveTo:   revisedAt,
+			Schedule:      domain.Schedule{Dosage: before},
+		}},
+	}
+
+	if got := s.DosageAt(revisedAt.Add(-time.Hour)); got != before {
+		t.Errorf("Expected %v before revision, got %v", before, got)
+	}
+	if got := s.DosageAt(revisedAt); got != after {
+		t.Errorf("Expected %v after revision, got %v", after, got)
+	}
+}
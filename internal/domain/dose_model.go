@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidDoseStatus = errors.New("status must be one of taken, skipped, snoozed")
+	ErrSkipReason        = errors.New("skipped dose requires a reason")
+	ErrInvalidSnooze     = errors.New("snoozed dose requires snoozed_until after the planned time")
+	ErrInvalidTakenAt    = errors.New("taken dose requires taken_at")
+)
+
+type DoseStatus string
+
+const (
+	DoseTaken   DoseStatus = "taken"
+	DoseSkipped DoseStatus = "skipped"
+	DoseSnoozed DoseStatus = "snoozed"
+)
+
+// DoseEvent — что фактически произошло с запланированным приёмом.
+// Приём определяется парой (ScheduleID, PlannedAt), где PlannedAt — время
+// из CalculateTakings; повторная отметка заменяет предыдущую.
+type DoseEvent struct {
+	ID           int
+	UserID       int
+	ScheduleID   int
+	PlannedAt    time.Time
+	Status       DoseStatus
+	Reason       string
+	SnoozedUntil time.Time
+	TakenAt      time.Time
+	RecordedAt   time.Time
+}
+
+func (e *DoseEvent) Validate() error {
+	switch e.Status {
+	case DoseTaken:
+		if e.TakenAt.IsZero() {
+			return ErrInvalidTakenAt
+		}
+	case DoseSkipped:
+		if e.Reason == "" {
+			return ErrSkipReason
+		}
+	case DoseSnoozed:
+		if !e.SnoozedUntil.After(e.PlannedAt) {
+			return ErrInvalidSnooze
+		}
+	default:
+		return ErrInvalidDoseStatus
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestDoseEventValidate(t *testing.T) {
+	planned := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		event    domain.DoseEvent
+		expected error
+	}{
+		{"Taken", domain.DoseEvent{Status: domain.DoseTaken, PlannedAt: planned, TakenAt: planned}, nil},
+		{"Taken without time", domain.DoseEvent{Status: domain.DoseTaken, PlannedAt: planned}, domain.ErrInvalidTakenAt},
+		{"Skipped", domain.DoseEvent{Status: domain.DoseSkipped, Reason: "nausea"}, nil},
+		{"Skipped without reason", domain.DoseEvent{Status: domain.DoseSkipped}, domain.ErrSkipReason},
+		{"Snoozed", domain.DoseEvent{Status: domain.DoseSnoozed, PlannedAt: planned, SnoozedUntil: planned.Add(10 * time.Minute)}, nil},
+		{"Snoozed into the past", domain.DoseEvent{Status: domain.DoseSnoozed, PlannedAt: planned, SnoozedUntil: planned}, domain.ErrInvalidSnooze},
+		{"Unknown status", domain.DoseEvent{Status: "lost"}, domain.ErrInvalidDoseStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.event.Validate(); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestIsPlannedTaking(t *testing.T) {
+	schedule := domain.Schedule{
+		Frequency: 4 * time.Hour,
+		Window:    domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+	}
+
+	tests := []struct {
+		taking   time.Time
+		expected bool
+	}{
+		{time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 2, 4, 15, 0, 0, time.UTC), false},
+		{time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		if got := schedule.IsPlannedTaking(tt.taking); got != tt.expected {
+			t.Errorf("%v: expected %v, got %v", tt.taking, tt.expected, got)
+		}
+	}
+}
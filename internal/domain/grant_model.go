@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidAccess = errors.New("access must be read or manage")
+	ErrSelfGrant     = errors.New("access cannot be shared with yourself")
+)
+
+// AccessLevel — права доверенного лица на данные пациента: read — только
+// просмотр, manage — ещё и изменение расписаний, отметки о приёме и
+// настройки.
+type AccessLevel string
+
+const (
+	AccessRead   AccessLevel = "read"
+	AccessManage AccessLevel = "manage"
+)
+
+func (l AccessLevel) Validate() error {
+	if l != AccessRead && l != AccessManage {
+		return ErrInvalidAccess
+	}
+	return nil
+}
+
+// Allows сообщает, достаточно ли прав l для действия, требующего need.
+func (l AccessLevel) Allows(need AccessLevel) bool {
+	return l == AccessManage || l == need
+}
+
+type GrantStatus string
+
+const (
+	// GrantPending — приглашение отправлено, но доверенное лицо его ещё
+	// не приняло; доступа пока нет.
+	GrantPending  GrantStatus = "pending"
+	GrantAccepted GrantStatus = "accepted"
+)
+
+// Grant — доступ, который пациент выдал другой учётной записи (родителю,
+// медсестре). Пациент может изменить права повторным приглашением и
+// отозвать доступ; доверенное лицо — отказаться от него.
+type Grant struct {
+	ID             int
+	PatientID      int
+	PatientEmail   string
+	CaregiverID    int
+	CaregiverEmail string
+	Access         AccessLevel
+	Status         GrantStatus
+	CreatedAt      time.Time
+	AcceptedAt     time.Time
+}
+
+// Allows сообщает, даёт ли доступ право на действие need.
+func (g *Grant) Allows(need AccessLevel) bool {
+	return g.Status == GrantAccepted && g.Access.Allows(need)
+}
+
+// PatientTakings — ближайшие приёмы одного пациента для доверенного лица.
+type PatientTakings struct {
+	Grant     Grant
+	Schedules []Schedule
+}
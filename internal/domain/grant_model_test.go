@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestGrantAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		grant  domain.Grant
+		need   domain.AccessLevel
+		allows bool
+	}{
+		{"read grants read", domain.Grant{Access: domain.AccessRead, Status: domain.GrantAccepted}, domain.AccessRead, true},
+		{"read denies manage", domain.Grant{Access: domain.AccessRead, Status: domain.GrantAccepted}, domain.AccessManage, false},
+		{"manage grants read", domain.Grant{Access: domain.AccessManage, Status: domain.GrantAccepted}, domain.AccessRead, true},
+		{"manage grants manage", domain.Grant{Access: domain.AccessManage, Status: domain.GrantAccepted}, domain.AccessManage, true},
+		{"pending grants nothing", domain.Grant{Access: domain.AccessManage, Status: domain.GrantPending}, domain.AccessRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grant.Allows(tt.need); got != tt.allows {
+				t.Errorf("Allows(%s) = %v, want %v", tt.need, got, tt.allows)
+			}
+		})
+	}
+}
+
+func TestAccessLevelValidate(t *testing.T) {
+	for _, level := range []domain.AccessLevel{domain.AccessRead, domain.AccessManage} {
+		if err := level.Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v", level, err)
+		}
+	}
+	for _, level := range []domain.AccessLevel{"", "admin"} {
+		if err := level.Validate(); !errors.Is(err, domain.ErrInvalidAccess) {
+			t.Errorf("Validate(%q) = %v, want ErrInvalidAccess", level, err)
+		}
+	}
+}
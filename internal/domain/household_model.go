@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// PersonTakings — ближайшие приёмы одного человека в общей сводке: у
+// каждого расписания в Takings ровно один, ближайший, приём.
+type PersonTakings struct {
+	UserID    int
+	Schedules []Schedule
+}
+
+// Next возвращает самый ранний из ближайших приёмов человека.
+func (p PersonTakings) Next() (time.Time, bool) {
+	var next time.Time
+	for _, schedule := range p.Schedules {
+		if len(schedule.Takings) > 0 && (next.IsZero() || schedule.Takings[0].Before(next)) {
+			next = schedule.Takings[0]
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// SortHousehold упорядочивает сводку по времени: расписания каждого
+// человека — по ближайшему приёму, людей — по самому раннему из них. Люди
+// без приёмов в периоде идут последними, при равенстве порядок задаёт
+// UserID.
+func SortHousehold(people []PersonTakings) {
+	for _, person := range people {
+		slices.SortStableFunc(person.Schedules, func(a, b Schedule) int {
+			return compareTakings(a.Takings, b.Takings)
+		})
+	}
+
+	slices.SortStableFunc(people, func(a, b PersonTakings) int {
+		nextA, okA := a.Next()
+		nextB, okB := b.Next()
+		switch {
+		case okA && okB && !nextA.Equal(nextB):
+			return nextA.Compare(nextB)
+		case okA != okB:
+			if okA {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+}
+
+func compareTakings(a, b []time.Time) int {
+	if len(a) == 0 || len(b) == 0 {
+		return cmp.Compare(len(b), len(a))
+	}
+	return a[0].Compare(b[0])
+}
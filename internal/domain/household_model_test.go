@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"medication-scheduler/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestSortHousehold(t *testing.T) {
+	at := func(hour int) []time.Time {
+		return []time.Time{time.Date(2025, 1, 1, hour, 0, 0, 0, time.UTC)}
+	}
+
+	people := []domain.PersonTakings{
+		{UserID: 1, Schedules: []domain.Schedule{{ID: 11, Takings: at(14)}, {ID: 12, Takings: at(9)}}},
+		{UserID: 2},
+		{UserID: 3, Schedules: []domain.Schedule{{ID: 31, Takings: at(8)}}},
+		{UserID: 4, Schedules: []domain.Schedule{{ID: 41, Takings: at(9)}}},
+	}
+
+	domain.SortHousehold(people)
+
+	var order []int
+	for _, person := range people {
+		order = append(order, person.UserID)
+	}
+	want := []int{3, 1, 4, 2}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("people order = %v, want %v", order, want)
+		}
+	}
+
+	if people[1].Schedules[0].ID != 12 || people[1].Schedules[1].ID != 11 {
+		t.Errorf("schedules of user 1 are not sorted by next taking: %+v", people[1].Schedules)
+	}
+	if next, ok := people[1].Next(); !ok || next.Hour() != 9 {
+		t.Errorf("Next() = %v, %v, want 09:00", next, ok)
+	}
+	if _, ok := people[3].Next(); ok {
+		t.Error("person without takings must have no next taking")
+	}
+}
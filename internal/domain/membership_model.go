@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidRole   = errors.New("role must be patient, nurse, doctor or admin")
+	ErrOwnMembership = errors.New("admins cannot change their own membership")
+)
+
+// Role — роль пользователя в клинике.
+type Role string
+
+const (
+	// RoleNone — пользователь вне клиники: ведёт свои расписания сам и
+	// может всё, кроме управления клиникой.
+	RoleNone    Role = ""
+	RolePatient Role = "patient"
+	RoleNurse   Role = "nurse"
+	RoleDoctor  Role = "doctor"
+	RoleAdmin   Role = "admin"
+	// RoleOwner — создатель собственной организации: ведёт свои расписания,
+	// как пользователь вне клиники, и вдобавок управляет участниками. Эта
+	// роль выдаётся только при регистрации, назначить её нельзя.
+	RoleOwner Role = "owner"
+)
+
+// Permission — вид действий, который разрешает роль.
+type Permission string
+
+const (
+	// PermView — просмотр расписаний, приёмов, отметок, статистики и
+	// настроек.
+	PermView Permission = "view"
+	// PermRecordDoses — отметки о приёме.
+	PermRecordDoses Permission = "record_doses"
+	// PermPrescribe — создание и изменение расписаний и настроек.
+	PermPrescribe Permission = "prescribe"
+	// PermShare — выдача доступа доверенным лицам.
+	PermShare Permission = "share"
+	// PermManageMembers — назначение ролей в клинике.
+	PermManageMembers Permission = "manage_members"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleNone:    {PermView, PermRecordDoses, PermPrescribe, PermShare},
+	RolePatient: {PermView, PermShare},
+	RoleNurse:   {PermView, PermRecordDoses},
+	RoleDoctor:  {PermView, PermPrescribe},
+	RoleAdmin:   {PermView, PermManageMembers},
+	RoleOwner:   {PermView, PermRecordDoses, PermPrescribe, PermShare, PermManageMembers},
+}
+
+// Validate проверяет роль, которую можно назначить; RoleNone назначить
+// нельзя — для этого членство удаляется, RoleOwner — тоже.
+func (r Role) Validate() error {
+	if r == RoleNone || r == RoleOwner {
+		return ErrInvalidRole
+	}
+	if _, ok := rolePermissions[r]; !ok {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+// GrantAccess возвращает права доступа доверенного лица, достаточные для
+// действия perm с данными пациента: просмотру хватает read, остальному
+// нужен manage.
+func (p Permission) GrantAccess() AccessLevel {
+	if p == PermView {
+		return AccessRead
+	}
+	return AccessManage
+}
+
+func (r Role) Can(perm Permission) bool {
+	return slices.Contains(rolePermissions[r], perm)
+}
+
+// Staff сообщает, работает ли пользователь с данными пациентов клиники.
+func (r Role) Staff() bool {
+	return r == RoleNurse || r == RoleDoctor || r == RoleAdmin
+}
+
+// Membership — роль учётной записи в клинике.
+type Membership struct {
+	UserID    int
+	Email     string
+	Role      Role
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"errors"
+	"medication-scheduler/internal/domain"
+	"testing"
+)
+
+func TestRoleCan(t *testing.T) {
+	tests := []struct {
+		role  domain.Role
+		perm  domain.Permission
+		allow bool
+	}{
+		{domain.RoleDoctor, domain.PermPrescribe, true},
+		{domain.RoleDoctor, domain.PermRecordDoses, false},
+		{domain.RoleNurse, domain.PermRecordDoses, true},
+		{domain.RoleNurse, domain.PermPrescribe, false},
+		{domain.RolePatient, domain.PermView, true},
+		{domain.RolePatient, domain.PermRecordDoses, false},
+		{domain.RolePatient, domain.PermPrescribe, false},
+		{domain.RoleAdmin, domain.PermManageMembers, true},
+		{domain.RoleAdmin, domain.PermPrescribe, false},
+		{domain.RoleNone, domain.PermPrescribe, true},
+		{domain.RoleNone, domain.PermManageMembers, false},
+		{domain.RoleOwner, domain.PermPrescribe, true},
+		{domain.RoleOwner, domain.PermRecordDoses, true},
+		{domain.RoleOwner, domain.PermShare, true},
+		{domain.RoleOwner, domain.PermManageMembers, true},
+		{domain.Role("superuser"), domain.PermView, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Can(tt.perm); got != tt.allow {
+			t.Errorf("Role(%q).Can(%q) = %v, want %v", tt.role, tt.perm, got, tt.allow)
+		}
+	}
+}
+
+func TestRoleValidate(t *testing.T) {
+	for _, role := range []domain.Role{domain.RolePatient, domain.RoleNurse, domain.RoleDoctor, domain.RoleAdmin} {
+		if err := role.Validate(); err != nil {
+			t.Errorf("Role(%q).Validate() = %v", role, err)
+		}
+	}
+	for _, role := range []domain.Role{domain.RoleNone, domain.RoleOwner, "superuser"} {
+		if err := role.Validate(); !errors.Is(err, domain.ErrInvalidRole) {
+			t.Errorf("Role(%q).Validate() = %v, want ErrInvalidRole", role, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// IsMultiDay сообщает, что интервал между приёмами больше суток (36h,
+// 48h...). Такие приёмы отсчитываются не от начала каждого окна, а от
+// StartTime, и выпадают не каждый день.
+func (s *Schedule) IsMultiDay() bool {
+	return (s.Kind == "" || s.Kind == KindInterval) && s.Frequency > 24*time.Hour
+}
+
+// multiDayTakings возвращает приём многодневного интервала, относящийся к
+// окну [dayStart, dayEnd]. Окну принадлежат приёмы после конца предыдущего
+// окна: приём, выпавший на время вне окна (ночью), переносится на начало
+// окна, но следующие приёмы по-прежнему отсчитываются от StartTime.
+func (s *Schedule) multiDayTakings(dayStart, dayEnd time.Time) []time.Time {
+	rounding := s.RoundingPolicy()
+	prevEnd := dayEnd.AddDate(0, 0, -1)
+
+	first := 0
+	if elapsed := prevEnd.Sub(s.StartTime); elapsed > 0 {
+		// Отступаем на шаг назад: округление может сдвинуть приём через границу.
+		first = int(elapsed/s.Frequency) - 1
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	var takings []time.Time
+	for k := first; ; k++ {
+		planned := s.StartTime.Add(time.Duration(k) * s.Frequency).In(dayStart.Location())
+		taking := rounding.Apply(planned)
+		if taking.After(dayEnd) {
+			break
+		}
+		if !taking.After(prevEnd) {
+			continue
+		}
+		if taking.Before(dayStart) {
+			taking = dayStart
+		}
+		if len(takings) == 0 || taking.After(takings[len(takings)-1]) {
+			takings = append(takings, taking)
+		}
+	}
+	return takings
+}
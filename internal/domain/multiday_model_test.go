@@ -0,0 +1,110 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestPlannedTakings_MultiDay(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		frequency time.Duration
+		expected  []time.Time
+	}{
+		{"Every other day", 48 * time.Hour, []time.Time{at(1, 9), at(3, 9), at(5, 9)}},
+		// 09:00 → 21:00 → 09:00 (через 36 часов) → 21:00...
+		{"Every 36 hours", 36 * time.Hour, []time.Time{at(1, 9), at(2, 21), at(4, 9), at(5, 21)}},
+		// 09:00 → 03:00 переносится на начало окна 08:00, следующий приём — от 09:00
+		{"Night dose moved to the window start", 42 * time.Hour, []time.Time{at(1, 9), at(3, 8), at(4, 21)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := domain.Schedule{
+				Kind:      domain.KindInterval,
+				Frequency: tt.frequency,
+				Timezone:  "UTC",
+				StartTime: start,
+				EndTime:   domain.PerpetualEndTime,
+			}
+
+			takings := s.PlannedTakings(start, at(6, 0))
+			if len(takings) != len(tt.expected) {
+				t.Fatalf("Expected %d takings, got %d: %v", len(tt.expected), len(takings), takings)
+			}
+			for i, taking := range takings {
+				if !taking.Equal(tt.expected[i]) {
+					t.Errorf("Taking %d: expected %v, got %v", i, tt.expected[i], taking)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateTakings_MultiDayRestDay(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:      domain.KindInterval,
+		Frequency: 48 * time.Hour,
+		Timezone:  "UTC",
+		StartTime: start,
+		EndTime:   domain.PerpetualEndTime,
+	}
+
+	if takings := s.CalculateTakings(start.Add(24 * time.Hour)); len(takings) != 0 {
+		t.Errorf("Expected no takings on the rest day, got %v", takings)
+	}
+	if takings := s.CalculateTakings(start.Add(48 * time.Hour)); len(takings) != 1 {
+		t.Errorf("Expected one taking on the dosing day, got %v", takings)
+	}
+}
+
+func TestFindNextTaking_Tomorrow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule domain.Schedule
+		expected time.Time
+	}{
+		{
+			name:     "Interval",
+			schedule: domain.Schedule{Kind: domain.KindInterval, Frequency: 4 * time.Hour},
+			expected: tomorrow,
+		},
+		{
+			name:     "Fixed times",
+			schedule: domain.Schedule{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{9 * time.Hour}},
+			expected: tomorrow.Add(time.Hour),
+		},
+		{
+			name:     "Multi-day",
+			schedule: domain.Schedule{Kind: domain.KindInterval, Frequency: 36 * time.Hour},
+			// Старт 31 декабря в 20:00: следующий приём 2 января в 08:00.
+			expected: tomorrow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.schedule
+			s.Timezone = "UTC"
+			s.StartTime = time.Date(2024, 12, 31, 20, 0, 0, 0, time.UTC)
+			s.EndTime = domain.PerpetualEndTime
+
+			next, ok := s.FindNextTaking(now, now.Add(12*time.Hour))
+			if !ok || !next.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v (found %v)", tt.expected, next, ok)
+			}
+			if _, ok := s.FindNextTaking(now, now.Add(time.Hour)); ok {
+				t.Error("Expected no taking within an hour")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"container/heap"
+	"time"
+)
+
+// occurrenceChunk — на сколько вперёд итератор считает приёмы одного
+// расписания за раз.
+const occurrenceChunk = 24 * time.Hour
+
+// Occurrence — запланированный приём конкретного расписания.
+type Occurrence struct {
+	ScheduleID int
+	Medication string
+	At         time.Time
+	Dosage     Dosage
+}
+
+// OccurrenceIterator перебирает запланированные приёмы нескольких
+// расписаний в [from, to) в хронологическом порядке. Приёмы считаются
+// лениво, по суткам на расписание, поэтому длинный интервал не требует
+// держать в памяти все приёмы сразу.
+type OccurrenceIterator struct {
+	cursors occurrenceHeap
+}
+
+func NewOccurrenceIterator(schedules []Schedule, from, to time.Time) *OccurrenceIterator {
+	it := &OccurrenceIterator{}
+	for i := range schedules {
+		cursor := &occurrenceCursor{schedule: &schedules[i], next: from, to: to}
+		if cursor.fill() {
+			it.cursors = append(it.cursors, cursor)
+		}
+	}
+	heap.Init(&it.cursors)
+	return it
+}
+
+// Next возвращает следующий приём; false — приёмы закончились. Приёмы в
+// одно время упорядочены по ScheduleID.
+func (it *OccurrenceIterator) Next() (Occurrence, bool) {
+	if len(it.cursors) == 0 {
+		return Occurrence{}, false
+	}
+
+	cursor := it.cursors[0]
+	at := cursor.pending[0]
+	occurrence := Occurrence{
+		ScheduleID: cursor.schedule.ID,
+		Medication: cursor.schedule.Medication,
+		At:         at,
+		Dosage:     cursor.schedule.DosageAt(at),
+	}
+
+	cursor.pending = cursor.pending[1:]
+	if cursor.fill() {
+		heap.Fix(&it.cursors, 0)
+	} else {
+		heap.Pop(&it.cursors)
+	}
+	return occurrence, true
+}
+
+// Occurrences собирает все приёмы расписаний в [from, to).
+func Occurrences(schedules []Schedule, from, to time.Time) []Occurrence {
+	var result []Occurrence
+	it := NewOccurrenceIterator(schedules, from, to)
+	for occurrence, ok := it.Next(); ok; occurrence, ok = it.Next() {
+		result = append(result, occurrence)
+	}
+	return result
+}
+
+// occurrenceCursor — приёмы одного расписания: уже посчитанные pending и
+// начало ещё не просмотренного интервала next.
+type occurrenceCursor struct {
+	schedule *Schedule
+	pending  []time.Time
+	next     time.Time
+	to       time.Time
+}
+
+// fill досчитывает приёмы, пока в pending нет хотя бы одного или интервал
+// не исчерпан; false — у расписания больше нет приёмов.
+func (c *occurrenceCursor) fill() bool {
+	for len(c.pending) == 0 && c.next.Before(c.to) {
+		end := earliest(c.next.Add(occurrenceChunk), c.to)
+		c.pending = c.schedule.PlannedTakings(c.next, end)
+		c.next = end
+	}
+	return len(c.pending) > 0
+}
+
+type occurrenceHeap []*occurrenceCursor
+
+func (h occurrenceHeap) Len() int { return len(h) }
+
+func (h occurrenceHeap) Less(i, j int) bool {
+	a, b := h[i].pending[0], h[j].pending[0]
+	if a.Equal(b) {
+		return h[i].schedule.ID < h[j].schedule.ID
+	}
+	return a.Before(b)
+}
+
+func (h occurrenceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *occurrenceHeap) Push(x interface{}) { *h = append(*h, x.(*occurrenceCursor)) }
+
+func (h *occurrenceHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,108 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestOccurrences(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	schedules := []domain.Schedule{
+		{
+			ID:         2,
+			Medication: "Vitamin D",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+			Timezone:   "UTC",
+			StartTime:  start,
+			EndTime:    domain.PerpetualEndTime,
+		},
+		{
+			ID:         1,
+			Medication: "Amoxicillin",
+			Dosage:     domain.Dosage{Amount: 500, Unit: domain.UnitMg},
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+			Duration:   48 * time.Hour,
+			Timezone:   "UTC",
+			StartTime:  start,
+			EndTime:    start.Add(48 * time.Hour),
+		},
+	}
+
+	occurrences := domain.Occurrences(schedules, at(1, 12), at(4, 0))
+
+	expected := []struct {
+		scheduleID int
+		at         time.Time
+	}{
+		{1, at(1, 21)},
+		{1, at(2, 9)},
+		{2, at(2, 9)},
+		{1, at(2, 21)},
+		{2, at(3, 9)},
+	}
+	if len(occurrences) != len(expected) {
+		t.Fatalf("Expected %d occurrences, got %d: %+v", len(expected), len(occurrences), occurrences)
+	}
+	for i, occurrence := range occurrences {
+		if occurrence.ScheduleID != expected[i].scheduleID || !occurrence.At.Equal(expected[i].at) {
+			t.Errorf("Occurrence %d: expected schedule %d at %v, got schedule %d at %v",
+				i, expected[i].scheduleID, expected[i].at, occurrence.ScheduleID, occurrence.At)
+		}
+	}
+	if occurrences[0].Medication != "Amoxicillin" || occurrences[0].Dosage.Amount != 500 {
+		t.Errorf("Expected medication and dose of the schedule, got %+v", occurrences[0])
+	}
+}
+
+func TestOccurrenceIterator_Lazy(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{{
+		ID:        1,
+		Kind:      domain.KindInterval,
+		Frequency: 4 * time.Hour,
+		Timezone:  "UTC",
+		StartTime: start,
+		EndTime:   domain.PerpetualEndTime,
+	}}
+
+	// Год приёмов: итератор отдаёт первые, не считая остальные.
+	it := domain.NewOccurrenceIterator(schedules, start, start.AddDate(1, 0, 0))
+	first, ok := it.Next()
+	if !ok || !first.At.Equal(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected first taking at 08:00, got %v (ok %v)", first.At, ok)
+	}
+
+	previous := first.At
+	for i := 0; i < 20; i++ {
+		next, ok := it.Next()
+		if !ok {
+			t.Fatal("Iterator ended early")
+		}
+		if !next.At.After(previous) {
+			t.Fatalf("Occurrences out of order: %v after %v", next.At, previous)
+		}
+		previous = next.At
+	}
+}
+
+func TestOccurrences_Empty(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{{
+		ID:          1,
+		Kind:        domain.KindAsNeeded,
+		MinInterval: 6 * time.Hour,
+		Timezone:    "UTC",
+		StartTime:   from,
+		EndTime:     domain.PerpetualEndTime,
+	}}
+
+	if occurrences := domain.Occurrences(schedules, from, from.Add(72*time.Hour)); len(occurrences) != 0 {
+		t.Errorf("Expected no occurrences for an as-needed schedule, got %v", occurrences)
+	}
+}
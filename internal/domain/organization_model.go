@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// InviteTTL — срок действия приглашения в организацию.
+const InviteTTL = 7 * 24 * time.Hour
+
+// PersonalOrganizationName — название организации, которую пользователь
+// создаёт при регистрации без приглашения. Его видят участники, поэтому
+// адрес пользователя в название не попадает.
+const PersonalOrganizationName = "Personal"
+
+// Organization — клиника, обслуживаемая развёртыванием. Пользователи и
+// расписания принадлежат ровно одной организации и не видны другим.
+type Organization struct {
+	ID        int
+	Slug      string
+	Name      string
+	CreatedAt time.Time
+}
+
+// Invite — одноразовое приглашение зарегистрироваться в организации,
+// выданное её администратором. Сам токен приглашения не хранится.
+type Invite struct {
+	ID             int
+	OrganizationID int
+	CreatedBy      int
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+// PerpetualEndTime — дата окончания бессрочного расписания.
+var PerpetualEndTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// SchedulePatch — частичное изменение расписания: nil-поля не меняются.
+type SchedulePatch struct {
+	Medication *string
+	Dosage     *Dosage
+	// Regimen заменяет вид расписания вместе с Frequency и TimesOfDay.
+	Regimen  *Regimen
+	Rounding *Rounding
+	Duration *time.Duration
+	Timezone *string
+	// Window с нулевым значением возвращает наследование окна из настроек
+	// пользователя.
+	Window   *DayWindow
+	Weekdays *Weekdays
+	Cycle    *Cycle
+	// Phases заменяет этапы целиком; пустой список отменяет этапы.
+	Phases *[]Phase
+}
+
+type Regimen struct {
+	Kind        ScheduleKind
+	Frequency   time.Duration
+	TimesOfDay  []time.Duration
+	Anchors     []Anchor
+	MinInterval time.Duration
+	MaxPerDay   int
+}
+
+// Apply применяет изменение. Срок действия отсчитывается от исходного
+// StartTime; результат нужно проверить через Validate.
+func (s *Schedule) Apply(patch SchedulePatch) {
+	if patch.Medication != nil {
+		s.Medication = *patch.Medication
+	}
+	if patch.Dosage != nil {
+		s.Dosage = *patch.Dosage
+	}
+	if patch.Regimen != nil {
+		s.Kind = patch.Regimen.Kind
+		s.Frequency = patch.Regimen.Frequency
+		s.TimesOfDay = patch.Regimen.TimesOfDay
+		s.Anchors = patch.Regimen.Anchors
+		s.MinInterval = patch.Regimen.MinInterval
+		s.MaxPerDay = patch.Regimen.MaxPerDay
+	}
+	if patch.Rounding != nil {
+		s.Rounding = *patch.Rounding
+	}
+	if patch.Duration != nil {
+		s.Duration = *patch.Duration
+		s.SetEndTime()
+	}
+	if patch.Timezone != nil {
+		s.Timezone = *patch.Timezone
+	}
+	if patch.Window != nil {
+		s.Window = *patch.Window
+		s.InheritsWindow = patch.Window.IsZero()
+	}
+	if patch.Weekdays != nil {
+		s.Weekdays = *patch.Weekdays
+	}
+	if patch.Cycle != nil {
+		s.Cycle = *patch.Cycle
+	}
+	if patch.Phases != nil {
+		s.Phases = *patch.Phases
+	}
+}
+
+// SetEndTime пересчитывает EndTime по StartTime и Duration.
+func (s *Schedule) SetEndTime() {
+	if s.Duration > 0 {
+		s.EndTime = s.StartTime.Add(s.Duration)
+	} else {
+		// Для бессрочного приема
+		s.EndTime = PerpetualEndTime
+	}
+}
@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestScheduleApply(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := domain.Schedule{
+		Medication: "Aspirin",
+		Kind:       domain.KindInterval,
+		Frequency:  time.Hour,
+		Timezone:   "UTC",
+		Window:     domain.DayWindow{Start: 9 * time.Hour, End: 21 * time.Hour},
+		StartTime:  start,
+		EndTime:    domain.PerpetualEndTime,
+	}
+
+	t.Run("empty patch changes nothing", func(t *testing.T) {
+		s := base
+		s.Apply(domain.SchedulePatch{})
+		if !reflect.DeepEqual(base, s) {
+			t.Errorf("Expected %+v, got %+v", base, s)
+		}
+	})
+
+	t.Run("duration is counted from start", func(t *testing.T) {
+		s := base
+		duration := 72 * time.Hour
+		s.Apply(domain.SchedulePatch{Duration: &duration})
+		if !s.EndTime.Equal(start.Add(duration)) {
+			t.Errorf("Expected end %v, got %v", start.Add(duration), s.EndTime)
+		}
+	})
+
+	t.Run("regimen replaces kind", func(t *testing.T) {
+		s := base
+		s.Apply(domain.SchedulePatch{Regimen: &domain.Regimen{
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{10 * time.Hour},
+		}})
+		if s.Kind != domain.KindFixedTimes || s.Frequency != 0 || !slices.Equal(s.TimesOfDay, []time.Duration{10 * time.Hour}) {
+			t.Errorf("Unexpected regimen: kind=%s frequency=%v times=%v", s.Kind, s.Frequency, s.TimesOfDay)
+		}
+	})
+
+	t.Run("zero window restores inheritance", func(t *testing.T) {
+		s := base
+		s.Apply(domain.SchedulePatch{Window: &domain.DayWindow{}})
+		if !s.Window.IsZero() || !s.InheritsWindow {
+			t.Errorf("Expected inherited window, got %+v (inherits=%v)", s.Window, s.InheritsWindow)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWeekdays = errors.New("weekdays must be short English day names (mon..sun)")
+	ErrInvalidCycle    = errors.New("cycle requires at least one day on and one day off")
+)
+
+// MaxCycleDays ограничивает длину цикла приёма разумным курсом.
+const MaxCycleDays = 365
+
+// Weekdays — маска дней недели: бит i соответствует time.Weekday(i).
+// Пустая маска означает приём каждый день.
+type Weekdays uint8
+
+const AllWeekdays Weekdays = 1<<7 - 1
+
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << d
+	}
+	return w
+}
+
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w == 0 || w&(1<<d) != 0
+}
+
+// ParseWeekdays разбирает названия дней ("mon", "wed", "fri") в маску.
+func ParseWeekdays(names []string) (Weekdays, error) {
+	var w Weekdays
+	for _, name := range names {
+		day := -1
+		for i, known := range weekdayNames {
+			if strings.EqualFold(name, known) {
+				day = i
+				break
+			}
+		}
+		if day < 0 {
+			return 0, ErrInvalidWeekdays
+		}
+		w |= 1 << day
+	}
+	return w, nil
+}
+
+func (w Weekdays) Names() []string {
+	var names []string
+	for i, name := range weekdayNames {
+		if w&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Cycle — чередование дней приёма и перерыва (например, 21 день приёма и
+// 7 дней перерыва или «через день» как 1/1). Отсчёт ведётся от дня начала
+// расписания.
+type Cycle struct {
+	OnDays  int
+	OffDays int
+}
+
+func (c Cycle) IsZero() bool {
+	return c.OnDays == 0 && c.OffDays == 0
+}
+
+func (c Cycle) Validate() error {
+	if c.OnDays < 1 || c.OffDays < 1 || c.OnDays+c.OffDays > MaxCycleDays {
+		return ErrInvalidCycle
+	}
+	return nil
+}
+
+// IsOnDay сообщает, приходится ли день на фазу приёма, если цикл начался
+// в день start. Дни считаются по календарю, поэтому переход на летнее время
+// не сдвигает цикл.
+func (c Cycle) IsOnDay(start, day time.Time) bool {
+	if c.IsZero() {
+		return true
+	}
+
+	days := calendarDays(start, day)
+	period := c.OnDays + c.OffDays
+	return (days%period+period)%period < c.OnDays
+}
+
+func calendarDays(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a) / (24 * time.Hour))
+}
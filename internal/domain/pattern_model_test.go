@@ -0,0 +1,108 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	w, err := domain.ParseWeekdays([]string{"mon", "WED", "fri"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != domain.NewWeekdays(time.Monday, time.Wednesday, time.Friday) {
+		t.Errorf("Unexpected mask %07b", w)
+	}
+	if names := w.Names(); len(names) != 3 || names[0] != "mon" || names[2] != "fri" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	if _, err := domain.ParseWeekdays([]string{"monday"}); err != domain.ErrInvalidWeekdays {
+		t.Errorf("Expected ErrInvalidWeekdays, got %v", err)
+	}
+}
+
+func TestCycleIsOnDay(t *testing.T) {
+	start := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	cycle := domain.Cycle{OnDays: 21, OffDays: 7}
+
+	tests := []struct {
+		offset int
+		on     bool
+	}{
+		{0, true},
+		{20, true},
+		{21, false},
+		{27, false},
+		{28, true},
+		{-1, false},
+	}
+
+	for _, tt := range tests {
+		day := start.AddDate(0, 0, tt.offset)
+		if got := cycle.IsOnDay(start, day); got != tt.on {
+			t.Errorf("Day %d: expected on=%v, got %v", tt.offset, tt.on, got)
+		}
+	}
+}
+
+func TestCalculateTakings_DosingPatterns(t *testing.T) {
+	start := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC) // понедельник
+
+	tests := []struct {
+		name     string
+		schedule domain.Schedule
+		day      time.Time
+		expected bool
+	}{
+		{
+			"Weekday on",
+			domain.Schedule{Weekdays: domain.NewWeekdays(time.Monday, time.Wednesday, time.Friday)},
+			time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"Weekday off",
+			domain.Schedule{Weekdays: domain.NewWeekdays(time.Monday, time.Wednesday, time.Friday)},
+			time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC),
+			false,
+		},
+		{
+			"Every other day on",
+			domain.Schedule{Cycle: domain.Cycle{OnDays: 1, OffDays: 1}},
+			time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"Every other day off",
+			domain.Schedule{Cycle: domain.Cycle{OnDays: 1, OffDays: 1}},
+			time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC),
+			false,
+		},
+		{
+			"Weekday in local time zone",
+			domain.Schedule{Weekdays: domain.NewWeekdays(time.Tuesday), Timezone: "Asia/Tokyo"},
+			time.Date(2025, 1, 6, 23, 30, 0, 0, time.UTC), // вторник 08:30 в Токио
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.schedule.Frequency = 4 * time.Hour
+			tt.schedule.StartTime = start
+
+			takings := tt.schedule.CalculateTakings(tt.day)
+			if (len(takings) > 0) != tt.expected {
+				t.Errorf("Expected takings=%v, got %v", tt.expected, takings)
+			}
+
+			_, found := tt.schedule.FindNextTaking(tt.day, tt.day.Add(6*time.Hour))
+			if found != tt.expected {
+				t.Errorf("Expected next taking found=%v, got %v", tt.expected, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidPhase = errors.New("phase requires a positive duration (only the last phase may be open-ended) and must fit the schedule")
+	ErrPhaseOverlap = errors.New("phases must not overlap")
+	ErrPhaseGap     = errors.New("phases must follow each other without gaps from the schedule start")
+)
+
+// Phase — этап ступенчатого расписания (снижение или наращивание дозы):
+// в [Offset, Offset+Duration) от полуночи дня начала расписания приёмы идут
+// по своему режиму и со своей дозой (см. PhaseBounds). Нулевой Regimen и
+// нулевая Dosage наследуются из расписания; нулевой Duration у последнего
+// этапа — этап длится до конца расписания.
+type Phase struct {
+	Offset   time.Duration
+	Duration time.Duration
+	Dosage   Dosage
+	Regimen  Regimen
+}
+
+// PhasesEnd возвращает смещение конца последнего этапа или 0, если
+// последний этап бессрочный.
+func PhasesEnd(phases []Phase) time.Duration {
+	if len(phases) == 0 {
+		return 0
+	}
+	last := phases[len(phases)-1]
+	if last.Duration == 0 {
+		return 0
+	}
+	return last.Offset + last.Duration
+}
+
+// validatePhases проверяет, что этапы идут встык от начала расписания и
+// покрывают весь срок его действия, а режим и доза каждого этапа корректны.
+func (s *Schedule) validatePhases() error {
+	var end time.Duration
+	for i, phase := range s.Phases {
+		last := i == len(s.Phases)-1
+		if phase.Duration < 0 || (phase.Duration == 0 && !last) || phase.Regimen.Kind == KindAsNeeded {
+			return ErrInvalidPhase
+		}
+		switch {
+		case phase.Offset < end:
+			return ErrPhaseOverlap
+		case phase.Offset > end:
+			return ErrPhaseGap
+		}
+		end = phase.Offset + phase.Duration
+
+		if err := s.withPhase(phase).validateRegimen(); err != nil {
+			return err
+		}
+		if !phase.Dosage.IsZero() {
+			if err := phase.Dosage.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	last := s.Phases[len(s.Phases)-1]
+	if last.Duration == 0 {
+		if s.Duration != 0 && s.Duration <= last.Offset {
+			return ErrInvalidPhase
+		}
+		return nil
+	}
+	switch {
+	case s.Duration == 0 || s.Duration > end:
+		return ErrPhaseGap
+	case s.Duration < end:
+		return ErrInvalidPhase
+	}
+	return nil
+}
+
+// PhaseBounds возвращает границы i-го этапа; нулевой to — этап длится до
+// конца расписания. Этапы сменяются в полночь по часовому поясу
+// расписания, а не в час его начала, чтобы доза не менялась посреди дня.
+// Последний этап заканчивается вместе с расписанием, даже если оно
+// закончится не в полночь.
+func (s *Schedule) PhaseBounds(i int) (from, to time.Time) {
+	phase := s.Phases[i]
+	from = s.phaseStart(phase.Offset)
+	if phase.Duration > 0 && i < len(s.Phases)-1 {
+		to = s.phaseStart(phase.Offset + phase.Duration)
+	}
+	return from, to
+}
+
+// phaseStart отсчитывает offset от полуночи дня начала расписания. Целые
+// сутки прибавляются календарными днями, чтобы переход на летнее время не
+// сдвигал границу с полуночи.
+func (s *Schedule) phaseStart(offset time.Duration) time.Time {
+	const day = 24 * time.Hour
+	midnight := AtClock(s.StartTime.In(s.Location()), 0)
+	return midnight.AddDate(0, 0, int(offset/day)).Add(offset % day)
+}
+
+// PhaseAt возвращает этап, действующий в момент t.
+func (s *Schedule) PhaseAt(t time.Time) (Phase, bool) {
+	for i, phase := range s.Phases {
+		from, to := s.PhaseBounds(i)
+		if !t.Before(from) && (to.IsZero() || t.Before(to)) {
+			return phase, true
+		}
+	}
+	return Phase{}, false
+}
+
+// withPhase возвращает копию расписания с режимом и дозой этапа.
+func (s *Schedule) withPhase(phase Phase) *Schedule {
+	phased := *s
+	phased.Phases = nil
+	phased.Revisions = nil
+	if phase.Regimen.Kind != "" {
+		phased.Kind = phase.Regimen.Kind
+		phased.Frequency = phase.Regimen.Frequency
+		phased.TimesOfDay = phase.Regimen.TimesOfDay
+	}
+	if !phase.Dosage.IsZero() {
+		phased.Dosage = phase.Dosage
+	}
+	return &phased
+}
+
+// phaseTakings раскладывает приёмы окна [dayStart, dayEnd] по этапам: каждый
+// этап даёт приёмы по своему режиму только в своих границах.
+func (s *Schedule) phaseTakings(dayStart, dayEnd time.Time) []time.Time {
+	var takings []time.Time
+	for i, phase := range s.Phases {
+		from, to := s.PhaseBounds(i)
+		if from.After(dayEnd) {
+			break
+		}
+		if !to.IsZero() && !to.After(dayStart) {
+			continue
+		}
+		for _, taking := range s.withPhase(phase).takingsBetween(dayStart, dayEnd) {
+			if !taking.Before(from) && (to.IsZero() || taking.Before(to)) {
+				takings = append(takings, taking)
+			}
+		}
+	}
+	return takings
+}
@@ -0,0 +1,169 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func taperSchedule() domain.Schedule {
+	s := domain.Schedule{
+		Medication: "Prednisolone",
+		Kind:       domain.KindInterval,
+		Frequency:  12 * time.Hour,
+		Timezone:   "UTC",
+		Duration:   96 * time.Hour,
+		StartTime:  time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+		Phases: []domain.Phase{
+			{
+				Duration: 48 * time.Hour,
+				Dosage:   domain.Dosage{Amount: 40, Unit: domain.UnitMg},
+			},
+			{
+				Offset:   48 * time.Hour,
+				Duration: 48 * time.Hour,
+				Dosage:   domain.Dosage{Amount: 20, Unit: domain.UnitMg},
+				Regimen:  domain.Regimen{Kind: domain.KindInterval, Frequency: 24 * time.Hour},
+			},
+		},
+	}
+	s.SetEndTime()
+	return s
+}
+
+func TestPhases_Takings(t *testing.T) {
+	s := taperSchedule()
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected valid taper, got %v", err)
+	}
+
+	takings := s.PlannedTakings(s.StartTime, s.EndTime.Add(24*time.Hour))
+	expected := []time.Time{
+		time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 3, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 4, 8, 0, 0, 0, time.UTC),
+	}
+	if len(takings) != len(expected) {
+		t.Fatalf("Expected %d takings, got %d: %v", len(expected), len(takings), takings)
+	}
+	for i := range expected {
+		if !takings[i].Equal(expected[i]) {
+			t.Errorf("Taking %d: expected %v, got %v", i, expected[i], takings[i])
+		}
+	}
+
+	if got := s.DosageAt(expected[3]); got.Amount != 40 {
+		t.Errorf("Expected 40 mg in the first phase, got %v", got)
+	}
+	if got := s.DosageAt(expected[4]); got.Amount != 20 {
+		t.Errorf("Expected 20 mg in the second phase, got %v", got)
+	}
+
+	now := time.Date(2025, 1, 2, 21, 0, 0, 0, time.UTC)
+	if next, found := s.FindNextTaking(now, now.Add(12*time.Hour)); !found || !next.Equal(expected[4]) {
+		t.Errorf("Expected next taking %v, got %v (found=%v)", expected[4], next, found)
+	}
+
+	if got := s.CalculateTakings(expected[4]); len(got) != 1 {
+		t.Errorf("Expected one taking per day in the second phase, got %v", got)
+	}
+}
+
+func TestPhases_OpenEnded(t *testing.T) {
+	s := taperSchedule()
+	s.Duration = 0
+	s.Phases[1].Duration = 0
+	s.SetEndTime()
+
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected open-ended last phase to be valid, got %v", err)
+	}
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	if takings := s.TakingsOn(day); len(takings) != 1 || s.DosageAt(takings[0]).Amount != 20 {
+		t.Errorf("Expected maintenance dose once a day, got %v", takings)
+	}
+}
+
+func TestPhases_SwitchAtLocalMidnight(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	twice := domain.Regimen{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{8 * time.Hour, 20 * time.Hour}}
+	once := domain.Regimen{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{8 * time.Hour}}
+	s := domain.Schedule{
+		Medication: "Prednisolone",
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: twice.TimesOfDay,
+		Timezone:   "Europe/Moscow",
+		Duration:   96 * time.Hour,
+		// Начало посреди дня: этапы всё равно сменяются в полночь.
+		StartTime: time.Date(2025, 1, 1, 14, 0, 0, 0, moscow),
+		Phases: []domain.Phase{
+			{Duration: 48 * time.Hour, Dosage: domain.Dosage{Amount: 40, Unit: domain.UnitMg}, Regimen: twice},
+			{Offset: 48 * time.Hour, Duration: 48 * time.Hour, Dosage: domain.Dosage{Amount: 20, Unit: domain.UnitMg}, Regimen: once},
+		},
+	}
+	s.SetEndTime()
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected valid taper, got %v", err)
+	}
+
+	expected := []struct {
+		at     time.Time
+		amount float64
+	}{
+		{time.Date(2025, 1, 1, 20, 0, 0, 0, moscow), 40},
+		{time.Date(2025, 1, 2, 8, 0, 0, 0, moscow), 40},
+		{time.Date(2025, 1, 2, 20, 0, 0, 0, moscow), 40},
+		{time.Date(2025, 1, 3, 8, 0, 0, 0, moscow), 20},
+		{time.Date(2025, 1, 4, 8, 0, 0, 0, moscow), 20},
+		// Последний этап длится до конца расписания в 14:00, а не до полуночи.
+		{time.Date(2025, 1, 5, 8, 0, 0, 0, moscow), 20},
+	}
+	takings := s.PlannedTakings(s.StartTime, s.EndTime.Add(24*time.Hour))
+	if len(takings) != len(expected) {
+		t.Fatalf("Expected %d takings, got %d: %v", len(expected), len(takings), takings)
+	}
+	for i, want := range expected {
+		if !takings[i].Equal(want.at) {
+			t.Errorf("Taking %d: expected %v, got %v", i, want.at, takings[i])
+		}
+		if got := s.DosageAt(takings[i]); got.Amount != want.amount {
+			t.Errorf("Taking %d: expected %v mg, got %v", i, want.amount, got)
+		}
+	}
+}
+
+func TestPhases_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(s *domain.Schedule)
+		expected error
+	}{
+		{"Gap between phases", func(s *domain.Schedule) { s.Phases[1].Offset = 72 * time.Hour; s.Duration = 120 * time.Hour }, domain.ErrPhaseGap},
+		{"Overlapping phases", func(s *domain.Schedule) { s.Phases[1].Offset = 24 * time.Hour; s.Duration = 72 * time.Hour }, domain.ErrPhaseOverlap},
+		{"First phase starts late", func(s *domain.Schedule) { s.Phases[0].Offset = time.Hour }, domain.ErrPhaseGap},
+		{"Open-ended middle phase", func(s *domain.Schedule) { s.Phases[0].Duration = 0 }, domain.ErrInvalidPhase},
+		{"Schedule outlives phases", func(s *domain.Schedule) { s.Duration = 120 * time.Hour }, domain.ErrPhaseGap},
+		{"Perpetual schedule with closed phases", func(s *domain.Schedule) { s.Duration = 0 }, domain.ErrPhaseGap},
+		{"Phases outlive schedule", func(s *domain.Schedule) { s.Duration = 72 * time.Hour }, domain.ErrInvalidPhase},
+		{"Invalid phase frequency", func(s *domain.Schedule) { s.Phases[1].Regimen.Frequency = time.Minute }, domain.ErrInvalidFrequency},
+		{"Invalid phase dose", func(s *domain.Schedule) { s.Phases[0].Dosage.Unit = "spoon" }, domain.ErrInvalidDosage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := taperSchedule()
+			tt.modify(&s)
+			if err := s.Validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
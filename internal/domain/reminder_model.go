@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReminderHorizon — насколько вперёд напоминания ставятся в очередь.
+const ReminderHorizon = 24 * time.Hour
+
+// snoozeKeySuffix отличает ключ напоминания после отсрочки от ключа
+// запланированного приёма того же расписания на то же время.
+const snoozeKeySuffix = ":snooze"
+
+// ReminderKind отличает напоминание о запланированном приёме от повторного
+// напоминания по окончании отсрочки.
+type ReminderKind string
+
+const (
+	ReminderScheduled ReminderKind = "scheduled"
+	ReminderSnooze    ReminderKind = "snooze"
+)
+
+// Reminder — напоминание пациенту о предстоящем приёме. Snooze — повторное
+// напоминание по окончании отсрочки. PlannedAt — приём, о котором
+// напоминание: у запланированного он совпадает с DueAt, у повторного —
+// время приёма, который отложили.
+type Reminder struct {
+	UserID     int
+	ScheduleID int
+	Medication string
+	DueAt      time.Time
+	PlannedAt  time.Time
+	Snooze     bool
+	// Email — адрес пациента; заполняется при выдаче из очереди, пустой —
+	// у пациента нет учётной записи.
+	Email string
+}
+
+// IdempotencyKey однозначно определяет напоминание: повторная постановка в
+// очередь и повторная доставка одного и того же приёма дают тот же ключ.
+func (r Reminder) IdempotencyKey() string {
+	if r.Snooze {
+		return ReminderKey(r.ScheduleID, r.DueAt) + snoozeKeySuffix
+	}
+	return ReminderKey(r.ScheduleID, r.DueAt)
+}
+
+func (r Reminder) Kind() ReminderKind {
+	if r.Snooze {
+		return ReminderSnooze
+	}
+	return ReminderScheduled
+}
+
+func ReminderKey(scheduleID int, dueAt time.Time) string {
+	return fmt.Sprintf("schedule-%d-%s", scheduleID, dueAt.UTC().Format(time.RFC3339))
+}
+
+// RemindersBetween возвращает напоминания о приёмах в [from, to).
+func (s *Schedule) RemindersBetween(from, to time.Time) []Reminder {
+	takings := s.PlannedTakings(from, to)
+	reminders := make([]Reminder, 0, len(takings))
+	for _, taking := range takings {
+		reminders = append(reminders, Reminder{
+			UserID:     s.UserID,
+			ScheduleID: s.ID,
+			Medication: s.Medication,
+			DueAt:      taking,
+			PlannedAt:  taking,
+		})
+	}
+	return reminders
+}
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	OutboxCancelled OutboxStatus = "cancelled"
+	OutboxDead      OutboxStatus = "dead"
+)
+
+// OutboxMessage — напоминание в очереди на доставку.
+type OutboxMessage struct {
+	ID       int64
+	Reminder Reminder
+	Attempts int
+}
@@ -0,0 +1,50 @@
+package domain_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestReminderIdempotencyKey(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	utc := domain.Reminder{ScheduleID: 7, DueAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)}
+	local := domain.Reminder{ScheduleID: 7, DueAt: time.Date(2025, 1, 1, 12, 0, 0, 0, moscow)}
+
+	if key := utc.IdempotencyKey(); key != "schedule-7-2025-01-01T09:00:00Z" {
+		t.Errorf("Unexpected key %q", key)
+	}
+	if utc.IdempotencyKey() != local.IdempotencyKey() {
+		t.Errorf("Expected key to ignore time zone, got %q and %q", utc.IdempotencyKey(), local.IdempotencyKey())
+	}
+
+	snooze := utc
+	snooze.Snooze = true
+	if key := snooze.IdempotencyKey(); key != "schedule-7-2025-01-01T09:00:00Z:snooze" {
+		t.Errorf("Expected snooze key to differ from the planned taking, got %q", key)
+	}
+}
+
+func TestRemindersBetween(t *testing.T) {
+	schedule := domain.Schedule{
+		ID:         3,
+		UserID:     1,
+		Medication: "Aspirin",
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	from := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	first, second := time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	expected := []domain.Reminder{
+		{UserID: 1, ScheduleID: 3, Medication: "Aspirin", DueAt: first, PlannedAt: first},
+		{UserID: 1, ScheduleID: 3, Medication: "Aspirin", DueAt: second, PlannedAt: second},
+	}
+	if got := schedule.RemindersBetween(from, from.Add(domain.ReminderHorizon)); !slices.Equal(expected, got) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// ScheduleRevision — редакция расписания, действовавшая в
+// [EffectiveFrom, EffectiveTo). Нулевой EffectiveTo — редакция действует
+// до сих пор.
+type ScheduleRevision struct {
+	Number        int
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time
+	Schedule      Schedule
+}
+
+func (r ScheduleRevision) Contains(t time.Time) bool {
+	return !t.Before(r.EffectiveFrom) && (r.EffectiveTo.IsZero() || t.Before(r.EffectiveTo))
+}
+
+// RevisedAt возвращает момент, с которого действует текущая редакция.
+func (s *Schedule) RevisedAt() time.Time {
+	if len(s.Revisions) == 0 {
+		return time.Time{}
+	}
+	return s.Revisions[len(s.Revisions)-1].EffectiveTo
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package domain_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestRevisedSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	revisedAt := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	original := domain.Schedule{
+		ID:         1,
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  start,
+	}
+	current := original
+	current.TimesOfDay = []time.Duration{10 * time.Hour}
+	current.Revisions = []domain.ScheduleRevision{
+		{Number: 1, EffectiveFrom: start, EffectiveTo: revisedAt, Schedule: original},
+	}
+
+	expected := []time.Time{at(1, 9), at(2, 9), at(3, 10), at(4, 10)}
+	if got := current.PlannedTakings(start, at(5, 0)); !slices.Equal(expected, got) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	tests := []struct {
+		taking  time.Time
+		planned bool
+	}{
+		{at(1, 9), true},
+		{at(1, 10), false},
+		{at(3, 9), false},
+		{at(3, 10), true},
+	}
+	for _, tt := range tests {
+		if got := current.IsPlannedTaking(tt.taking); got != tt.planned {
+			t.Errorf("IsPlannedTaking(%v): expected %v, got %v", tt.taking, tt.planned, got)
+		}
+	}
+}
+
+func TestResumedScheduleKeepsPause(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pausedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	resumedAt := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	active := domain.Schedule{
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  start,
+	}
+	paused := active
+	paused.PausedAt = pausedAt
+
+	current := active
+	current.Revisions = []domain.ScheduleRevision{
+		{Number: 1, EffectiveFrom: start, EffectiveTo: pausedAt, Schedule: active},
+		{Number: 2, EffectiveFrom: pausedAt, EffectiveTo: resumedAt, Schedule: paused},
+	}
+
+	takings := current.PlannedTakings(start, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	if len(takings) != 3 {
+		t.Fatalf("Expected 3 takings outside the pause, got %v", takings)
+	}
+	if takings[1].Before(resumedAt) {
+		t.Errorf("Expected no takings during the pause, got %v", takings[1])
+	}
+}
+
+// Пауза → изменение → возобновление: редакция, открытая во время паузы, не
+// хранит приостановку, поэтому приёмы после возобновления снова плановые.
+func TestRevisionOpenedDuringPause(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+	regimen := func(hour int) domain.Schedule {
+		return domain.Schedule{
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{time.Duration(hour) * time.Hour},
+			Timezone:   "UTC",
+			StartTime:  start,
+		}
+	}
+
+	current := regimen(11)
+	current.Revisions = []domain.ScheduleRevision{
+		{Number: 1, EffectiveFrom: start, EffectiveTo: at(3, 0), Schedule: regimen(9)},
+		{Number: 2, EffectiveFrom: at(3, 0), EffectiveTo: at(6, 0), Schedule: regimen(10)},
+	}
+	current.Pauses = []domain.Pause{{From: at(2, 0), To: at(4, 0)}}
+
+	expected := []time.Time{at(1, 9), at(4, 10), at(5, 10), at(6, 11)}
+	if got := current.PlannedTakings(start, at(7, 0)); !slices.Equal(expected, got) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if !current.IsPlannedTaking(at(5, 10)) {
+		t.Errorf("Expected taking after the resume to be planned")
+	}
+	if current.IsPlannedTaking(at(3, 10)) {
+		t.Errorf("Expected taking during the pause not to be planned")
+	}
+}
+
+func TestCompletedPauseWithoutRevision(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	schedule := domain.Schedule{
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  start,
+		Pauses:     []domain.Pause{{From: at(2, 0), To: at(4, 0)}},
+	}
+
+	expected := []time.Time{at(1, 9), at(4, 9), at(5, 9)}
+	if got := schedule.PlannedTakings(start, at(6, 0)); !slices.Equal(expected, got) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if schedule.IsPlannedTaking(at(3, 9)) {
+		t.Errorf("Expected taking during the pause not to be planned")
+	}
+}
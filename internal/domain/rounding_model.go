@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrInvalidRounding = errors.New("rounding mode must be none, nearest, up or down with a step of whole minutes that divides 24 hours")
+
+// RoundingMode — как время приёма интервального расписания приводится к
+// сетке с шагом Rounding.Step.
+type RoundingMode string
+
+const (
+	// RoundNone — приём в точно рассчитанное время (инсулин, антибиотики).
+	RoundNone    RoundingMode = "none"
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+var roundingModes = []RoundingMode{RoundNone, RoundNearest, RoundUp, RoundDown}
+
+// DefaultRounding — округление расписаний, для которых оно не задано:
+// вверх до ближайших 15 минут.
+var DefaultRounding = Rounding{Mode: RoundUp, Step: RoundTo * time.Minute}
+
+// Rounding — политика округления времени приёма. Сетка строится по
+// настенным часам от местной полуночи, поэтому работает и для поясов со
+// смещением, не кратным шагу.
+type Rounding struct {
+	Mode RoundingMode
+	Step time.Duration
+}
+
+func (r Rounding) IsZero() bool {
+	return r == Rounding{}
+}
+
+func (r Rounding) Validate() error {
+	if !slices.Contains(roundingModes, r.Mode) {
+		return ErrInvalidRounding
+	}
+	if r.Mode == RoundNone {
+		return nil
+	}
+	if r.Step < time.Minute || r.Step%time.Minute != 0 || (24*time.Hour)%r.Step != 0 {
+		return ErrInvalidRounding
+	}
+	return nil
+}
+
+// Apply округляет t по политике. Результат не убывает с ростом t в пределах
+// одного смещения часового пояса.
+func (r Rounding) Apply(t time.Time) time.Time {
+	if r.Mode == RoundNone || r.Step <= 0 {
+		return t
+	}
+
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	steps, remainder := clock/r.Step, clock%r.Step
+	switch r.Mode {
+	case RoundUp:
+		if remainder > 0 {
+			steps++
+		}
+	case RoundNearest:
+		if 2*remainder >= r.Step {
+			steps++
+		}
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, int(steps*r.Step/time.Minute), 0, 0, t.Location())
+}
+
+// RoundingPolicy возвращает округление расписания или DefaultRounding,
+// если оно не задано.
+func (s *Schedule) RoundingPolicy() Rounding {
+	if s.Rounding.IsZero() {
+		return DefaultRounding
+	}
+	return s.Rounding
+}
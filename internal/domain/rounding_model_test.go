@@ -0,0 +1,191 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestRounding_Apply(t *testing.T) {
+	at := func(hour, minute, second int) time.Time {
+		return time.Date(2025, 1, 1, hour, minute, second, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rounding domain.Rounding
+		input    time.Time
+		expected time.Time
+	}{
+		{"None", domain.Rounding{Mode: domain.RoundNone}, at(9, 7, 30), at(9, 7, 30)},
+		{"Up", domain.Rounding{Mode: domain.RoundUp, Step: 15 * time.Minute}, at(9, 1, 0), at(9, 15, 0)},
+		{"Up on grid", domain.Rounding{Mode: domain.RoundUp, Step: 15 * time.Minute}, at(9, 15, 0), at(9, 15, 0)},
+		{"Down", domain.Rounding{Mode: domain.RoundDown, Step: 15 * time.Minute}, at(9, 14, 59), at(9, 0, 0)},
+		{"Nearest below half", domain.Rounding{Mode: domain.RoundNearest, Step: 15 * time.Minute}, at(9, 7, 0), at(9, 0, 0)},
+		{"Nearest half", domain.Rounding{Mode: domain.RoundNearest, Step: 15 * time.Minute}, at(9, 7, 30), at(9, 15, 0)},
+		{"Custom step", domain.Rounding{Mode: domain.RoundNearest, Step: 5 * time.Minute}, at(9, 8, 0), at(9, 10, 0)},
+		{"Up past midnight", domain.Rounding{Mode: domain.RoundUp, Step: time.Hour}, at(23, 10, 0), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rounding.Apply(tt.input); !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRounding_Validate(t *testing.T) {
+	tests := []struct {
+		rounding domain.Rounding
+		wantErr  bool
+	}{
+		{domain.Rounding{Mode: domain.RoundNone}, false},
+		{domain.Rounding{Mode: domain.RoundDown, Step: 10 * time.Minute}, false},
+		{domain.Rounding{Mode: domain.RoundUp, Step: 24 * time.Hour}, false},
+		{domain.Rounding{Mode: "ceil", Step: 15 * time.Minute}, true},
+		{domain.Rounding{Mode: domain.RoundUp}, true},
+		{domain.Rounding{Mode: domain.RoundUp, Step: 90 * time.Second}, true},
+		{domain.Rounding{Mode: domain.RoundNearest, Step: 7 * time.Minute}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.rounding.Validate()
+		if tt.wantErr != errors.Is(err, domain.ErrInvalidRounding) {
+			t.Errorf("%+v: expected error %v, got %v", tt.rounding, tt.wantErr, err)
+		}
+	}
+}
+
+// roundingCase — случайные входные данные для свойств округления.
+type roundingCase struct {
+	Mode   uint8
+	Step   uint8
+	Offset uint32
+	Delta  uint32
+}
+
+var (
+	propertyModes = []domain.RoundingMode{domain.RoundNone, domain.RoundNearest, domain.RoundUp, domain.RoundDown}
+	propertySteps = []time.Duration{time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute, time.Hour}
+)
+
+func (c roundingCase) rounding() domain.Rounding {
+	return domain.Rounding{
+		Mode: propertyModes[int(c.Mode)%len(propertyModes)],
+		Step: propertySteps[int(c.Step)%len(propertySteps)],
+	}
+}
+
+func TestRounding_Properties(t *testing.T) {
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, kathmandu)
+
+	monotonic := func(c roundingCase) bool {
+		r := c.rounding()
+		t1 := base.Add(time.Duration(c.Offset) * time.Millisecond)
+		t2 := t1.Add(time.Duration(c.Delta) * time.Millisecond)
+		return !r.Apply(t2).Before(r.Apply(t1))
+	}
+	if err := quick.Check(monotonic, nil); err != nil {
+		t.Errorf("Rounding is not monotonic: %v", err)
+	}
+
+	onGridAndClose := func(c roundingCase) bool {
+		r := c.rounding()
+		input := base.Add(time.Duration(c.Offset) * time.Millisecond)
+		got := r.Apply(input)
+		if r.Mode == domain.RoundNone {
+			return got.Equal(input)
+		}
+
+		local := got.In(kathmandu)
+		clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+		if clock%r.Step != 0 || local.Second() != 0 || local.Nanosecond() != 0 {
+			return false
+		}
+		shift := got.Sub(input)
+		switch r.Mode {
+		case domain.RoundUp:
+			return shift >= 0 && shift < r.Step
+		case domain.RoundDown:
+			return shift <= 0 && shift > -r.Step
+		default:
+			return 2*shift <= r.Step && 2*shift > -r.Step
+		}
+	}
+	if err := quick.Check(onGridAndClose, nil); err != nil {
+		t.Errorf("Rounding is off the grid or too far: %v", err)
+	}
+}
+
+func TestCalculateTakings_RoundingStaysInWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	containment := func(c roundingCase, frequency uint16, windowStart, windowEnd uint16) bool {
+		s := domain.Schedule{
+			Kind:      domain.KindInterval,
+			Frequency: 15*time.Minute + time.Duration(frequency%600)*time.Minute + time.Duration(c.Delta%60)*time.Second,
+			Rounding:  c.rounding(),
+			Window: domain.DayWindow{
+				Start: time.Duration(windowStart%1440) * time.Minute,
+				End:   time.Duration(windowEnd%1440) * time.Minute,
+			},
+			Timezone:  "UTC",
+			StartTime: now.Add(-48 * time.Hour),
+			EndTime:   domain.PerpetualEndTime,
+		}
+		if s.Window.Validate() != nil {
+			return true
+		}
+
+		dayStart, dayEnd := s.Window.Bounds(now)
+		takings := s.CalculateTakings(now)
+		for i, taking := range takings {
+			if taking.Before(dayStart) || taking.After(dayEnd) {
+				return false
+			}
+			if i > 0 && !taking.After(takings[i-1]) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(containment, nil); err != nil {
+		t.Errorf("Takings left the window: %v", err)
+	}
+}
+
+func TestFindNextTaking_MatchesCalculateTakings(t *testing.T) {
+	now := time.Date(2025, 1, 1, 11, 50, 0, 0, time.UTC)
+	for _, mode := range propertyModes {
+		s := domain.Schedule{
+			Kind:      domain.KindInterval,
+			Frequency: 50 * time.Minute,
+			Rounding:  domain.Rounding{Mode: mode, Step: 15 * time.Minute},
+			Timezone:  "UTC",
+			StartTime: now.Add(-24 * time.Hour),
+			EndTime:   domain.PerpetualEndTime,
+		}
+
+		var expected time.Time
+		for _, taking := range s.CalculateTakings(now) {
+			if taking.After(now) {
+				expected = taking
+				break
+			}
+		}
+
+		next, ok := s.FindNextTaking(now, now.Add(3*time.Hour))
+		if !ok || !next.Equal(expected) {
+			t.Errorf("%s: expected next taking %v, got %v (found %v)", mode, expected, next, ok)
+		}
+	}
+}
@@ -2,15 +2,37 @@ package domain
 
 import (
 	"errors"
+	"slices"
 	"time"
 )
 
 var (
 	ErrInvalidFrequency = errors.New("frequency must be at least 15 minutes")
 	ErrInvalidDuration  = errors.New("duration must be positive or zero for perpetual")
+	ErrInvalidTimezone  = errors.New("timezone must be a valid IANA time zone name")
+	ErrInvalidKind      = errors.New("unknown schedule kind")
+	ErrInvalidTimes     = errors.New("times of day must be unique and fall within the intake window")
+)
+
+type ScheduleKind string
+
+const (
+	// KindInterval — приём каждые Frequency, начиная с начала окна; при
+	// Frequency больше суток — от начала расписания (см. IsMultiDay).
+	KindInterval ScheduleKind = "interval"
+	// KindFixedTimes — приём в заданное время суток (TimesOfDay).
+	KindFixedTimes ScheduleKind = "fixed_times"
+	// KindAsNeeded — приём по требованию (PRN): плановых приёмов нет,
+	// ограничены интервал между приёмами (MinInterval) и их число за
+	// сутки (MaxPerDay).
+	KindAsNeeded ScheduleKind = "as_needed"
+	// KindEventRelative — приём относительно событий дня пациента
+	// (Anchors): «за 30 минут до завтрака», «перед сном».
+	KindEventRelative ScheduleKind = "event_relative"
 )
 
 const (
+	DayStartHour  = 8
 	AvailableTime = 14
 	RoundTo       = 15
 )
@@ -19,41 +41,282 @@ type Schedule struct {
 	ID         int
 	UserID     int
 	Medication string
-	Frequency  time.Duration
-	Duration   time.Duration
-	StartTime  time.Time
-	EndTime    time.Time
-	Takings    []time.Time
+	// Dosage — доза на один приём; нулевое значение — доза не указана.
+	Dosage    Dosage
+	Kind      ScheduleKind
+	Frequency time.Duration
+	// Rounding — округление приёмов интервального расписания; нулевое
+	// значение — DefaultRounding.
+	Rounding   Rounding
+	TimesOfDay []time.Duration
+	// Anchors — привязки приёмов к событиям дня; время событий берётся из
+	// Events — настроек пользователя.
+	Anchors []Anchor
+	Events  DailyEvents `json:"-"`
+	// MinInterval и MaxPerDay — ограничения приёма по требованию.
+	MinInterval time.Duration
+	MaxPerDay   int
+	Duration    time.Duration
+	Timezone    string
+	Window      DayWindow
+	// InheritsWindow — окно не задано в расписании и взято из настроек
+	// пользователя.
+	InheritsWindow bool
+	Weekdays       Weekdays
+	Cycle          Cycle
+	// Phases — этапы ступенчатого расписания по порядку; пустой список —
+	// весь срок действуют режим и доза самого расписания.
+	Phases    []Phase
+	StartTime time.Time
+	EndTime   time.Time
+	// PausedAt — момент приостановки; нулевое значение — расписание действует.
+	PausedAt time.Time
+	// Pauses — завершённые приостановки в хронологическом порядке. Редакций
+	// они не создают, поэтому учитываются поверх всей истории расписания.
+	Pauses []Pause `json:"-"`
+	// Revisions — прежние редакции расписания в хронологическом порядке;
+	// текущая редакция действует с конца последней из них. В ответы API не
+	// попадает: история отдаётся отдельным запросом.
+	Revisions []ScheduleRevision `json:"-"`
+	Takings   []time.Time
+}
+
+// Pause — завершённая приостановка расписания, действовавшая в [From, To).
+type Pause struct {
+	From time.Time
+	To   time.Time
 }
 
 func (s *Schedule) Validate() error {
-	if s.Frequency < 15*time.Minute {
-		return ErrInvalidFrequency
+	if err := s.validateRegimen(); err != nil {
+		return err
 	}
 	if s.Duration < 0 {
 		return ErrInvalidDuration
 	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "Local" {
+		return ErrInvalidTimezone
+	}
+	if !s.Window.IsZero() {
+		if err := s.Window.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.Weekdays > AllWeekdays {
+		return ErrInvalidWeekdays
+	}
+	if !s.Cycle.IsZero() {
+		if err := s.Cycle.Validate(); err != nil {
+			return err
+		}
+	}
+	if !s.Dosage.IsZero() {
+		if err := s.Dosage.Validate(); err != nil {
+			return err
+		}
+	}
+	if !s.Rounding.IsZero() {
+		if err := s.Rounding.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(s.Phases) > 0 {
+		if err := s.validatePhases(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schedule) validateRegimen() error {
+	switch s.Kind {
+	case "", KindInterval:
+		if s.Frequency < 15*time.Minute {
+			return ErrInvalidFrequency
+		}
+	case KindFixedTimes:
+		if err := s.validateTimesOfDay(); err != nil {
+			return err
+		}
+	case KindAsNeeded:
+		if err := s.validateAsNeeded(); err != nil {
+			return err
+		}
+	case KindEventRelative:
+		if err := s.validateAnchors(); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidKind
+	}
 	return nil
 }
 
+func (s *Schedule) validateTimesOfDay() error {
+	if len(s.TimesOfDay) == 0 {
+		return ErrInvalidTimes
+	}
+
+	window := s.DayWindow()
+	seen := make(map[time.Duration]bool, len(s.TimesOfDay))
+	for _, clock := range s.TimesOfDay {
+		if clock < 0 || clock >= 24*time.Hour || clock%time.Minute != 0 {
+			return ErrInvalidTimes
+		}
+		if seen[clock] || !window.Contains(clock) {
+			return ErrInvalidTimes
+		}
+		seen[clock] = true
+	}
+	return nil
+}
+
+// DayWindow возвращает окно приёма расписания или окно по умолчанию,
+// если ни расписание, ни настройки пользователя его не задают.
+func (s *Schedule) DayWindow() DayWindow {
+	if s.Window.IsZero() {
+		return DefaultDayWindow
+	}
+	return s.Window
+}
+
+// Location возвращает часовой пояс пациента, в котором строится окно приёма.
+// Пустое или неизвестное значение трактуется как UTC.
+func (s *Schedule) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 func (s *Schedule) CalculateTakings(now time.Time) []time.Time {
 	if !s.IsActive(now) {
 		return nil
 	}
 
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, now.Location())
-	dayEnd := dayStart.Add(AvailableTime * time.Hour)
+	return s.regimenTakings(s.DayWindow().Bounds(now.In(s.Location())))
+}
+
+// TakingsOn возвращает запланированные приёмы в окне, которое начинается
+// в календарный день day по часовому поясу расписания. В отличие от
+// CalculateTakings результат не зависит от текущего момента: приёмы вне
+// срока действия расписания отбрасываются.
+func (s *Schedule) TakingsOn(day time.Time) []time.Time {
+	window := s.DayWindow()
+	takings := s.regimenTakings(window.Bounds(AtClock(day.In(s.Location()), window.Start)))
+	active := takings[:0]
+	for _, taking := range takings {
+		if taking.Before(s.StartTime) || (s.Duration > 0 && !taking.Before(s.EndTime)) {
+			continue
+		}
+		if s.isPausedAt(taking) {
+			continue
+		}
+		active = append(active, taking)
+	}
+	return active
+}
 
+// PlannedTakings возвращает все запланированные приёмы в [from, to) в
+// хронологическом порядке. Приёмы в прошлом считаются по редакции
+// расписания, действовавшей в тот момент.
+func (s *Schedule) PlannedTakings(from, to time.Time) []time.Time {
+	var result []time.Time
+	for _, revision := range s.Revisions {
+		for _, taking := range revision.Schedule.plannedTakings(
+			latest(from, revision.EffectiveFrom), earliest(to, revision.EffectiveTo)) {
+			if !s.isPausedAt(taking) {
+				result = append(result, taking)
+			}
+		}
+	}
+	return append(result, s.plannedTakings(latest(from, s.RevisedAt()), to)...)
+}
+
+// plannedTakings считает приёмы по текущим полям расписания. Обход
+// начинается с предыдущего дня, чтобы не потерять приёмы ночного окна,
+// начавшегося накануне.
+func (s *Schedule) plannedTakings(from, to time.Time) []time.Time {
+	if !from.Before(to) {
+		return nil
+	}
+
+	var result []time.Time
+	day := AtClock(from.In(s.Location()), 0).AddDate(0, 0, -1)
+	for !day.After(to) {
+		for _, taking := range s.TakingsOn(day) {
+			if !taking.Before(from) && taking.Before(to) {
+				result = append(result, taking)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return result
+}
+
+// IsPlannedTaking сообщает, совпадает ли t с одним из запланированных
+// приёмов. Проверяется и окно предыдущего дня, если оно переходит через
+// полночь.
+func (s *Schedule) IsPlannedTaking(t time.Time) bool {
+	if s.isPausedAt(t) {
+		return false
+	}
+	if t.Before(s.RevisedAt()) {
+		for _, revision := range s.Revisions {
+			if revision.Contains(t) {
+				return revision.Schedule.IsPlannedTaking(t)
+			}
+		}
+		return false
+	}
+
+	local := t.In(s.Location())
+	for _, day := range []time.Time{local, local.AddDate(0, 0, -1)} {
+		for _, taking := range s.TakingsOn(day) {
+			if taking.Equal(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regimenTakings возвращает приёмы окна [dayStart, dayEnd] по режиму
+// расписания или, если оно ступенчатое, по режимам его этапов.
+func (s *Schedule) regimenTakings(dayStart, dayEnd time.Time) []time.Time {
+	if len(s.Phases) > 0 {
+		return s.phaseTakings(dayStart, dayEnd)
+	}
+	return s.takingsBetween(dayStart, dayEnd)
+}
+
+func (s *Schedule) takingsBetween(dayStart, dayEnd time.Time) []time.Time {
+	if s.Kind == KindAsNeeded || !s.IsDosingDay(dayStart) {
+		return nil
+	}
+	if s.Kind == KindFixedTimes || s.Kind == KindEventRelative {
+		return s.fixedTakings(dayStart, dayEnd)
+	}
+	if s.IsMultiDay() {
+		return s.multiDayTakings(dayStart, dayEnd)
+	}
+
+	rounding := s.RoundingPolicy()
 	var takings []time.Time
 	current := dayStart
 
 	for current.Before(dayEnd) {
-		rounded := RoundToNearest15(current)
+		// Округлённое время не должно выходить за окно приёма.
+		rounded := rounding.Apply(current)
 		if rounded.After(dayEnd) {
 			break
 		}
 
-		if len(takings) == 0 || rounded.After(takings[len(takings)-1]) {
+		if !rounded.Before(dayStart) && (len(takings) == 0 || rounded.After(takings[len(takings)-1])) {
 			takings = append(takings, rounded)
 		}
 
@@ -62,46 +325,76 @@ func (s *Schedule) CalculateTakings(now time.Time) []time.Time {
 	return takings
 }
 
+// fixedTakings раскладывает время суток по окну [dayStart, dayEnd]: время,
+// меньшее начала окна, относится к следующим календарным суткам. Заданное
+// время не округляется — это точное назначение врача.
+func (s *Schedule) fixedTakings(dayStart, dayEnd time.Time) []time.Time {
+	clocks := s.TimesOfDay
+	if s.Kind == KindEventRelative {
+		clocks = s.anchorClocks()
+	}
+	takings := make([]time.Time, 0, len(clocks))
+	for _, clock := range clocks {
+		taking := AtClock(dayStart, clock)
+		if taking.Before(dayStart) {
+			taking = AtClock(dayStart.AddDate(0, 0, 1), clock)
+		}
+		if !taking.After(dayEnd) {
+			takings = append(takings, taking)
+		}
+	}
+
+	slices.SortFunc(takings, func(a, b time.Time) int { return a.Compare(b) })
+	return takings
+}
+
 func (s *Schedule) IsActive(now time.Time) bool {
+	if s.IsPaused() && !now.Before(s.PausedAt) {
+		return false
+	}
+	if now.Before(s.StartTime) {
+		return false
+	}
 	if s.Duration == 0 {
 		return true
 	}
 	return now.After(s.StartTime) && now.Before(s.EndTime)
 }
 
-func RoundToNearest15(t time.Time) time.Time {
-	minutes := t.Minute()
-	remainder := minutes % RoundTo
-	if remainder == 0 {
-		return t.Truncate(RoundTo * time.Minute)
-	}
-	return t.Add(time.Duration(RoundTo-remainder) * time.Minute).Truncate(RoundTo * time.Minute)
+func (s *Schedule) IsPaused() bool {
+	return !s.PausedAt.IsZero()
 }
 
-func (s *Schedule) FindNextTaking(now time.Time, periodEnd time.Time) (time.Time, bool) {
-	if !s.IsActive(now) {
-		return time.Time{}, false
+// isPausedAt сообщает, приходится ли t на текущую или одну из завершённых
+// приостановок.
+func (s *Schedule) isPausedAt(t time.Time) bool {
+	if s.IsPaused() && !t.Before(s.PausedAt) {
+		return true
 	}
-
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, now.Location())
-	dayEnd := dayStart.Add(AvailableTime * time.Hour)
-
-	if now.After(dayEnd) || now.Before(dayStart) {
-		return time.Time{}, false
+	for _, pause := range s.Pauses {
+		if !t.Before(pause.From) && t.Before(pause.To) {
+			return true
+		}
 	}
+	return false
+}
 
-	baseTime := dayStart
-
-	elapsed := now.Sub(baseTime)
-	intervalsPassed := elapsed / s.Frequency
-
-	nextInterval := baseTime.Add((intervalsPassed + 1) * s.Frequency)
-
-	nextTime := RoundToNearest15(nextInterval)
+// IsDosingDay сообщает, положен ли приём в сутки, окно которых начинается
+// в dayStart, с учётом дней недели и цикла приёма/перерыва.
+func (s *Schedule) IsDosingDay(dayStart time.Time) bool {
+	loc := s.Location()
+	day := dayStart.In(loc)
+	return s.Weekdays.Has(day.Weekday()) && s.Cycle.IsOnDay(s.StartTime.In(loc), day)
+}
 
-	if nextTime.Before(dayStart) || nextTime.After(dayEnd) || nextTime.After(periodEnd) {
-		return time.Time{}, false
+// FindNextTaking возвращает ближайший приём после now, не позже periodEnd.
+// Поиск не ограничен текущим окном: если окно уже закончилось, ближайшим
+// будет приём следующего дня.
+func (s *Schedule) FindNextTaking(now time.Time, periodEnd time.Time) (time.Time, bool) {
+	for _, taking := range s.PlannedTakings(now, periodEnd.Add(time.Nanosecond)) {
+		if taking.After(now) {
+			return taking, true
+		}
 	}
-
-	return nextTime, true
+	return time.Time{}, false
 }
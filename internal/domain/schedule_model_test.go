@@ -3,11 +3,12 @@ package domain_test
 import (
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	"medication-scheduler/internal/domain"
 )
 
-func TestRoundToNearest15(t *testing.T) {
+func TestDefaultRounding(t *testing.T) {
 	tests := []struct {
 		input  time.Time
 		expect time.Time
@@ -28,7 +29,7 @@ func TestRoundToNearest15(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input.String(), func(t *testing.T) {
-			got := domain.RoundToNearest15(tt.input)
+			got := domain.DefaultRounding.Apply(tt.input)
 			if !got.Equal(tt.expect) {
 				t.Errorf("Expected %v, got %v", tt.expect, got)
 			}
@@ -132,3 +133,202 @@ func TestFindNextTaking(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateTakings_Timezone(t *testing.T) {
+	schedule := domain.Schedule{
+		Frequency: 4 * time.Hour,
+		Timezone:  "America/New_York",
+	}
+
+	// 03:00 UTC — ещё 22:00 предыдущего дня в Нью-Йорке
+	now := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	takings := schedule.CalculateTakings(now)
+
+	expected := []string{"08:00", "12:00", "16:00", "20:00"}
+	if len(takings) != len(expected) {
+		t.Fatalf("Expected %d takings, got %d", len(expected), len(takings))
+	}
+	for i, tm := range takings {
+		if tm.Location().String() != "America/New_York" {
+			t.Errorf("Expected New York location, got %v", tm.Location())
+		}
+		if tm.Day() != 1 || tm.Format("15:04") != expected[i] {
+			t.Errorf("Expected Jan 1 %s, got %v", expected[i], tm)
+		}
+	}
+}
+
+func TestCalculateTakings_DSTTransition(t *testing.T) {
+	schedule := domain.Schedule{
+		Frequency: 2 * time.Hour,
+		Timezone:  "Europe/Berlin",
+	}
+
+	// 30 марта 2025 в Берлине переход на летнее время
+	now := time.Date(2025, 3, 30, 10, 0, 0, 0, time.UTC)
+	takings := schedule.CalculateTakings(now)
+
+	if len(takings) != 7 {
+		t.Fatalf("Expected 7 takings, got %d", len(takings))
+	}
+	if takings[0].Format("15:04 -0700") != "08:00 +0200" {
+		t.Errorf("Expected first taking at 08:00 CEST, got %v", takings[0])
+	}
+	if last := takings[len(takings)-1]; last.Format("15:04") != "20:00" {
+		t.Errorf("Expected last taking at 20:00, got %v", last)
+	}
+}
+
+func TestDefaultRounding_NonQuarterOffset(t *testing.T) {
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := domain.DefaultRounding.Apply(time.Date(2025, 1, 1, 9, 7, 0, 0, kathmandu))
+	if got.Format("15:04") != "09:15" {
+		t.Errorf("Expected 09:15 local time, got %v", got)
+	}
+}
+
+func TestValidate_Timezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		wantErr  bool
+	}{
+		{"", false},
+		{"UTC", false},
+		{"Europe/Moscow", false},
+		{"Local", true},
+		{"Mars/Olympus", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			s := domain.Schedule{Frequency: time.Hour, Timezone: tt.timezone}
+			err := s.Validate()
+			if tt.wantErr && err != domain.ErrInvalidTimezone {
+				t.Errorf("Expected ErrInvalidTimezone, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCalculateTakings_FixedTimes(t *testing.T) {
+	schedule := domain.Schedule{
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{21 * time.Hour, 8 * time.Hour, 14*time.Hour + 10*time.Minute},
+		Timezone:   "Europe/Moscow",
+	}
+
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC) // 12:00 в Москве
+	takings := schedule.CalculateTakings(now)
+
+	expected := []string{"08:00", "14:10", "21:00"}
+	if len(takings) != len(expected) {
+		t.Fatalf("Expected %d takings, got %v", len(expected), takings)
+	}
+	for i, tm := range takings {
+		if tm.Format("15:04") != expected[i] {
+			t.Errorf("Expected %s, got %v", expected[i], tm)
+		}
+	}
+
+	next, found := schedule.FindNextTaking(now, now.Add(3*time.Hour))
+	if !found || next.Format("15:04") != "14:10" {
+		t.Errorf("Expected next taking at 14:10, got %v (found=%v)", next, found)
+	}
+
+	if _, found := schedule.FindNextTaking(now, now.Add(time.Hour)); found {
+		t.Errorf("Expected no taking within an hour")
+	}
+}
+
+func TestValidate_FixedTimes(t *testing.T) {
+	tests := []struct {
+		name    string
+		times   []time.Duration
+		wantErr bool
+	}{
+		{"Valid", []time.Duration{8 * time.Hour, 21 * time.Hour}, false},
+		{"Empty", nil, true},
+		{"Duplicate", []time.Duration{8 * time.Hour, 8 * time.Hour}, true},
+		{"Outside window", []time.Duration{3 * time.Hour}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := domain.Schedule{Kind: domain.KindFixedTimes, TimesOfDay: tt.times}
+			err := s.Validate()
+			if tt.wantErr && err != domain.ErrInvalidTimes {
+				t.Errorf("Expected ErrInvalidTimes, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPausedSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pausedAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  start,
+		PausedAt:   pausedAt,
+	}
+
+	takings := s.PlannedTakings(start, start.AddDate(0, 0, 3))
+	if len(takings) != 3 {
+		t.Fatalf("Expected 3 takings before pause, got %d", len(takings))
+	}
+	if last := takings[len(takings)-1]; !last.Before(pausedAt) {
+		t.Errorf("Expected no takings after pause, got %v", last)
+	}
+
+	if !s.IsActive(pausedAt.Add(-time.Minute)) || s.IsActive(pausedAt) {
+		t.Errorf("Expected schedule to become inactive at %v", pausedAt)
+	}
+	if _, found := s.FindNextTaking(pausedAt.Add(time.Hour), pausedAt.Add(12*time.Hour)); found {
+		t.Errorf("Expected no next taking while paused")
+	}
+
+	s.PausedAt = time.Time{}
+	expected := time.Date(2025, 1, 2, 21, 0, 0, 0, time.UTC)
+	if next, found := s.FindNextTaking(pausedAt.Add(time.Hour), pausedAt.Add(12*time.Hour)); !found || !next.Equal(expected) {
+		t.Errorf("Expected next taking %v after resume, got %v (found=%v)", expected, next, found)
+	}
+}
+
+func TestFutureStartSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	s := domain.Schedule{
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  start,
+	}
+
+	if s.IsActive(start.Add(-time.Hour)) || !s.IsActive(start.Add(time.Hour)) {
+		t.Errorf("Expected schedule to become active at %v", start)
+	}
+
+	if takings := s.TakingsOn(start); len(takings) != 1 || takings[0].Hour() != 21 {
+		t.Errorf("Expected only the 21:00 taking on the start day, got %v", takings)
+	}
+
+	expected := time.Date(2025, 1, 3, 21, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	if next, found := s.FindNextTaking(now, now.Add(48*time.Hour)); !found || !next.Equal(expected) {
+		t.Errorf("Expected first taking %v, got %v (found=%v)", expected, next, found)
+	}
+	if _, found := s.FindNextTaking(now, now.Add(24*time.Hour)); found {
+		t.Errorf("Expected no taking before the schedule starts")
+	}
+}
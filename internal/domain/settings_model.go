@@ -0,0 +1,18 @@
+package domain
+
+// UserSettings — персональные настройки пациента, которые наследуют все его
+// расписания, если в самом расписании они не переопределены.
+type UserSettings struct {
+	UserID int
+	Window DayWindow
+	// Events — время событий дня пациента; не заданные берутся из
+	// DefaultDailyEvents.
+	Events DailyEvents
+}
+
+func (s *UserSettings) Validate() error {
+	if err := s.Window.Validate(); err != nil {
+		return err
+	}
+	return s.Events.Validate()
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// StreamEventType — вид события в потоке пользователя.
+type StreamEventType string
+
+const (
+	// StreamTakingDue — наступило время запланированного приёма.
+	StreamTakingDue StreamEventType = "taking_due"
+	// StreamScheduleChanged — расписание создано, изменено, приостановлено,
+	// возобновлено или удалено; клиенту стоит перечитать приёмы.
+	StreamScheduleChanged StreamEventType = "schedule_changed"
+	// StreamDoseRecorded — отмечен приём, пропуск или откладывание дозы.
+	StreamDoseRecorded StreamEventType = "dose_recorded"
+)
+
+// StreamEvent — событие для доставки клиентам пользователя в реальном
+// времени. ID присваивается при публикации и растёт монотонно.
+type StreamEvent struct {
+	ID         int64
+	Type       StreamEventType
+	UserID     int
+	ScheduleID int
+	Medication string
+	// At — время приёма для taking_due и dose_recorded, момент изменения
+	// для schedule_changed.
+	At     time.Time
+	Dosage Dosage
+	Status DoseStatus
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidEmail    = errors.New("email must be a valid address")
+	ErrInvalidPassword = errors.New("password must be from 8 to 72 bytes long")
+)
+
+// Границы длины пароля; 72 байта — предел bcrypt.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// User — учётная запись пациента. Его ID — тот user_id, которым помечены
+// расписания, отметки и настройки.
+type User struct {
+	ID             int
+	OrganizationID int
+	Email          string
+	PasswordHash   []byte
+	CreatedAt      time.Time
+}
+
+// NormalizeEmail приводит адрес к виду, в котором он хранится: без
+// пробелов по краям и в нижнем регистре.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidateCredentials проверяет адрес и пароль при регистрации.
+func ValidateCredentials(email, password string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return ErrInvalidEmail
+	}
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// Session — токен, выданный пользователю при входе.
+type Session struct {
+	UserID         int
+	OrganizationID int
+	Token          string
+	ExpiresAt      time.Time
+}
@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := domain.NormalizeEmail("  Patient@Example.COM "); got != "patient@example.com" {
+		t.Errorf("NormalizeEmail() = %q", got)
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     error
+	}{
+		{"valid", "patient@example.com", "correct horse", nil},
+		{"display name", "Patient <patient@example.com>", "correct horse", domain.ErrInvalidEmail},
+		{"no domain", "patient", "correct horse", domain.ErrInvalidEmail},
+		{"empty email", "", "correct horse", domain.ErrInvalidEmail},
+		{"short password", "patient@example.com", "short", domain.ErrInvalidPassword},
+		{"long password", "patient@example.com", strings.Repeat("x", 73), domain.ErrInvalidPassword},
+		{"longest password", "patient@example.com", strings.Repeat("x", 72), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := domain.ValidateCredentials(tt.email, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateCredentials() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidWindow = errors.New("intake window must start and end within a day and must not be empty")
+	ErrInvalidClock  = errors.New("time of day must be in HH:MM format")
+)
+
+// DayWindow — окно приёма внутри суток, заданное смещениями от полуночи
+// по местному времени. Если End не позже Start, окно переходит через полночь
+// (например, 20:00–08:00 для ночной смены).
+type DayWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+var DefaultDayWindow = DayWindow{
+	Start: DayStartHour * time.Hour,
+	End:   (DayStartHour + AvailableTime) * time.Hour,
+}
+
+func (w DayWindow) IsZero() bool {
+	return w.Start == 0 && w.End == 0
+}
+
+func (w DayWindow) Validate() error {
+	if w.Start < 0 || w.Start >= 24*time.Hour || w.End <= 0 || w.End > 24*time.Hour {
+		return ErrInvalidWindow
+	}
+	if w.Start == w.End || w.Start%time.Minute != 0 || w.End%time.Minute != 0 {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Contains сообщает, попадает ли время суток clock в окно (границы включены).
+func (w DayWindow) Contains(clock time.Duration) bool {
+	if w.crossesMidnight() {
+		return clock >= w.Start || clock <= w.End
+	}
+	return clock >= w.Start && clock <= w.End
+}
+
+func (w DayWindow) crossesMidnight() bool {
+	return w.End <= w.Start
+}
+
+// Bounds возвращает окно, в которое попадает now, а если now вне окна —
+// окно, начинающееся в тот же календарный день. Границы строятся по
+// настенным часам часового пояса now, поэтому переходы на летнее время
+// не сдвигают окно.
+func (w DayWindow) Bounds(now time.Time) (time.Time, time.Time) {
+	if !w.crossesMidnight() {
+		return AtClock(now, w.Start), AtClock(now, w.End)
+	}
+
+	yesterday := now.AddDate(0, 0, -1)
+	if prevEnd := AtClock(now, w.End); now.Before(prevEnd) {
+		return AtClock(yesterday, w.Start), prevEnd
+	}
+	return AtClock(now, w.Start), AtClock(now.AddDate(0, 0, 1), w.End)
+}
+
+// AtClock возвращает момент в календарный день day, соответствующий
+// смещению clock от местной полуночи.
+func AtClock(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(clock/time.Minute), 0, 0, day.Location())
+}
+
+// ParseClock разбирает время суток в формате "HH:MM"; "24:00" допустимо
+// как конец окна.
+func ParseClock(s string) (time.Duration, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, ErrInvalidClock
+	}
+	for i, c := range s {
+		if i != 2 && (c < '0' || c > '9') {
+			return 0, ErrInvalidClock
+		}
+	}
+
+	h := int(s[0]-'0')*10 + int(s[1]-'0')
+	m := int(s[3]-'0')*10 + int(s[4]-'0')
+	if m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, ErrInvalidClock
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func FormatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
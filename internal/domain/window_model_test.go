@@ -0,0 +1,139 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"08:00", 8 * time.Hour, false},
+		{"21:45", 21*time.Hour + 45*time.Minute, false},
+		{"24:00", 24 * time.Hour, false},
+		{"8:00", 0, true},
+		{"24:30", 0, true},
+		{"12:60", 0, true},
+		{"+8:00", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := domain.ParseClock(tt.input)
+			if tt.wantErr {
+				if err != domain.ErrInvalidClock {
+					t.Errorf("Expected ErrInvalidClock, got %v", err)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("Expected %v, got %v (err %v)", tt.expected, got, err)
+			}
+			if domain.FormatClock(got) != tt.input {
+				t.Errorf("Expected %s after format, got %s", tt.input, domain.FormatClock(got))
+			}
+		})
+	}
+}
+
+func TestDayWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  domain.DayWindow
+		wantErr bool
+	}{
+		{"Default", domain.DefaultDayWindow, false},
+		{"Night shift", domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour}, false},
+		{"Until midnight", domain.DayWindow{Start: 10 * time.Hour, End: 24 * time.Hour}, false},
+		{"Empty", domain.DayWindow{Start: 9 * time.Hour, End: 9 * time.Hour}, true},
+		{"Start out of day", domain.DayWindow{Start: 24 * time.Hour, End: 8 * time.Hour}, true},
+		{"Seconds", domain.DayWindow{Start: 8*time.Hour + time.Second, End: 9 * time.Hour}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDayWindowBounds(t *testing.T) {
+	night := domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour}
+
+	tests := []struct {
+		name          string
+		window        domain.DayWindow
+		now           time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			"Day window",
+			domain.DefaultDayWindow,
+			time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			"Night window after midnight",
+			night,
+			time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			"Night window before start",
+			night,
+			time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 3, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.window.Bounds(tt.now)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Errorf("Expected [%v, %v], got [%v, %v]", tt.expectedStart, tt.expectedEnd, start, end)
+			}
+		})
+	}
+}
+
+func TestCalculateTakings_CustomWindow(t *testing.T) {
+	schedule := domain.Schedule{
+		Frequency: 4 * time.Hour,
+		Window:    domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+	}
+
+	now := time.Date(2025, 1, 2, 1, 0, 0, 0, time.UTC)
+	takings := schedule.CalculateTakings(now)
+
+	expected := []time.Time{
+		time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC),
+	}
+	if len(takings) != len(expected) {
+		t.Fatalf("Expected %d takings, got %v", len(expected), takings)
+	}
+	for i := range expected {
+		if !takings[i].Equal(expected[i]) {
+			t.Errorf("Expected %v, got %v", expected[i], takings[i])
+		}
+	}
+
+	next, found := schedule.FindNextTaking(now, now.Add(4*time.Hour))
+	if !found || !next.Equal(expected[2]) {
+		t.Errorf("Expected next taking %v, got %v (found=%v)", expected[2], next, found)
+	}
+}
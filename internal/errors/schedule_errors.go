@@ -2,24 +2,68 @@ package myerrors
 
 import (
 	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/ical"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	ErrInvalidUserID     = errors.New("user ID must be positive")
-	ErrInvalidScheduleID = errors.New("schedule ID must be positive")
-	ErrInvalidMedication = errors.New("medication cannot be empty")
-	ErrInvalidTimeRange  = errors.New("wrong start or end time")
-	ErrInvalidTimeWindow = errors.New("medication can only be taken between 9:00 and 22:00")
-	ErrScheduleNotFound  = errors.New("schedule not found")
-	ErrForbidden         = errors.New("schedule does not belong to the user")
-	ErrInvalidRequest    = errors.New("invalid data in request")
-	ErrInvalidFrequency  = errors.New("invalid frequency format")
-	ErrInvalidDuration   = errors.New("invalid duration format")
+	ErrInvalidUserID        = errors.New("user ID must be positive")
+	ErrInvalidScheduleID    = errors.New("schedule ID must be positive")
+	ErrInvalidMedication    = errors.New("medication cannot be empty")
+	ErrInvalidTimeRange     = errors.New("wrong start or end time")
+	ErrInvalidTimeWindow    = errors.New("intake window requires both window_start and window_end in HH:MM format")
+	ErrScheduleNotFound     = errors.New("schedule not found")
+	ErrSettingsNotFound     = errors.New("settings not found")
+	ErrForbidden            = errors.New("schedule does not belong to the user")
+	ErrInvalidRequest       = errors.New("invalid data in request")
+	ErrInvalidFrequency     = errors.New("invalid frequency format")
+	ErrInvalidDuration      = errors.New("invalid duration format")
+	ErrInvalidTimes         = errors.New("times must be a list of HH:MM and cannot be combined with frequency")
+	ErrInvalidRRule         = errors.New("rrule cannot be combined with frequency, times, weekdays or cycle")
+	ErrTakingNotPlanned     = errors.New("planned_at does not match any planned taking of the schedule")
+	ErrUnauthorized         = errors.New("authentication required")
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrAccessDenied         = errors.New("access to another user's data is denied")
+	ErrEmailTaken           = errors.New("email is already registered")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrGrantNotFound        = errors.New("grant not found")
+	ErrMembershipNotFound   = errors.New("membership not found")
+	ErrInvalidInvite        = errors.New("invite is unknown, expired or already used")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrFeedUserID           = errors.New("calendar feed shows only its owner's schedules; user_id is not accepted")
+	// ErrTenantRequired — запрос к расписаниям без организации в контексте.
+	// Это ошибка в коде вызывающего, поэтому отвечаем 500.
+	ErrTenantRequired = errors.New("organization is not set in context")
 )
 
+// PermissionError — роль пользователя не разрешает действие. Ответ 403
+// сообщает роль и недостающее право; errors.Is(err, ErrForbidden) для неё
+// истинно.
+type PermissionError struct {
+	Role       domain.Role
+	Permission domain.Permission
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("role %s is not allowed to %s", e.role(), e.Permission)
+}
+
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// role — имя роли в ответе; у пользователя вне клиники это none.
+func (e *PermissionError) role() string {
+	if e.Role == domain.RoleNone {
+		return "none"
+	}
+	return string(e.Role)
+}
+
 func HandleError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, ErrInvalidUserID),
@@ -29,13 +73,80 @@ func HandleError(c *gin.Context, err error) {
 		errors.Is(err, ErrInvalidRequest),
 		errors.Is(err, ErrInvalidFrequency),
 		errors.Is(err, ErrInvalidDuration),
-		errors.Is(err, ErrInvalidTimeWindow):
+		errors.Is(err, ErrInvalidTimes),
+		errors.Is(err, ErrInvalidRRule),
+		errors.Is(err, ErrTakingNotPlanned),
+		errors.Is(err, ErrInvalidInvite),
+		errors.Is(err, ErrFeedUserID),
+		errors.Is(err, ical.ErrUnsupportedRRule),
+		errors.Is(err, ErrInvalidTimeWindow),
+		errors.Is(err, domain.ErrInvalidFrequency),
+		errors.Is(err, domain.ErrInvalidDuration),
+		errors.Is(err, domain.ErrInvalidTimezone),
+		errors.Is(err, domain.ErrInvalidWindow),
+		errors.Is(err, domain.ErrInvalidClock),
+		errors.Is(err, domain.ErrInvalidKind),
+		errors.Is(err, domain.ErrInvalidTimes),
+		errors.Is(err, domain.ErrInvalidWeekdays),
+		errors.Is(err, domain.ErrInvalidCycle),
+		errors.Is(err, domain.ErrInvalidDosage),
+		errors.Is(err, domain.ErrInvalidPhase),
+		errors.Is(err, domain.ErrPhaseOverlap),
+		errors.Is(err, domain.ErrPhaseGap),
+		errors.Is(err, domain.ErrInvalidAsNeeded),
+		errors.Is(err, domain.ErrNotAsNeeded),
+		errors.Is(err, domain.ErrAsNeededStatus),
+		errors.Is(err, domain.ErrInvalidEvents),
+		errors.Is(err, domain.ErrInvalidAnchors),
+		errors.Is(err, domain.ErrInvalidRounding),
+		errors.Is(err, domain.ErrInvalidDoseStatus),
+		errors.Is(err, domain.ErrSkipReason),
+		errors.Is(err, domain.ErrInvalidSnooze),
+		errors.Is(err, domain.ErrInvalidTakenAt),
+		errors.Is(err, domain.ErrInvalidGrouping),
+		errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrInvalidPassword),
+		errors.Is(err, domain.ErrInvalidAccess),
+		errors.Is(err, domain.ErrSelfGrant),
+		errors.Is(err, domain.ErrInvalidRole),
+		errors.Is(err, domain.ErrOwnMembership):
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case errors.Is(err, ErrScheduleNotFound):
+	case errors.Is(err, ErrScheduleNotFound),
+		errors.Is(err, ErrSettingsNotFound),
+		errors.Is(err, ErrUserNotFound),
+		errors.Is(err, ErrGrantNotFound),
+		errors.Is(err, ErrMembershipNotFound),
+		errors.Is(err, ErrCalendarFeedNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	case errors.Is(err, ErrForbidden):
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrUnauthorized),
+		errors.Is(err, ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden),
+		errors.Is(err, ErrAccessDenied):
+		c.JSON(http.StatusForbidden, forbiddenResponse(err))
+	case errors.Is(err, ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 	}
 }
+
+// forbiddenResponse объясняет отказ: reason — не тот владелец расписания
+// (not_owner), нет доступа к данным пациента (access_denied) или роли не
+// хватает права (permission_denied).
+func forbiddenResponse(err error) gin.H {
+	var permErr *PermissionError
+	switch {
+	case errors.As(err, &permErr):
+		return gin.H{
+			"error":      err.Error(),
+			"reason":     "permission_denied",
+			"role":       permErr.role(),
+			"permission": permErr.Permission,
+		}
+	case errors.Is(err, ErrAccessDenied):
+		return gin.H{"error": err.Error(), "reason": "access_denied"}
+	default:
+		return gin.H{"error": err.Error(), "reason": "not_owner"}
+	}
+}
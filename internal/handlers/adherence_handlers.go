@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAdherenceRange — период отчёта, если from не указан.
+const defaultAdherenceRange = 30 * 24 * time.Hour
+
+type AdherenceService interface {
+	GetAdherence(ctx context.Context, userID, scheduleID int, from, to, now time.Time, group domain.Grouping, loc *time.Location) (*domain.AdherenceReport, error)
+}
+
+type AdherenceHandler struct {
+	service AdherenceService
+	logger  *slog.Logger
+}
+
+func NewAdherenceHandler(service AdherenceService, logger *slog.Logger) *AdherenceHandler {
+	return &AdherenceHandler{service: service, logger: logger}
+}
+
+type AdherenceStatsResponse struct {
+	Due            int     `json:"due"`
+	Taken          int     `json:"taken"`
+	Late           int     `json:"late"`
+	Skipped        int     `json:"skipped"`
+	Missed         int     `json:"missed"`
+	Upcoming       int     `json:"upcoming"`
+	AdherencePct   float64 `json:"adherence_pct"`
+	LateMinutes    int     `json:"late_minutes"`
+	AvgLateMinutes float64 `json:"avg_late_minutes"`
+	CurrentStreak  int     `json:"current_streak_days,omitempty"`
+	LongestStreak  int     `json:"longest_streak_days,omitempty"`
+}
+
+type AdherencePeriodResponse struct {
+	Start time.Time              `json:"start"`
+	End   time.Time              `json:"end"`
+	Stats AdherenceStatsResponse `json:"stats"`
+}
+
+type ScheduleAdherenceResponse struct {
+	ScheduleID int                    `json:"schedule_id"`
+	Medication string                 `json:"medication"`
+	Stats      AdherenceStatsResponse `json:"stats"`
+}
+
+type AdherenceResponse struct {
+	UserID    int                         `json:"user_id"`
+	From      time.Time                   `json:"from"`
+	To        time.Time                   `json:"to"`
+	Group     string                      `json:"group"`
+	Summary   AdherenceStatsResponse      `json:"summary"`
+	Periods   []AdherencePeriodResponse   `json:"periods"`
+	Schedules []ScheduleAdherenceResponse `json:"schedules"`
+}
+
+func (h *AdherenceHandler) GetAdherence(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	var scheduleID int
+	if value := c.Query("schedule_id"); value != "" {
+		scheduleID, err = strconv.Atoi(value)
+		if err != nil || scheduleID <= 0 {
+			myerrors.HandleError(c, myerrors.ErrInvalidScheduleID)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	from, to, err := parseTimeRange(c, now.Add(-defaultAdherenceRange), now)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	loc, err := time.LoadLocation(c.DefaultQuery("timezone", "UTC"))
+	if err != nil {
+		myerrors.HandleError(c, domain.ErrInvalidTimezone)
+		return
+	}
+
+	group := domain.Grouping(c.DefaultQuery("group", string(domain.GroupByDay)))
+	report, err := h.service.GetAdherence(c.Request.Context(), userID, scheduleID, from, to, now, group, loc)
+	if err != nil {
+		h.logger.Error("Failed to build adherence report", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := AdherenceResponse{
+		UserID:    userID,
+		From:      report.From,
+		To:        report.To,
+		Group:     string(report.Grouping),
+		Summary:   newAdherenceStatsResponse(report.Summary),
+		Periods:   make([]AdherencePeriodResponse, 0, len(report.Periods)),
+		Schedules: make([]ScheduleAdherenceResponse, 0, len(report.Schedules)),
+	}
+	for _, period := range report.Periods {
+		response.Periods = append(response.Periods, AdherencePeriodResponse{
+			Start: period.Start,
+			End:   period.End,
+			Stats: newAdherenceStatsResponse(period.Stats),
+		})
+	}
+	for _, schedule := range report.Schedules {
+		response.Schedules = append(response.Schedules, ScheduleAdherenceResponse{
+			ScheduleID: schedule.ScheduleID,
+			Medication: schedule.Medication,
+			Stats:      newAdherenceStatsResponse(schedule.Stats),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func newAdherenceStatsResponse(stats domain.AdherenceStats) AdherenceStatsResponse {
+	return AdherenceStatsResponse{
+		Due:            stats.Due,
+		Taken:          stats.Taken,
+		Late:           stats.Late,
+		Skipped:        stats.Skipped,
+		Missed:         stats.Missed,
+		Upcoming:       stats.Upcoming,
+		AdherencePct:   stats.AdherencePct,
+		LateMinutes:    stats.LateMinutes,
+		AvgLateMinutes: stats.AvgLateMinutes,
+		CurrentStreak:  stats.CurrentStreak,
+		LongestStreak:  stats.LongestStreak,
+	}
+}
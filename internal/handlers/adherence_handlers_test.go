@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdherenceService struct {
+	mock.Mock
+}
+
+func (m *MockAdherenceService) GetAdherence(ctx context.Context, userID, scheduleID int, from, to, now time.Time, group domain.Grouping, loc *time.Location) (*domain.AdherenceReport, error) {
+	args := m.Called(ctx, userID, scheduleID, from, to, now, group, loc)
+	return args.Get(0).(*domain.AdherenceReport), args.Error(1)
+}
+
+func TestGetAdherence_Success(t *testing.T) {
+	mockService := new(MockAdherenceService)
+	handler := handlers.NewAdherenceHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/adherence", handler.GetAdherence)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	report := &domain.AdherenceReport{
+		From:     from,
+		To:       to,
+		Grouping: domain.GroupByWeek,
+		Summary:  domain.AdherenceStats{Due: 10, Taken: 9, AdherencePct: 90},
+		Periods:  []domain.AdherencePeriod{{Start: from, End: to}},
+	}
+
+	mockService.On("GetAdherence", mock.Anything, 1, 0, from, to, mock.AnythingOfType("time.Time"),
+		domain.GroupByWeek, mock.AnythingOfType("*time.Location")).Return(report, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/adherence?user_id=1&group=week&from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response handlers.AdherenceResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 90.0, response.Summary.AdherencePct)
+	assert.Len(t, response.Periods, 1)
+	assert.NotNil(t, response.Schedules)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAdherence_InvalidParams(t *testing.T) {
+	handler := handlers.NewAdherenceHandler(new(MockAdherenceService), slog.Default())
+
+	router := setupRouter()
+	router.GET("/adherence", handler.GetAdherence)
+
+	queries := []string{
+		"user_id=0",
+		"user_id=1&from=yesterday",
+		"user_id=1&from=2025-01-08T00:00:00Z&to=2025-01-01T00:00:00Z",
+		"user_id=1&timezone=Mars/Olympus",
+	}
+
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/adherence?"+query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDKey — ключ gin.Context, под которым Authenticate сохраняет ID
+// пользователя из токена.
+const UserIDKey = "userID"
+
+// SessionCookie — cookie с токеном, которую ставит вход. Она нужна
+// клиентам, которые не могут передать заголовок Authorization, например
+// браузерному EventSource.
+const SessionCookie = "session"
+
+type UserService interface {
+	Register(ctx context.Context, email, password, invite string, now time.Time) (*domain.User, error)
+	Login(ctx context.Context, email, password string, now time.Time) (*domain.Session, error)
+}
+
+type TokenVerifier interface {
+	Verify(token string) (auth.Identity, error)
+}
+
+type AuthHandler struct {
+	service UserService
+	logger  *slog.Logger
+}
+
+func NewAuthHandler(service UserService, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{service: service, logger: logger}
+}
+
+type CredentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterRequest — CredentialsRequest с токеном приглашения в клинику;
+// без него учётная запись создаётся в собственной новой организации.
+type RegisterRequest struct {
+	CredentialsRequest
+	Invite string `json:"invite"`
+}
+
+type UserResponse struct {
+	ID             int       `json:"id"`
+	OrganizationID int       `json:"organization_id"`
+	Email          string    `json:"email"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SessionResponse struct {
+	UserID         int       `json:"user_id"`
+	OrganizationID int       `json:"organization_id"`
+	Token          string    `json:"token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	user, err := h.service.Register(c.Request.Context(), req.Email, req.Password, req.Invite, time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to register user", "invited", req.Invite != "", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, UserResponse{
+		ID:             user.ID,
+		OrganizationID: user.OrganizationID,
+		Email:          user.Email,
+		CreatedAt:      user.CreatedAt,
+	})
+}
+
+// Login возвращает токен в ответе и ставит его же в cookie сессии.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	now := time.Now().UTC()
+	session, err := h.service.Login(c.Request.Context(), req.Email, req.Password, now)
+	if err != nil {
+		h.logger.Warn("Failed to log in", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, session.Token, int(session.ExpiresAt.Sub(now).Seconds()), "/", "", c.Request.TLS != nil, true)
+	c.JSON(http.StatusOK, SessionResponse{
+		UserID:         session.UserID,
+		OrganizationID: session.OrganizationID,
+		Token:          session.Token,
+		ExpiresAt:      session.ExpiresAt,
+	})
+}
+
+// Logout удаляет cookie сессии. Выданный токен действует до истечения
+// срока: сервер не хранит сессий.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+	c.Status(http.StatusNoContent)
+}
+
+// Authenticate пропускает запрос только с действующим токеном — в
+// заголовке Authorization: Bearer или в cookie сессии — и сохраняет ID
+// пользователя под UserIDKey, а пользователя и его организацию — в
+// контексте запроса (auth.WithIdentity).
+func Authenticate(tokens TokenVerifier, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := requestToken(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			myerrors.HandleError(c, myerrors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		identity, err := tokens.Verify(token)
+		if err != nil {
+			logger.Debug("Rejected token", "error", err)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			myerrors.HandleError(c, myerrors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Set(UserIDKey, identity.UserID)
+		c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
+
+func requestToken(c *gin.Context) (string, bool) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", false
+		}
+		return token, true
+	}
+
+	token, err := c.Cookie(SessionCookie)
+	return token, err == nil && token != ""
+}
+
+// currentUserID возвращает пользователя из токена.
+func currentUserID(c *gin.Context) (int, error) {
+	userID := c.GetInt(UserIDKey)
+	if userID <= 0 {
+		return 0, myerrors.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// requestUserID возвращает пациента, с чьими данными работает запрос:
+// requested или, если он не указан (0), пользователя из токена. Есть ли
+// у пользователя доступ к данным пациента, проверяет сервис.
+func requestUserID(c *gin.Context, requested int) (int, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return 0, err
+	}
+	if requested < 0 {
+		return 0, myerrors.ErrInvalidUserID
+	}
+	if requested == 0 {
+		return userID, nil
+	}
+	return requested, nil
+}
+
+// queryUserID — requestUserID для необязательного параметра user_id.
+func queryUserID(c *gin.Context) (int, error) {
+	var requested int
+	if value := c.Query("user_id"); value != "" {
+		var err error
+		requested, err = strconv.Atoi(value)
+		if err != nil || requested <= 0 {
+			return 0, myerrors.ErrInvalidUserID
+		}
+	}
+	return requestUserID(c, requested)
+}
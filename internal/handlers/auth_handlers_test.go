@@ -0,0 +1,254 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) Register(ctx context.Context, email, password, invite string, now time.Time) (*domain.User, error) {
+	args := m.Called(ctx, email, password, invite, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserService) Login(ctx context.Context, email, password string, now time.Time) (*domain.Session, error) {
+	args := m.Called(ctx, email, password, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+// stubTokens принимает только перечисленные токены.
+type stubTokens map[string]auth.Identity
+
+func (s stubTokens) Verify(token string) (auth.Identity, error) {
+	if identity, ok := s[token]; ok {
+		return identity, nil
+	}
+	return auth.Identity{}, errors.New("unknown token")
+}
+
+func TestRegister(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		user     *domain.User
+		err      error
+		expected int
+	}{
+		{"Created", &domain.User{ID: 7, OrganizationID: 2, Email: "patient@example.com", CreatedAt: createdAt}, nil, http.StatusCreated},
+		{"Email taken", nil, myerrors.ErrEmailTaken, http.StatusConflict},
+		{"Weak password", nil, domain.ErrInvalidPassword, http.StatusBadRequest},
+		{"Invalid invite", nil, myerrors.ErrInvalidInvite, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			handler := handlers.NewAuthHandler(mockService, slog.Default())
+
+			router := setupRouter()
+			router.POST("/auth/register", handler.Register)
+
+			mockService.On("Register", mock.Anything, "patient@example.com", "correct horse", "invite-token",
+				mock.AnythingOfType("time.Time")).Return(tc.user, tc.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBufferString(
+				`{"email": "patient@example.com", "password": "correct horse", "invite": "invite-token"}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.user != nil {
+				assert.JSONEq(t, `{"id": 7, "organization_id": 2, "email": "patient@example.com",
+					"created_at": "2025-01-01T09:00:00Z"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := handlers.NewAuthHandler(mockService, slog.Default())
+
+		router := setupRouter()
+		router.POST("/auth/login", handler.Login)
+
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		mockService.On("Login", mock.Anything, "patient@example.com", "correct horse", mock.AnythingOfType("time.Time")).
+			Return(&domain.Session{UserID: 7, OrganizationID: 2, Token: "user-7", ExpiresAt: expiresAt}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/auth/login",
+			bytes.NewBufferString(`{"email": "patient@example.com", "password": "correct horse"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response handlers.SessionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, handlers.SessionResponse{UserID: 7, OrganizationID: 2, Token: "user-7", ExpiresAt: expiresAt}, response)
+
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, handlers.SessionCookie, cookies[0].Name)
+			assert.Equal(t, "user-7", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+		}
+	})
+
+	t.Run("Invalid credentials", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := handlers.NewAuthHandler(mockService, slog.Default())
+
+		router := setupRouter()
+		router.POST("/auth/login", handler.Login)
+
+		mockService.On("Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, myerrors.ErrInvalidCredentials)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/auth/login",
+			bytes.NewBufferString(`{"email": "patient@example.com", "password": "wrong"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handlers.Authenticate(stubTokens{"user-7": {UserID: 7, OrganizationID: 2}}, slog.Default()))
+
+	mockService := new(MockScheduleService)
+	asActor := mock.MatchedBy(func(ctx context.Context) bool {
+		userID, ok := auth.UserIDFrom(ctx)
+		organizationID, inOrganization := auth.OrganizationIDFrom(ctx)
+		return ok && userID == 7 && inOrganization && organizationID == 2
+	})
+	mockService.On("GetSchedulesByUserID", asActor, 7).Return([]domain.Schedule{}, nil)
+	mockService.On("GetSchedulesByUserID", asActor, 8).Return([]domain.Schedule(nil), myerrors.ErrAccessDenied)
+	router.GET("/schedules", handlers.New(mockService, slog.Default()).GetSchedules)
+
+	testCases := []struct {
+		name     string
+		url      string
+		header   string
+		cookie   string
+		expected int
+	}{
+		{"No token", "/schedules", "", "", http.StatusUnauthorized},
+		{"Unknown token", "/schedules", "Bearer user-8", "", http.StatusUnauthorized},
+		{"Basic scheme", "/schedules", "Basic dXNlcjpwYXNz", "", http.StatusUnauthorized},
+		{"Bearer token", "/schedules", "Bearer user-7", "", http.StatusOK},
+		{"Session cookie", "/schedules", "", "user-7", http.StatusOK},
+		{"Own user_id", "/schedules?user_id=7", "Bearer user-7", "", http.StatusOK},
+		{"Patient without grant", "/schedules?user_id=8", "Bearer user-7", "", http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tc.url, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: handlers.SessionCookie, Value: tc.cookie})
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+// Пользователь, зарегистрировавшийся без приглашения, сразу ведёт свои
+// расписания: роль создателя организации не отнимает прав пользователя вне
+// клиники.
+func TestRegisteredUserManagesOwnSchedules(t *testing.T) {
+	roles := stubRoles{}
+	users := new(MockUserService)
+	users.On("Register", mock.Anything, "patient@example.com", "correct horse", "", mock.AnythingOfType("time.Time")).
+		Run(func(mock.Arguments) { roles[7] = domain.RoleOwner }).
+		Return(&domain.User{ID: 7, OrganizationID: 2, Email: "patient@example.com"}, nil)
+	users.On("Login", mock.Anything, "patient@example.com", "correct horse", mock.AnythingOfType("time.Time")).
+		Return(&domain.Session{UserID: 7, OrganizationID: 2, Token: "user-7", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	schedules := new(MockScheduleService)
+	schedules.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool { return s.UserID == 7 })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Schedule).ID = 3 }).
+		Return(nil)
+	doses := new(MockDoseService)
+	doses.On("RecordDose", mock.Anything, mock.MatchedBy(func(e *domain.DoseEvent) bool {
+		return e.UserID == 7 && e.ScheduleID == 3
+	})).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authHandler := handlers.NewAuthHandler(users, slog.Default())
+	router.POST("/auth/register", authHandler.Register)
+	router.POST("/auth/login", authHandler.Login)
+	api := router.Group("", handlers.Authenticate(stubTokens{"user-7": {UserID: 7, OrganizationID: 2}}, slog.Default()))
+	api.POST("/schedule", handlers.Require(roles, domain.PermPrescribe, slog.Default()),
+		handlers.New(schedules, slog.Default()).CreateSchedule)
+	api.POST("/doses", handlers.Require(roles, domain.PermRecordDoses, slog.Default()),
+		handlers.NewDoseHandler(doses, slog.Default()).RecordDose)
+
+	steps := []struct {
+		path     string
+		body     string
+		expected int
+	}{
+		{"/auth/register", `{"email": "patient@example.com", "password": "correct horse"}`, http.StatusCreated},
+		{"/auth/login", `{"email": "patient@example.com", "password": "correct horse"}`, http.StatusOK},
+		{"/schedule", `{"medication": "Aspirin", "frequency": "8h", "duration": "24h"}`, http.StatusCreated},
+		{"/doses", `{"schedule_id": 3, "planned_at": "2025-01-01T09:00:00Z", "status": "taken"}`, http.StatusCreated},
+	}
+	for _, step := range steps {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", step.path, bytes.NewBufferString(step.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer user-7")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, step.expected, w.Code, "%s: %s", step.path, w.Body.String())
+	}
+	schedules.AssertExpectations(t)
+	doses.AssertExpectations(t)
+}
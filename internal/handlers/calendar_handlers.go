@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedTokenParam — параметр ссылки подписки на календарь с её токеном.
+const FeedTokenParam = "token"
+
+// CalendarFeedPath — путь календаря, на который выдаются ссылки подписки.
+const CalendarFeedPath = "/schedules.ics"
+
+type FeedResolver interface {
+	ResolveFeed(ctx context.Context, token string) (auth.Identity, error)
+}
+
+type CalendarFeedService interface {
+	FeedResolver
+	IssueFeed(ctx context.Context) (string, error)
+	RevokeFeed(ctx context.Context) error
+}
+
+type CalendarFeedHandler struct {
+	service CalendarFeedService
+	logger  *slog.Logger
+}
+
+func NewCalendarFeedHandler(service CalendarFeedService, logger *slog.Logger) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: service, logger: logger}
+}
+
+// CalendarFeedResponse — ссылка подписки: путь с токеном, который нужно
+// добавить к адресу сервера.
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+// IssueFeed выдаёт новую ссылку подписки; прежняя перестаёт действовать.
+func (h *CalendarFeedHandler) IssueFeed(c *gin.Context) {
+	token, err := h.service.IssueFeed(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to issue calendar feed", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CalendarFeedResponse{
+		Token: token,
+		Path:  CalendarFeedPath + "?" + url.Values{FeedTokenParam: {token}}.Encode(),
+	})
+}
+
+func (h *CalendarFeedHandler) RevokeFeed(c *gin.Context) {
+	if err := h.service.RevokeFeed(c.Request.Context()); err != nil {
+		h.logger.Error("Failed to revoke calendar feed", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AuthenticateFeed — Authenticate для подписки на календарь: календарные
+// приложения не передают заголовок Authorization, поэтому пользователь
+// определяется по токену подписки в параметре FeedTokenParam. Подписка
+// показывает только расписания того, кто её выдал: параметр user_id
+// отклоняется, чтобы утёкшая ссылка сотрудника не открывала данные других
+// пациентов.
+func AuthenticateFeed(feeds FeedResolver, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.GetQuery("user_id"); ok {
+			myerrors.HandleError(c, myerrors.ErrFeedUserID)
+			c.Abort()
+			return
+		}
+
+		identity, err := feeds.ResolveFeed(c.Request.Context(), c.Query(FeedTokenParam))
+		if err != nil {
+			if errors.Is(err, myerrors.ErrCalendarFeedNotFound) {
+				logger.Debug("Rejected calendar feed token")
+				err = myerrors.ErrUnauthorized
+			} else {
+				logger.Error("Failed to resolve calendar feed", "error", err)
+			}
+			myerrors.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(UserIDKey, identity.UserID)
+		c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
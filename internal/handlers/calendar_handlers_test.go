@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCalendarFeedService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedService) ResolveFeed(ctx context.Context, token string) (auth.Identity, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(auth.Identity), args.Error(1)
+}
+
+func (m *MockCalendarFeedService) IssueFeed(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarFeedService) RevokeFeed(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func TestIssueCalendarFeed(t *testing.T) {
+	mockService := new(MockCalendarFeedService)
+	handler := handlers.NewCalendarFeedHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/calendar/feed", handler.IssueFeed)
+
+	mockService.On("IssueFeed", mock.Anything).Return("abc-123_x", nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/calendar/feed", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response handlers.CalendarFeedResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "abc-123_x", response.Token)
+	assert.Equal(t, "/schedules.ics?token=abc-123_x", response.Path)
+}
+
+func TestAuthenticateFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCalendarFeedService)
+	mockService.On("ResolveFeed", mock.Anything, "valid").
+		Return(auth.Identity{UserID: 2, OrganizationID: 5}, nil)
+	mockService.On("ResolveFeed", mock.Anything, "revoked").
+		Return(auth.Identity{}, myerrors.ErrCalendarFeedNotFound)
+
+	router := gin.New()
+	router.GET("/schedules.ics", handlers.AuthenticateFeed(mockService, slog.Default()), func(c *gin.Context) {
+		organizationID, _ := auth.OrganizationIDFrom(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt(handlers.UserIDKey), "organization_id": organizationID})
+	})
+
+	t.Run("Valid token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/schedules.ics?token=valid", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": 2, "organization_id": 5}`, w.Body.String())
+	})
+
+	t.Run("Revoked token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/schedules.ics?token=revoked", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Another patient", func(t *testing.T) {
+		for _, url := range []string{"/schedules.ics?token=valid&user_id=3", "/schedules.ics?token=valid&user_id=2"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", url, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, url)
+			assert.Contains(t, w.Body.String(), "user_id is not accepted")
+		}
+	})
+
+	t.Run("Bearer token is not enough", func(t *testing.T) {
+		mockService.On("ResolveFeed", mock.Anything, "").
+			Return(auth.Identity{}, myerrors.ErrCalendarFeedNotFound)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/schedules.ics", nil)
+		req.Header.Set("Authorization", "Bearer jwt")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultDosesLookback — за какой период отдаются отметки, если from не указан.
+const defaultDosesLookback = 7 * 24 * time.Hour
+
+type DoseService interface {
+	RecordDose(ctx context.Context, event *domain.DoseEvent) error
+	GetDoses(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error)
+	CheckIntake(ctx context.Context, userID, scheduleID int, now time.Time) (domain.IntakeDecision, error)
+}
+
+type DoseHandler struct {
+	service DoseService
+	logger  *slog.Logger
+}
+
+func NewDoseHandler(service DoseService, logger *slog.Logger) *DoseHandler {
+	return &DoseHandler{service: service, logger: logger}
+}
+
+type DoseRequest struct {
+	UserID       int        `json:"user_id"`
+	ScheduleID   int        `json:"schedule_id"`
+	PlannedAt    time.Time  `json:"planned_at"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason"`
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+	TakenAt      *time.Time `json:"taken_at"`
+}
+
+type DoseResponse struct {
+	ID           int        `json:"id"`
+	ScheduleID   int        `json:"schedule_id"`
+	PlannedAt    time.Time  `json:"planned_at"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason,omitempty"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	TakenAt      *time.Time `json:"taken_at,omitempty"`
+	RecordedAt   time.Time  `json:"recorded_at"`
+}
+
+func (h *DoseHandler) RecordDose(c *gin.Context) {
+	var req DoseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	userID, err := requestUserID(c, req.UserID)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+	req.UserID = userID
+
+	if req.ScheduleID <= 0 {
+		myerrors.HandleError(c, myerrors.ErrInvalidScheduleID)
+		return
+	}
+
+	event := &domain.DoseEvent{
+		UserID:     req.UserID,
+		ScheduleID: req.ScheduleID,
+		PlannedAt:  req.PlannedAt,
+		Status:     domain.DoseStatus(req.Status),
+		Reason:     req.Reason,
+	}
+	if req.SnoozedUntil != nil {
+		event.SnoozedUntil = *req.SnoozedUntil
+	}
+	if req.TakenAt != nil {
+		event.TakenAt = *req.TakenAt
+	}
+
+	if err := h.service.RecordDose(c.Request.Context(), event); err != nil {
+		h.logger.Error("Failed to record dose", "userID", req.UserID, "scheduleID", req.ScheduleID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newDoseResponse(*event))
+}
+
+func (h *DoseHandler) GetDoses(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	var scheduleID int
+	if value := c.Query("schedule_id"); value != "" {
+		scheduleID, err = strconv.Atoi(value)
+		if err != nil || scheduleID <= 0 {
+			myerrors.HandleError(c, myerrors.ErrInvalidScheduleID)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	from, to, err := parseTimeRange(c, now.Add(-defaultDosesLookback), now.Add(24*time.Hour))
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	events, err := h.service.GetDoses(c.Request.Context(), userID, scheduleID, from, to)
+	if err != nil {
+		h.logger.Error("Failed to fetch doses", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]DoseResponse, 0, len(events))
+	for _, event := range events {
+		response = append(response, newDoseResponse(event))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// IntakeResponse — можно ли принять дозу по требованию сейчас; earliest_at
+// отсутствует, если расписание больше не действует.
+type IntakeResponse struct {
+	Allowed      bool       `json:"allowed"`
+	EarliestAt   *time.Time `json:"earliest_at,omitempty"`
+	TakenLast24h int        `json:"taken_last_24h"`
+}
+
+func (h *DoseHandler) CheckIntake(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	decision, err := h.service.CheckIntake(c.Request.Context(), userID, scheduleID, time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to check intake", "userID", userID, "scheduleID", scheduleID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := IntakeResponse{Allowed: decision.Allowed, TakenLast24h: decision.Taken}
+	if !decision.EarliestAt.IsZero() {
+		response.EarliestAt = &decision.EarliestAt
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func newDoseResponse(event domain.DoseEvent) DoseResponse {
+	response := DoseResponse{
+		ID:         event.ID,
+		ScheduleID: event.ScheduleID,
+		PlannedAt:  event.PlannedAt,
+		Status:     string(event.Status),
+		Reason:     event.Reason,
+		RecordedAt: event.RecordedAt,
+	}
+	if !event.SnoozedUntil.IsZero() {
+		response.SnoozedUntil = &event.SnoozedUntil
+	}
+	if !event.TakenAt.IsZero() {
+		response.TakenAt = &event.TakenAt
+	}
+	return response
+}
+
+// parseTimeRange читает необязательные параметры from и to в формате RFC 3339.
+func parseTimeRange(c *gin.Context, defaultFrom, defaultTo time.Time) (time.Time, time.Time, error) {
+	from, to := defaultFrom, defaultTo
+	if value := c.Query("from"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, myerrors.ErrInvalidTimeRange
+		}
+		from = t
+	}
+	if value := c.Query("to"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, myerrors.ErrInvalidTimeRange
+		}
+		to = t
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, myerrors.ErrInvalidTimeRange
+	}
+	return from, to, nil
+}
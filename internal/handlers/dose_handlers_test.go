@@ -0,0 +1,204 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDoseService struct {
+	mock.Mock
+}
+
+func (m *MockDoseService) RecordDose(ctx context.Context, event *domain.DoseEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockDoseService) GetDoses(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error) {
+	args := m.Called(ctx, userID, scheduleID, from, to)
+	return args.Get(0).([]domain.DoseEvent), args.Error(1)
+}
+
+func (m *MockDoseService) CheckIntake(ctx context.Context, userID, scheduleID int, now time.Time) (domain.IntakeDecision, error) {
+	args := m.Called(ctx, userID, scheduleID, now)
+	return args.Get(0).(domain.IntakeDecision), args.Error(1)
+}
+
+func TestRecordDose_Success(t *testing.T) {
+	mockService := new(MockDoseService)
+	handler := handlers.NewDoseHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/doses", handler.RecordDose)
+
+	planned := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockService.On("RecordDose", mock.Anything, mock.MatchedBy(func(e *domain.DoseEvent) bool {
+		return e.UserID == 1 && e.ScheduleID == 2 && e.PlannedAt.Equal(planned) &&
+			e.Status == domain.DoseSkipped && e.Reason == "nausea"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.DoseEvent).ID = 10
+	}).Return(nil)
+
+	body := `{"user_id": 1, "schedule_id": 2, "planned_at": "2025-01-01T09:00:00Z", "status": "skipped", "reason": "nausea"}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/doses", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response handlers.DoseResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 10, response.ID)
+	assert.Equal(t, "skipped", response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestRecordDose_Errors(t *testing.T) {
+	mockService := new(MockDoseService)
+	handler := handlers.NewDoseHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/doses", handler.RecordDose)
+
+	mockService.On("RecordDose", mock.Anything, mock.Anything).Return(myerrors.ErrTakingNotPlanned)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{
+			name:     "Invalid planned_at",
+			body:     `{"user_id": 1, "schedule_id": 2, "planned_at": "tomorrow", "status": "taken"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Missing schedule_id",
+			body:     `{"user_id": 1, "planned_at": "2025-01-01T09:00:00Z", "status": "taken"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Not planned",
+			body:     `{"user_id": 1, "schedule_id": 2, "planned_at": "2025-01-01T09:05:00Z", "status": "taken"}`,
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/doses", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+func TestGetDoses_Success(t *testing.T) {
+	mockService := new(MockDoseService)
+	handler := handlers.NewDoseHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/doses", handler.GetDoses)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	events := []domain.DoseEvent{
+		{ID: 1, ScheduleID: 2, PlannedAt: from.Add(9 * time.Hour), Status: domain.DoseTaken, TakenAt: from.Add(9 * time.Hour)},
+	}
+
+	mockService.On("GetDoses", mock.Anything, 1, 2, from, to).Return(events, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/doses?user_id=1&schedule_id=2&from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []handlers.DoseResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.NotNil(t, response[0].TakenAt)
+	assert.Nil(t, response[0].SnoozedUntil)
+	mockService.AssertExpectations(t)
+}
+
+func TestCheckIntake(t *testing.T) {
+	earliest := time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    string
+		decision domain.IntakeDecision
+		err      error
+		expected int
+		body     string
+	}{
+		{
+			name:     "Allowed",
+			query:    "user_id=1&schedule_id=2",
+			decision: domain.IntakeDecision{Allowed: true, EarliestAt: earliest, Taken: 1},
+			expected: http.StatusOK,
+			body:     `{"allowed":true,"earliest_at":"2025-01-01T16:00:00Z","taken_last_24h":1}`,
+		},
+		{
+			name:     "Schedule ended",
+			query:    "user_id=1&schedule_id=2",
+			decision: domain.IntakeDecision{Taken: 4},
+			expected: http.StatusOK,
+			body:     `{"allowed":false,"taken_last_24h":4}`,
+		},
+		{
+			name:     "Not as-needed",
+			query:    "user_id=1&schedule_id=2",
+			err:      domain.ErrNotAsNeeded,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid schedule_id",
+			query:    "user_id=1&schedule_id=x",
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockDoseService)
+			handler := handlers.NewDoseHandler(mockService, slog.Default())
+
+			router := setupRouter()
+			router.GET("/doses/can_take", handler.CheckIntake)
+
+			mockService.On("CheckIntake", mock.Anything, 1, 2, mock.AnythingOfType("time.Time")).Return(tc.decision, tc.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/doses/can_take?"+tc.query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
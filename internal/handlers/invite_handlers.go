@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InviteService interface {
+	CreateInvite(ctx context.Context, now time.Time) (*domain.Invite, string, error)
+}
+
+type InviteHandler struct {
+	service InviteService
+	logger  *slog.Logger
+}
+
+func NewInviteHandler(service InviteService, logger *slog.Logger) *InviteHandler {
+	return &InviteHandler{service: service, logger: logger}
+}
+
+// InviteResponse — токен приглашения, который передаётся в поле invite
+// при регистрации. Показывается один раз: сервер хранит только его хеш.
+type InviteResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (h *InviteHandler) CreateInvite(c *gin.Context) {
+	invite, token, err := h.service.CreateInvite(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to create invite", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, InviteResponse{Token: token, ExpiresAt: invite.ExpiresAt})
+}
@@ -0,0 +1,46 @@
+package handlers_test
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInviteService struct {
+	mock.Mock
+}
+
+func (m *MockInviteService) CreateInvite(ctx context.Context, now time.Time) (*domain.Invite, string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*domain.Invite), args.String(1), args.Error(2)
+}
+
+func TestCreateInvite(t *testing.T) {
+	mockService := new(MockInviteService)
+	handler := handlers.NewInviteHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/invites", handler.CreateInvite)
+
+	expiresAt := time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC)
+	mockService.On("CreateInvite", mock.Anything, mock.AnythingOfType("time.Time")).
+		Return(&domain.Invite{ID: 4, OrganizationID: 1, CreatedBy: 1, ExpiresAt: expiresAt}, "invite-token", nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/invites", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"token": "invite-token", "expires_at": "2025-01-08T09:00:00Z"}`, w.Body.String())
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleLookup interface {
+	Role(ctx context.Context, userID int) (domain.Role, error)
+}
+
+type MembershipService interface {
+	RoleLookup
+	List(ctx context.Context) ([]domain.Membership, error)
+	Assign(ctx context.Context, email string, role domain.Role) (*domain.Membership, error)
+	Remove(ctx context.Context, userID int) error
+}
+
+// Require пропускает запрос, только если роль пользователя разрешает
+// действие perm; иначе отвечает 403 с ролью и недостающим правом. Ставится
+// после Authenticate.
+func Require(roles RoleLookup, perm domain.Permission, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := currentUserID(c)
+		if err != nil {
+			myerrors.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		role, err := roles.Role(c.Request.Context(), userID)
+		if err != nil {
+			logger.Error("Failed to get role", "userID", userID, "error", err)
+			myerrors.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if !role.Can(perm) {
+			logger.Warn("Permission denied", "userID", userID, "role", role, "permission", perm)
+			myerrors.HandleError(c, &myerrors.PermissionError{Role: role, Permission: perm})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type MembershipHandler struct {
+	service MembershipService
+	logger  *slog.Logger
+}
+
+func NewMembershipHandler(service MembershipService, logger *slog.Logger) *MembershipHandler {
+	return &MembershipHandler{service: service, logger: logger}
+}
+
+// MembershipRequest назначает роль учётной записи с адресом email.
+type MembershipRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type MembershipResponse struct {
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (h *MembershipHandler) GetMembers(c *gin.Context) {
+	memberships, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list memberships", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]MembershipResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		response = append(response, newMembershipResponse(membership))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *MembershipHandler) AssignMember(c *gin.Context) {
+	var req MembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	membership, err := h.service.Assign(c.Request.Context(), req.Email, domain.Role(req.Role))
+	if err != nil {
+		h.logger.Error("Failed to assign role", "role", req.Role, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newMembershipResponse(*membership))
+}
+
+// RemoveMember исключает пользователя user_id из клиники.
+func (h *MembershipHandler) RemoveMember(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Query("user_id"))
+	if err != nil || userID <= 0 {
+		myerrors.HandleError(c, myerrors.ErrInvalidUserID)
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), userID); err != nil {
+		h.logger.Error("Failed to remove membership", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newMembershipResponse(membership domain.Membership) MembershipResponse {
+	return MembershipResponse{
+		UserID:    membership.UserID,
+		Email:     membership.Email,
+		Role:      string(membership.Role),
+		CreatedAt: membership.CreatedAt,
+		UpdatedAt: membership.UpdatedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubRoles — роли пользователей; отсутствующие вне клиники.
+type stubRoles map[int]domain.Role
+
+func (s stubRoles) Role(_ context.Context, userID int) (domain.Role, error) {
+	return s[userID], nil
+}
+
+type MockMembershipService struct {
+	mock.Mock
+}
+
+func (m *MockMembershipService) Role(ctx context.Context, userID int) (domain.Role, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Role), args.Error(1)
+}
+
+func (m *MockMembershipService) List(ctx context.Context) ([]domain.Membership, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Membership), args.Error(1)
+}
+
+func (m *MockMembershipService) Assign(ctx context.Context, email string, role domain.Role) (*domain.Membership, error) {
+	args := m.Called(ctx, email, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Membership), args.Error(1)
+}
+
+func (m *MockMembershipService) Remove(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func TestRequire(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	roles := stubRoles{1: domain.RolePatient, 2: domain.RoleNurse, 3: domain.RoleDoctor}
+
+	testCases := []struct {
+		name     string
+		userID   int
+		perm     domain.Permission
+		expected int
+	}{
+		{"Doctor prescribes", 3, domain.PermPrescribe, http.StatusOK},
+		{"Nurse records", 2, domain.PermRecordDoses, http.StatusOK},
+		{"Nurse prescribes", 2, domain.PermPrescribe, http.StatusForbidden},
+		{"Patient views", 1, domain.PermView, http.StatusOK},
+		{"Patient records", 1, domain.PermRecordDoses, http.StatusForbidden},
+		{"Outside clinic", 4, domain.PermPrescribe, http.StatusOK},
+		{"Outside clinic manages members", 4, domain.PermManageMembers, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set(handlers.UserIDKey, tc.userID) })
+			router.GET("/action", handlers.Require(roles, tc.perm, slog.Default()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/action", nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected != http.StatusForbidden {
+				return
+			}
+
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "permission_denied", response["reason"])
+			assert.Equal(t, string(tc.perm), response["permission"])
+			if role := roles[tc.userID]; role != domain.RoleNone {
+				assert.Equal(t, string(role), response["role"])
+			} else {
+				assert.Equal(t, "none", response["role"])
+			}
+		})
+	}
+}
+
+func TestAssignMember(t *testing.T) {
+	mockService := new(MockMembershipService)
+	handler := handlers.NewMembershipHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.PUT("/members", handler.AssignMember)
+
+	mockService.On("Assign", mock.Anything, "nurse@example.com", domain.RoleNurse).
+		Return(&domain.Membership{UserID: 2, Email: "nurse@example.com", Role: domain.RoleNurse}, nil)
+	mockService.On("Assign", mock.Anything, "nurse@example.com", domain.Role("owner")).
+		Return(nil, domain.ErrInvalidRole)
+	mockService.On("Assign", mock.Anything, "admin@example.com", domain.RoleDoctor).
+		Return(nil, domain.ErrOwnMembership)
+	mockService.On("Assign", mock.Anything, "nobody@example.com", domain.RoleNurse).
+		Return(nil, myerrors.ErrUserNotFound)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"Success", `{"email": "nurse@example.com", "role": "nurse"}`, http.StatusOK},
+		{"Invalid role", `{"email": "nurse@example.com", "role": "owner"}`, http.StatusBadRequest},
+		{"Own membership", `{"email": "admin@example.com", "role": "doctor"}`, http.StatusBadRequest},
+		{"Unknown email", `{"email": "nobody@example.com", "role": "nurse"}`, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/members", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+func TestGetAndRemoveMembers(t *testing.T) {
+	mockService := new(MockMembershipService)
+	handler := handlers.NewMembershipHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/members", handler.GetMembers)
+	router.DELETE("/members", handler.RemoveMember)
+
+	mockService.On("List", mock.Anything).Return([]domain.Membership{
+		{UserID: 1, Email: "admin@example.com", Role: domain.RoleAdmin},
+		{UserID: 2, Email: "nurse@example.com", Role: domain.RoleNurse},
+	}, nil)
+	mockService.On("Remove", mock.Anything, 2).Return(nil)
+	mockService.On("Remove", mock.Anything, 3).Return(myerrors.ErrMembershipNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/members", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []handlers.MembershipResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, "nurse", response[1].Role)
+	}
+
+	for query, expected := range map[string]int{
+		"user_id=2": http.StatusNoContent,
+		"user_id=3": http.StatusNotFound,
+		"user_id=x": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/members?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, query)
+	}
+}
@@ -43,9 +43,51 @@ func (m *MockScheduleService) GetNextTakings(ctx context.Context, userID int, no
 	return args.Get(0).([]domain.Schedule), args.Error(1)
 }
 
+func (m *MockScheduleService) GetHouseholdTakings(ctx context.Context, userIDs []int, now time.Time) ([]domain.PersonTakings, error) {
+	args := m.Called(ctx, userIDs, now)
+	return args.Get(0).([]domain.PersonTakings), args.Error(1)
+}
+
+func (m *MockScheduleService) GetTakings(ctx context.Context, userID int, from, to time.Time) ([]domain.Occurrence, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]domain.Occurrence), args.Error(1)
+}
+
+func (m *MockScheduleService) UpdateSchedule(ctx context.Context, userID, scheduleID int, patch domain.SchedulePatch) (*domain.Schedule, error) {
+	args := m.Called(ctx, userID, scheduleID, patch)
+	schedule, _ := args.Get(0).(*domain.Schedule)
+	return schedule, args.Error(1)
+}
+
+func (m *MockScheduleService) DeleteSchedule(ctx context.Context, userID, scheduleID int) error {
+	args := m.Called(ctx, userID, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleService) PauseSchedule(ctx context.Context, userID, scheduleID int, now time.Time) (*domain.Schedule, error) {
+	args := m.Called(ctx, userID, scheduleID, now)
+	schedule, _ := args.Get(0).(*domain.Schedule)
+	return schedule, args.Error(1)
+}
+
+func (m *MockScheduleService) ResumeSchedule(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error) {
+	args := m.Called(ctx, userID, scheduleID)
+	schedule, _ := args.Get(0).(*domain.Schedule)
+	return schedule, args.Error(1)
+}
+
+func (m *MockScheduleService) GetScheduleHistory(ctx context.Context, userID, scheduleID int) ([]domain.ScheduleRevision, error) {
+	args := m.Called(ctx, userID, scheduleID)
+	history, _ := args.Get(0).([]domain.ScheduleRevision)
+	return history, args.Error(1)
+}
+
+// setupRouter возвращает роутер, в котором запросы выполняются от имени
+// пользователя 1, как после Authenticate.
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(handlers.UserIDKey, 1) })
 	return r
 }
 
@@ -100,6 +142,258 @@ func TestCreateSchedule_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateSchedule_FixedTimes(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Kind == domain.KindFixedTimes &&
+			s.Frequency == 0 &&
+			assert.ObjectsAreEqual([]time.Duration{8 * time.Hour, 21 * time.Hour}, s.TimesOfDay)
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Aspirin",
+		"times": ["08:00", "21:00"],
+		"duration": "0s"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_RRule(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Kind == domain.KindFixedTimes &&
+			s.Weekdays == domain.NewWeekdays(time.Monday, time.Friday) &&
+			assert.ObjectsAreEqual([]time.Duration{9 * time.Hour}, s.TimesOfDay)
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Aspirin",
+		"rrule": "FREQ=WEEKLY;BYDAY=MO,FR;BYHOUR=9"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_StartAndEnd(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	start := time.Date(2099, 1, 1, 8, 0, 0, 0, time.UTC)
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.StartTime.Equal(start) && s.Duration == 7*24*time.Hour
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Aspirin",
+		"frequency": "8h",
+		"start_time": "2099-01-01T11:00:00+03:00",
+		"end_time": "2099-01-08T08:00:00Z"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_Dosage(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	expected := domain.Dosage{Amount: 500, Unit: domain.UnitMg, Form: domain.FormTablet, Route: domain.RouteOral}
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Dosage == expected
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Paracetamol",
+		"dose": {"amount": 500, "unit": "mg", "form": "tablet", "route": "oral"},
+		"frequency": "6h",
+		"duration": "72h"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_Phases(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	expected := []domain.Phase{
+		{
+			Duration: 72 * time.Hour,
+			Dosage:   domain.Dosage{Amount: 40, Unit: domain.UnitMg},
+			Regimen:  domain.Regimen{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{8 * time.Hour, 20 * time.Hour}},
+		},
+		{
+			Offset:   72 * time.Hour,
+			Duration: 72 * time.Hour,
+			Dosage:   domain.Dosage{Amount: 20, Unit: domain.UnitMg},
+			Regimen:  domain.Regimen{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{8 * time.Hour}},
+		},
+	}
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Duration == 144*time.Hour &&
+			s.Kind == domain.KindFixedTimes &&
+			assert.ObjectsAreEqual(expected, s.Phases)
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Prednisolone",
+		"phases": [
+			{"duration": "72h", "dose": {"amount": 40, "unit": "mg"}, "times": ["08:00", "20:00"]},
+			{"duration": "72h", "dose": {"amount": 20, "unit": "mg"}, "times": ["08:00"]}
+		]
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_AsNeeded(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Kind == domain.KindAsNeeded && s.MinInterval == 6*time.Hour && s.MaxPerDay == 4 && s.Frequency == 0
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Ibuprofen",
+		"as_needed": {"min_interval": "6h", "max_per_day": 4},
+		"duration": "0s"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_EventRelative(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Kind == domain.KindEventRelative && assert.ObjectsAreEqual([]domain.Anchor{
+			{Event: domain.EventBreakfast, Offset: -30 * time.Minute},
+			{Event: domain.EventBedtime},
+		}, s.Anchors)
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Omeprazole",
+		"anchors": [{"event": "breakfast", "offset": "-30m"}, {"event": "bedtime"}],
+		"duration": "0s"
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_Rounding(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedules", handler.CreateSchedule)
+
+	mockService.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.Rounding == domain.Rounding{Mode: domain.RoundDown, Step: 10 * time.Minute}
+	})).Return(nil)
+
+	reqBody := `{
+		"user_id": 1,
+		"medication": "Amoxicillin",
+		"frequency": "8h",
+		"duration": "168h",
+		"rounding": {"mode": "down", "step": "10m"}
+	}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestCreateSchedule_InvalidRequest(t *testing.T) {
 	mockService := new(MockScheduleService)
 	logger := slog.Default()
@@ -128,6 +422,76 @@ func TestCreateSchedule_InvalidRequest(t *testing.T) {
 			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "duration": "invalid"}`,
 			expected: http.StatusBadRequest,
 		},
+		{
+			name:     "Times with frequency",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "times": ["08:00"], "duration": "24h"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid times",
+			body:     `{"user_id": 1, "medication": "Aspirin", "times": ["8 am"], "duration": "24h"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Unsupported rrule",
+			body:     `{"user_id": 1, "medication": "Aspirin", "rrule": "FREQ=MONTHLY;BYMONTHDAY=1"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Rrule with frequency",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "rrule": "FREQ=HOURLY"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid weekdays",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "duration": "24h", "weekdays": ["monday"]}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid window",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "duration": "24h", "window_start": "9:00", "window_end": "21:00"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "End before start",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "start_time": "2099-01-02T00:00:00Z", "end_time": "2099-01-01T00:00:00Z"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "End in the past",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "start_time": "2020-01-01T00:00:00Z", "end_time": "2020-01-02T00:00:00Z"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid phase duration",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "phases": [{"duration": "three days"}]}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid rounding",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "duration": "24h", "rounding": {"mode": "ceil", "step": "15m"}}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Anchors with times",
+			body:     `{"user_id": 1, "medication": "Omeprazole", "times": ["08:00"], "duration": "0s", "anchors": [{"event": "breakfast"}]}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid anchor offset",
+			body:     `{"user_id": 1, "medication": "Omeprazole", "duration": "0s", "anchors": [{"event": "breakfast", "offset": "half an hour"}]}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "As-needed with frequency",
+			body:     `{"user_id": 1, "medication": "Ibuprofen", "frequency": "6h", "duration": "0s", "as_needed": {"min_interval": "6h"}}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "End contradicts duration",
+			body:     `{"user_id": 1, "medication": "Aspirin", "frequency": "1h", "duration": "48h", "start_time": "2099-01-01T00:00:00Z", "end_time": "2099-01-02T00:00:00Z"}`,
+			expected: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -181,15 +545,17 @@ func TestGetSchedules_InvalidUserID(t *testing.T) {
 	router := setupRouter()
 	router.GET("/schedules", handler.GetSchedules)
 
+	mockService.On("GetSchedulesByUserID", mock.Anything, 2).Return([]domain.Schedule(nil), myerrors.ErrAccessDenied)
+
 	testCases := []struct {
 		name     string
 		query    string
 		expected int
 	}{
 		{
-			name:     "Missing user_id",
-			query:    "",
-			expected: http.StatusBadRequest,
+			name:     "Patient without grant",
+			query:    "user_id=2",
+			expected: http.StatusForbidden,
 		},
 		{
 			name:     "Invalid user_id",
@@ -282,8 +648,13 @@ func TestGetNextTakings_Success(t *testing.T) {
 	expectedSchedules := []domain.Schedule{
 		{
 			Medication: "Aspirin",
+			Dosage:     domain.Dosage{Amount: 500, Unit: domain.UnitMg, Form: domain.FormTablet},
 			Takings:    takings,
 		},
+		{
+			Medication: "Vitamin D",
+			Takings:    takings[:1],
+		},
 	}
 
 	mockService.On("GetNextTakings", mock.Anything, 1, mock.AnythingOfType("time.Time")).Return(expectedSchedules, nil)
@@ -299,12 +670,112 @@ func TestGetNextTakings_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Len(t, response, 1)
-	assert.Equal(t, "Aspirin", response[0].Medication)
-	assert.Len(t, response[0].Takings, 2)
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, "Aspirin", response[0].Medication)
+		if assert.Len(t, response[0].Takings, 2) {
+			assert.True(t, takings[0].Equal(response[0].Takings[0].At))
+			assert.Equal(t, &handlers.DosageResponse{Amount: 500, Unit: "mg", Form: "tablet"}, response[0].Takings[1].Dose)
+		}
+		if assert.Len(t, response[1].Takings, 1) {
+			assert.Nil(t, response[1].Takings[0].Dose)
+		}
+	}
 	mockService.AssertExpectations(t)
 }
 
+func TestGetTakings(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/takings", handler.GetTakings)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	mockService.On("GetTakings", mock.Anything, 1, from, to).Return([]domain.Occurrence{
+		{ScheduleID: 3, Medication: "Aspirin", At: from.Add(9 * time.Hour), Dosage: domain.Dosage{Amount: 100, Unit: domain.UnitMg}},
+		{ScheduleID: 4, Medication: "Vitamin D", At: from.Add(9 * time.Hour)},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/takings?user_id=1&from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []handlers.OccurrenceResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, 3, response[0].ScheduleID)
+		assert.Equal(t, "Aspirin", response[0].Medication)
+		assert.Equal(t, &handlers.DosageResponse{Amount: 100, Unit: "mg"}, response[0].Dose)
+		assert.Nil(t, response[1].Dose)
+	}
+	mockService.AssertExpectations(t)
+
+	for _, query := range []string{
+		"/takings?user_id=1",
+		"/takings?user_id=1&from=2025-01-01T00:00:00Z",
+		"/takings?user_id=1&from=2025-01-08T00:00:00Z&to=2025-01-01T00:00:00Z",
+		"/takings?user_id=0&from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", query, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetHouseholdTakings(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/household", handler.GetHouseholdTakings)
+
+	taking := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	people := []domain.PersonTakings{
+		{UserID: 3, Schedules: []domain.Schedule{{Medication: "Insulin", Takings: []time.Time{taking}}}},
+		{UserID: 1},
+	}
+	mockService.On("GetHouseholdTakings", mock.Anything, []int{}, mock.Anything).Return(people, nil)
+	mockService.On("GetHouseholdTakings", mock.Anything, []int{3, 1}, mock.Anything).Return(people, nil)
+	mockService.On("GetHouseholdTakings", mock.Anything, []int{4}, mock.Anything).
+		Return([]domain.PersonTakings(nil), myerrors.ErrAccessDenied)
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{"Everyone", "", http.StatusOK},
+		{"Selected people", "?user_id=3&user_id=1", http.StatusOK},
+		{"Patient without grant", "?user_id=4", http.StatusForbidden},
+		{"Invalid user_id", "?user_id=3&user_id=x", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/household"+tc.query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected != http.StatusOK {
+				return
+			}
+
+			var response []handlers.PersonTakingsResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.Len(t, response, 2) {
+				assert.Equal(t, 3, response[0].UserID)
+				assert.Equal(t, "Insulin", response[0].NextTakings[0].Medication)
+				assert.NotNil(t, response[1].NextTakings)
+			}
+		})
+	}
+}
+
 func TestGetNextTakings_InvalidUserID(t *testing.T) {
 	mockService := new(MockScheduleService)
 	logger := slog.Default()
@@ -340,3 +811,185 @@ func TestGetNextTakings_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestExportCalendar_Success(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/schedules", handler.GetSchedules)
+	router.GET("/schedules.ics", handler.ExportCalendar)
+
+	schedules := []domain.Schedule{
+		{
+			ID:         1,
+			UserID:     1,
+			Medication: "Aspirin",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+		},
+	}
+
+	mockService.On("GetSchedulesByUserID", mock.Anything, 1).Return(schedules, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/schedules.ics", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "BEGIN:VCALENDAR")
+	assert.Contains(t, w.Body.String(), "SUMMARY:Aspirin")
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSchedule_Success(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.PATCH("/schedule", handler.UpdateSchedule)
+
+	mockService.On("UpdateSchedule", mock.Anything, 1, 7, mock.MatchedBy(func(p domain.SchedulePatch) bool {
+		return p.Medication == nil &&
+			p.Regimen != nil && p.Regimen.Kind == domain.KindInterval && p.Regimen.Frequency == 6*time.Hour &&
+			p.Window != nil && p.Window.IsZero() &&
+			p.Weekdays != nil && *p.Weekdays == domain.NewWeekdays(time.Monday)
+	})).Return(&domain.Schedule{ID: 7, UserID: 1}, nil)
+
+	body := `{"frequency": "6h", "window_start": "", "window_end": "", "weekdays": ["mon"]}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/schedule?user_id=1&schedule_id=7", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSchedule_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		body     string
+		err      error
+		expected int
+	}{
+		{"Invalid schedule ID", "user_id=1&schedule_id=x", `{}`, nil, http.StatusBadRequest},
+		{"Half of window", "user_id=1&schedule_id=7", `{"window_start": "09:00"}`, nil, http.StatusBadRequest},
+		{"Times with frequency", "user_id=1&schedule_id=7", `{"frequency": "1h", "times": ["09:00"]}`, nil, http.StatusBadRequest},
+		{"Foreign schedule", "schedule_id=7", `{}`, myerrors.ErrForbidden, http.StatusForbidden},
+		{"Patient without grant", "user_id=2&schedule_id=7", `{}`, myerrors.ErrAccessDenied, http.StatusForbidden},
+		{"Not found", "user_id=1&schedule_id=7", `{}`, myerrors.ErrScheduleNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockScheduleService)
+			handler := handlers.New(mockService, slog.Default())
+
+			router := setupRouter()
+			router.PATCH("/schedule", handler.UpdateSchedule)
+
+			if tc.err != nil {
+				mockService.On("UpdateSchedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, tc.err)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/schedule?"+tc.query, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.DELETE("/schedule", handler.DeleteSchedule)
+
+	mockService.On("DeleteSchedule", mock.Anything, 1, 7).Return(nil)
+	mockService.On("DeleteSchedule", mock.Anything, 1, 8).Return(myerrors.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/schedule?user_id=1&schedule_id=7", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/schedule?schedule_id=8", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPauseAndResumeSchedule(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/schedule/pause", handler.PauseSchedule)
+	router.POST("/schedule/resume", handler.ResumeSchedule)
+
+	pausedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("PauseSchedule", mock.Anything, 1, 7, mock.AnythingOfType("time.Time")).
+		Return(&domain.Schedule{ID: 7, UserID: 1, PausedAt: pausedAt}, nil)
+	mockService.On("ResumeSchedule", mock.Anything, 1, 7).
+		Return(&domain.Schedule{ID: 7, UserID: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedule/pause?user_id=1&schedule_id=7", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var paused domain.Schedule
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &paused))
+	assert.Equal(t, pausedAt, paused.PausedAt)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/schedule/resume?user_id=1&schedule_id=7", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetScheduleHistory(t *testing.T) {
+	mockService := new(MockScheduleService)
+	handler := handlers.New(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/schedule/history", handler.GetScheduleHistory)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	revisedAt := start.AddDate(0, 0, 5)
+	mockService.On("GetScheduleHistory", mock.Anything, 1, 7).Return([]domain.ScheduleRevision{
+		{Number: 1, EffectiveFrom: start, EffectiveTo: revisedAt, Schedule: domain.Schedule{ID: 7, Frequency: time.Hour}},
+		{Number: 2, EffectiveFrom: revisedAt, Schedule: domain.Schedule{ID: 7, Frequency: 2 * time.Hour}},
+	}, nil)
+	mockService.On("GetScheduleHistory", mock.Anything, 1, 8).Return(nil, myerrors.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/schedule/history?user_id=1&schedule_id=7", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, "2025-01-06T00:00:00Z", response[0]["effective_to"])
+	assert.NotContains(t, response[1], "effective_to")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/schedule/history?schedule_id=8", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"log"
 	"log/slog"
 	"medication-scheduler/internal/domain"
 	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/ical"
 
 	"net/http"
 	"strconv"
@@ -19,6 +21,13 @@ type ScheduleService interface {
 	GetSchedulesByUserID(ctx context.Context, userID int) ([]domain.Schedule, error)
 	GetScheduleByIDs(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error)
 	GetNextTakings(ctx context.Context, userID int, now time.Time) ([]domain.Schedule, error)
+	GetHouseholdTakings(ctx context.Context, userIDs []int, now time.Time) ([]domain.PersonTakings, error)
+	GetTakings(ctx context.Context, userID int, from, to time.Time) ([]domain.Occurrence, error)
+	UpdateSchedule(ctx context.Context, userID, scheduleID int, patch domain.SchedulePatch) (*domain.Schedule, error)
+	DeleteSchedule(ctx context.Context, userID, scheduleID int) error
+	PauseSchedule(ctx context.Context, userID, scheduleID int, now time.Time) (*domain.Schedule, error)
+	ResumeSchedule(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error)
+	GetScheduleHistory(ctx context.Context, userID, scheduleID int) ([]domain.ScheduleRevision, error)
 }
 
 type ScheduleHandler struct {
@@ -31,10 +40,91 @@ func New(service ScheduleService, logger *slog.Logger) *ScheduleHandler {
 }
 
 type ScheduleRequest struct {
-	UserID     int    `json:"user_id"`
-	Medication string `json:"medication"`
-	Frequency  string `json:"frequency"`
-	Duration   string `json:"duration"`
+	UserID      int              `json:"user_id"`
+	Medication  string           `json:"medication"`
+	Dose        *DosageRequest   `json:"dose"`
+	Frequency   string           `json:"frequency"`
+	Times       []string         `json:"times"`
+	Duration    string           `json:"duration"`
+	Timezone    string           `json:"timezone"`
+	WindowStart string           `json:"window_start"`
+	WindowEnd   string           `json:"window_end"`
+	Weekdays    []string         `json:"weekdays"`
+	Cycle       *CycleRequest    `json:"cycle"`
+	RRule       string           `json:"rrule"`
+	StartTime   *time.Time       `json:"start_time"`
+	EndTime     *time.Time       `json:"end_time"`
+	Phases      []PhaseRequest   `json:"phases"`
+	AsNeeded    *AsNeededRequest `json:"as_needed"`
+	Anchors     []AnchorRequest  `json:"anchors"`
+	Rounding    *RoundingRequest `json:"rounding"`
+}
+
+// SchedulePatchRequest — изменение расписания; отсутствующие поля не
+// меняются. Пустые window_start и window_end возвращают окно из настроек
+// пользователя.
+type SchedulePatchRequest struct {
+	Medication  *string          `json:"medication"`
+	Dose        *DosageRequest   `json:"dose"`
+	Frequency   *string          `json:"frequency"`
+	Times       []string         `json:"times"`
+	Duration    *string          `json:"duration"`
+	Timezone    *string          `json:"timezone"`
+	WindowStart *string          `json:"window_start"`
+	WindowEnd   *string          `json:"window_end"`
+	Weekdays    *[]string        `json:"weekdays"`
+	Cycle       *CycleRequest    `json:"cycle"`
+	Phases      *[]PhaseRequest  `json:"phases"`
+	AsNeeded    *AsNeededRequest `json:"as_needed"`
+	Anchors     []AnchorRequest  `json:"anchors"`
+	Rounding    *RoundingRequest `json:"rounding"`
+}
+
+// DosageRequest — доза на один приём: 500 mg, tablet, oral.
+type DosageRequest struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+	Form   string  `json:"form"`
+	Route  string  `json:"route"`
+}
+
+// PhaseRequest — этап ступенчатого расписания. Без offset этап начинается
+// там, где закончился предыдущий; пустая duration у последнего этапа —
+// этап длится до конца расписания. Без frequency/times и dose действуют
+// режим и доза расписания.
+type PhaseRequest struct {
+	Offset    string         `json:"offset"`
+	Duration  string         `json:"duration"`
+	Dose      *DosageRequest `json:"dose"`
+	Frequency string         `json:"frequency"`
+	Times     []string       `json:"times"`
+}
+
+// AsNeededRequest — приём по требованию: не чаще min_interval и не больше
+// max_per_day раз за сутки (0 — без ограничения).
+type AsNeededRequest struct {
+	MinInterval string `json:"min_interval"`
+	MaxPerDay   int    `json:"max_per_day"`
+}
+
+// AnchorRequest — приём относительно события дня (breakfast, lunch, dinner,
+// bedtime): offset "-30m" — за полчаса до события, "1h" — через час после.
+type AnchorRequest struct {
+	Event  string `json:"event"`
+	Offset string `json:"offset"`
+}
+
+// RoundingRequest — округление приёмов интервального расписания: mode
+// none, nearest, up или down и шаг сетки step ("15m"). Без rounding
+// действует политика развёртывания.
+type RoundingRequest struct {
+	Mode string `json:"mode"`
+	Step string `json:"step"`
+}
+
+type CycleRequest struct {
+	OnDays  int `json:"on_days"`
+	OffDays int `json:"off_days"`
 }
 
 type ScheduleResponse struct {
@@ -44,9 +134,46 @@ type ScheduleResponse struct {
 	Message     string `json:"message,omitempty"`
 }
 
+// ScheduleRevisionResponse — редакция расписания; effective_to отсутствует
+// у действующей редакции.
+type ScheduleRevisionResponse struct {
+	Revision      int             `json:"revision"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+	EffectiveTo   *time.Time      `json:"effective_to,omitempty"`
+	Schedule      domain.Schedule `json:"schedule"`
+}
+
 type TakingsResponse struct {
-	Medication string      `json:"medication"`
-	Takings    []time.Time `json:"takings"`
+	Medication string           `json:"medication"`
+	Takings    []TakingResponse `json:"takings"`
+}
+
+// PersonTakingsResponse — ближайшие приёмы одного человека в общей сводке.
+type PersonTakingsResponse struct {
+	UserID      int               `json:"user_id"`
+	NextTakings []TakingsResponse `json:"next_takings"`
+}
+
+// TakingResponse — приём и доза на него; dose отсутствует, если доза в
+// расписании не указана.
+type TakingResponse struct {
+	At   time.Time       `json:"at"`
+	Dose *DosageResponse `json:"dose,omitempty"`
+}
+
+// OccurrenceResponse — запланированный приём в перечне за интервал.
+type OccurrenceResponse struct {
+	ScheduleID int             `json:"schedule_id"`
+	Medication string          `json:"medication"`
+	At         time.Time       `json:"at"`
+	Dose       *DosageResponse `json:"dose,omitempty"`
+}
+
+type DosageResponse struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+	Form   string  `json:"form,omitempty"`
+	Route  string  `json:"route,omitempty"`
 }
 
 func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
@@ -57,38 +184,233 @@ func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
 		return
 	}
 
-	freq, err := time.ParseDuration(req.Frequency)
+	userID, err := requestUserID(c, req.UserID)
 	if err != nil {
-		myerrors.HandleError(c, myerrors.ErrInvalidFrequency)
+		myerrors.HandleError(c, err)
 		return
 	}
+	req.UserID = userID
 
-	dur, err := time.ParseDuration(req.Duration)
+	schedule, err := newSchedule(req, time.Now().UTC())
 	if err != nil {
-		myerrors.HandleError(c, myerrors.ErrInvalidDuration)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	if err := h.service.CreateSchedule(c.Request.Context(), schedule); err != nil {
+		myerrors.HandleError(c, err)
 		return
 	}
 
+	c.JSON(http.StatusCreated, gin.H{"id": schedule.ID})
+}
+
+// newSchedule собирает расписание из запроса. Режим приёма задаётся либо
+// полями frequency/times/weekdays/cycle, либо одним правилом rrule. Без
+// start_time расписание начинается в now; конец задаётся end_time,
+// duration или UNTIL правила, и заданные вместе они должны совпадать.
+func newSchedule(req ScheduleRequest, now time.Time) (*domain.Schedule, error) {
+	window, err := parseWindow(req.WindowStart, req.WindowEnd)
+	if err != nil {
+		return nil, myerrors.ErrInvalidTimeWindow
+	}
+
 	schedule := &domain.Schedule{
 		UserID:     req.UserID,
 		Medication: req.Medication,
-		Frequency:  freq,
-		Duration:   dur,
+		Dosage:     newDosage(req.Dose),
+		Timezone:   req.Timezone,
+		Window:     window,
+		StartTime:  now,
+	}
+	if req.StartTime != nil {
+		schedule.StartTime = req.StartTime.UTC()
+	}
+	if req.Rounding != nil {
+		if schedule.Rounding, err = parseRounding(*req.Rounding); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := h.service.CreateSchedule(c, schedule); err != nil {
-		myerrors.HandleError(c, err)
-		return
+	schedule.Phases, err = parsePhases(req.Phases)
+	if err != nil {
+		return nil, err
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"id": schedule.ID})
+	if req.Duration != "" || (req.RRule == "" && req.EndTime == nil && len(req.Phases) == 0) {
+		schedule.Duration, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			return nil, myerrors.ErrInvalidDuration
+		}
+	}
+
+	if req.RRule != "" {
+		if req.Frequency != "" || len(req.Times) > 0 || len(req.Weekdays) > 0 || req.Cycle != nil || req.AsNeeded != nil ||
+			len(req.Anchors) > 0 {
+			return nil, myerrors.ErrInvalidRRule
+		}
+		if err := ical.ApplyRRule(schedule, req.RRule, schedule.StartTime); err != nil {
+			return nil, err
+		}
+	} else if req.AsNeeded != nil {
+		if req.Frequency != "" || len(req.Times) > 0 || len(req.Weekdays) > 0 || req.Cycle != nil || len(req.Phases) > 0 ||
+			len(req.Anchors) > 0 {
+			return nil, domain.ErrInvalidAsNeeded
+		}
+		regimen, err := parseAsNeeded(*req.AsNeeded)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Kind, schedule.MinInterval, schedule.MaxPerDay = regimen.Kind, regimen.MinInterval, regimen.MaxPerDay
+	} else {
+		if len(req.Anchors) > 0 {
+			if req.Frequency != "" || len(req.Times) > 0 {
+				return nil, domain.ErrInvalidAnchors
+			}
+			schedule.Kind = domain.KindEventRelative
+			if schedule.Anchors, err = parseAnchors(req.Anchors); err != nil {
+				return nil, err
+			}
+		} else if req.Frequency == "" && len(req.Times) == 0 && len(schedule.Phases) > 0 && schedule.Phases[0].Regimen.Kind != "" {
+			// Режим расписания по умолчанию — режим первого этапа.
+			first := schedule.Phases[0].Regimen
+			schedule.Kind, schedule.Frequency, schedule.TimesOfDay = first.Kind, first.Frequency, first.TimesOfDay
+		} else {
+			schedule.Kind, schedule.Frequency, schedule.TimesOfDay, err = parseRegimen(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		schedule.Weekdays, err = domain.ParseWeekdays(req.Weekdays)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Cycle != nil {
+			schedule.Cycle = domain.Cycle{OnDays: req.Cycle.OnDays, OffDays: req.Cycle.OffDays}
+		}
+	}
+
+	if req.EndTime != nil {
+		if !req.EndTime.After(schedule.StartTime) || !req.EndTime.After(now) {
+			return nil, myerrors.ErrInvalidTimeRange
+		}
+		duration := req.EndTime.Sub(schedule.StartTime)
+		if schedule.Duration != 0 && schedule.Duration != duration {
+			return nil, myerrors.ErrInvalidTimeRange
+		}
+		schedule.Duration = duration
+	}
+
+	if schedule.Duration == 0 && req.Duration == "" {
+		schedule.Duration = domain.PhasesEnd(schedule.Phases)
+	}
+	return schedule, nil
+}
+
+func parseAsNeeded(req AsNeededRequest) (domain.Regimen, error) {
+	minInterval, err := time.ParseDuration(req.MinInterval)
+	if err != nil {
+		return domain.Regimen{}, domain.ErrInvalidAsNeeded
+	}
+	return domain.Regimen{Kind: domain.KindAsNeeded, MinInterval: minInterval, MaxPerDay: req.MaxPerDay}, nil
+}
+
+func parseRounding(req RoundingRequest) (domain.Rounding, error) {
+	rounding := domain.Rounding{Mode: domain.RoundingMode(req.Mode)}
+	if req.Step != "" {
+		step, err := time.ParseDuration(req.Step)
+		if err != nil {
+			return domain.Rounding{}, domain.ErrInvalidRounding
+		}
+		rounding.Step = step
+	}
+	return rounding, rounding.Validate()
+}
+
+func parseAnchors(reqs []AnchorRequest) ([]domain.Anchor, error) {
+	anchors := make([]domain.Anchor, 0, len(reqs))
+	for _, req := range reqs {
+		anchor := domain.Anchor{Event: domain.DailyEvent(req.Event)}
+		if req.Offset != "" {
+			offset, err := time.ParseDuration(req.Offset)
+			if err != nil {
+				return nil, domain.ErrInvalidAnchors
+			}
+			anchor.Offset = offset
+		}
+		anchors = append(anchors, anchor)
+	}
+	return anchors, nil
+}
+
+// parsePhases разбирает этапы, вычисляя недостающие смещения по концу
+// предыдущего этапа. Стыковку этапов проверяет domain.Schedule.Validate.
+func parsePhases(reqs []PhaseRequest) ([]domain.Phase, error) {
+	var (
+		phases []domain.Phase
+		end    time.Duration
+	)
+	for _, req := range reqs {
+		phase := domain.Phase{Offset: end, Dosage: newDosage(req.Dose)}
+
+		var err error
+		if req.Offset != "" {
+			if phase.Offset, err = time.ParseDuration(req.Offset); err != nil {
+				return nil, myerrors.ErrInvalidDuration
+			}
+		}
+		if req.Duration != "" {
+			if phase.Duration, err = time.ParseDuration(req.Duration); err != nil {
+				return nil, myerrors.ErrInvalidDuration
+			}
+		}
+		if req.Frequency != "" || len(req.Times) > 0 {
+			regimen := ScheduleRequest{Frequency: req.Frequency, Times: req.Times}
+			phase.Regimen.Kind, phase.Regimen.Frequency, phase.Regimen.TimesOfDay, err = parseRegimen(regimen)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		end = phase.Offset + phase.Duration
+		phases = append(phases, phase)
+	}
+	return phases, nil
+}
+
+// parseRegimen определяет вид расписания: список times задаёт приём в
+// фиксированное время суток, иначе используется интервал frequency.
+func parseRegimen(req ScheduleRequest) (domain.ScheduleKind, time.Duration, []time.Duration, error) {
+	if len(req.Times) == 0 {
+		freq, err := time.ParseDuration(req.Frequency)
+		if err != nil {
+			return "", 0, nil, myerrors.ErrInvalidFrequency
+		}
+		return domain.KindInterval, freq, nil, nil
+	}
+
+	if req.Frequency != "" {
+		return "", 0, nil, myerrors.ErrInvalidTimes
+	}
+
+	times := make([]time.Duration, 0, len(req.Times))
+	for _, value := range req.Times {
+		clock, err := domain.ParseClock(value)
+		if err != nil {
+			return "", 0, nil, myerrors.ErrInvalidTimes
+		}
+		times = append(times, clock)
+	}
+	return domain.KindFixedTimes, 0, times, nil
 }
 
 func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Query("user_id"))
-	if err != nil || userID <= 0 {
+	userID, err := queryUserID(c)
+	if err != nil {
 		h.logger.Error("Invalid user_id", "userID", c.Query("user_id"), "error", err)
-		myerrors.HandleError(c, myerrors.ErrInvalidUserID)
+		myerrors.HandleError(c, err)
 		return
 	}
 
@@ -123,31 +445,265 @@ func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
 }
 
 func (h *ScheduleHandler) GetExactSchedule(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Query("user_id"))
-	if err != nil || userID <= 0 {
-		myerrors.HandleError(c, myerrors.ErrInvalidUserID)
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
 		return
 	}
 
-	scheduleID, err := strconv.Atoi(c.Query("schedule_id"))
-	if err != nil || scheduleID <= 0 {
-		myerrors.HandleError(c, myerrors.ErrInvalidScheduleID)
+	schedule, err := h.service.GetScheduleByIDs(c.Request.Context(), userID, scheduleID)
+	if err != nil {
+		myerrors.HandleError(c, err)
 		return
 	}
 
-	schedule, err := h.service.GetScheduleByIDs(c.Request.Context(), userID, scheduleID)
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
 	if err != nil {
 		myerrors.HandleError(c, err)
 		return
 	}
 
+	var req SchedulePatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	patch, err := newSchedulePatch(req)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	schedule, err := h.service.UpdateSchedule(c.Request.Context(), userID, scheduleID, patch)
+	if err != nil {
+		h.logger.Error("Failed to update schedule", "userID", userID, "scheduleID", scheduleID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, schedule)
 }
 
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	if err := h.service.DeleteSchedule(c.Request.Context(), userID, scheduleID); err != nil {
+		h.logger.Error("Failed to delete schedule", "userID", userID, "scheduleID", scheduleID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ScheduleHandler) PauseSchedule(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	schedule, err := h.service.PauseSchedule(c.Request.Context(), userID, scheduleID, time.Now().UTC())
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) ResumeSchedule(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	schedule, err := h.service.ResumeSchedule(c.Request.Context(), userID, scheduleID)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) GetScheduleHistory(c *gin.Context) {
+	userID, scheduleID, err := parseScheduleIDs(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	history, err := h.service.GetScheduleHistory(c.Request.Context(), userID, scheduleID)
+	if err != nil {
+		h.logger.Error("Failed to fetch schedule history", "userID", userID, "scheduleID", scheduleID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]ScheduleRevisionResponse, 0, len(history))
+	for _, revision := range history {
+		item := ScheduleRevisionResponse{
+			Revision:      revision.Number,
+			EffectiveFrom: revision.EffectiveFrom,
+			Schedule:      revision.Schedule,
+		}
+		if !revision.EffectiveTo.IsZero() {
+			effectiveTo := revision.EffectiveTo
+			item.EffectiveTo = &effectiveTo
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// newSchedulePatch переводит запрос в изменение расписания по тем же
+// правилам разбора, что и при создании.
+func newSchedulePatch(req SchedulePatchRequest) (domain.SchedulePatch, error) {
+	patch := domain.SchedulePatch{
+		Medication: req.Medication,
+		Timezone:   req.Timezone,
+	}
+
+	if req.Dose != nil {
+		dosage := newDosage(req.Dose)
+		patch.Dosage = &dosage
+	}
+
+	if req.AsNeeded != nil {
+		if req.Frequency != nil || req.Times != nil || req.Anchors != nil {
+			return domain.SchedulePatch{}, domain.ErrInvalidAsNeeded
+		}
+		regimen, err := parseAsNeeded(*req.AsNeeded)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Regimen = &regimen
+	}
+
+	if req.Frequency != nil || req.Times != nil {
+		regimen := ScheduleRequest{Times: req.Times}
+		if req.Frequency != nil {
+			regimen.Frequency = *req.Frequency
+		}
+		kind, frequency, times, err := parseRegimen(regimen)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Regimen = &domain.Regimen{Kind: kind, Frequency: frequency, TimesOfDay: times}
+	}
+
+	if req.Rounding != nil {
+		rounding, err := parseRounding(*req.Rounding)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Rounding = &rounding
+	}
+
+	if req.Anchors != nil {
+		if req.Frequency != nil || req.Times != nil {
+			return domain.SchedulePatch{}, domain.ErrInvalidAnchors
+		}
+		anchors, err := parseAnchors(req.Anchors)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Regimen = &domain.Regimen{Kind: domain.KindEventRelative, Anchors: anchors}
+	}
+
+	if req.Duration != nil {
+		duration, err := time.ParseDuration(*req.Duration)
+		if err != nil {
+			return domain.SchedulePatch{}, myerrors.ErrInvalidDuration
+		}
+		patch.Duration = &duration
+	}
+
+	if req.WindowStart != nil || req.WindowEnd != nil {
+		if req.WindowStart == nil || req.WindowEnd == nil {
+			return domain.SchedulePatch{}, myerrors.ErrInvalidTimeWindow
+		}
+		window, err := parseWindow(*req.WindowStart, *req.WindowEnd)
+		if err != nil {
+			return domain.SchedulePatch{}, myerrors.ErrInvalidTimeWindow
+		}
+		patch.Window = &window
+	}
+
+	if req.Weekdays != nil {
+		weekdays, err := domain.ParseWeekdays(*req.Weekdays)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Weekdays = &weekdays
+	}
+
+	if req.Cycle != nil {
+		patch.Cycle = &domain.Cycle{OnDays: req.Cycle.OnDays, OffDays: req.Cycle.OffDays}
+	}
+
+	if req.Phases != nil {
+		phases, err := parsePhases(*req.Phases)
+		if err != nil {
+			return domain.SchedulePatch{}, err
+		}
+		patch.Phases = &phases
+		if end := domain.PhasesEnd(phases); end > 0 && patch.Duration == nil {
+			patch.Duration = &end
+		}
+	}
+	return patch, nil
+}
+
+func newDosage(req *DosageRequest) domain.Dosage {
+	if req == nil {
+		return domain.Dosage{}
+	}
+	return domain.Dosage{
+		Amount: req.Amount,
+		Unit:   domain.DoseUnit(req.Unit),
+		Form:   domain.DoseForm(req.Form),
+		Route:  domain.DoseRoute(req.Route),
+	}
+}
+
+func newDosageResponse(d domain.Dosage) *DosageResponse {
+	if d.IsZero() {
+		return nil
+	}
+	return &DosageResponse{Amount: d.Amount, Unit: string(d.Unit), Form: string(d.Form), Route: string(d.Route)}
+}
+
+func parseScheduleIDs(c *gin.Context) (int, int, error) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	scheduleID, err := strconv.Atoi(c.Query("schedule_id"))
+	if err != nil || scheduleID <= 0 {
+		return 0, 0, myerrors.ErrInvalidScheduleID
+	}
+	return userID, scheduleID, nil
+}
+
 func (h *ScheduleHandler) GetNextTakings(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Query("user_id"))
-	if err != nil || userID <= 0 {
-		myerrors.HandleError(c, myerrors.ErrInvalidUserID)
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
 		return
 	}
 
@@ -158,13 +714,121 @@ func (h *ScheduleHandler) GetNextTakings(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, newTakingsResponse(schedules))
+}
+
+// GetHouseholdTakings — ближайшие приёмы сразу нескольких человек:
+// пользователя и пациентов, выдавших ему доступ. Параметр user_id можно
+// повторить, чтобы выбрать людей; без него в сводку попадают все.
+func (h *ScheduleHandler) GetHouseholdTakings(c *gin.Context) {
+	if _, err := currentUserID(c); err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	values := c.QueryArray("user_id")
+	userIDs := make([]int, 0, len(values))
+	for _, value := range values {
+		userID, err := strconv.Atoi(value)
+		if err != nil || userID <= 0 {
+			myerrors.HandleError(c, myerrors.ErrInvalidUserID)
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	people, err := h.service.GetHouseholdTakings(c.Request.Context(), userIDs, time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to get household takings", "userIDs", userIDs, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]PersonTakingsResponse, 0, len(people))
+	for _, person := range people {
+		response = append(response, PersonTakingsResponse{
+			UserID:      person.UserID,
+			NextTakings: newTakingsResponse(person.Schedules),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func newTakingsResponse(schedules []domain.Schedule) []TakingsResponse {
 	response := make([]TakingsResponse, 0, len(schedules))
 	for _, s := range schedules {
+		takings := make([]TakingResponse, 0, len(s.Takings))
+		for _, taking := range s.Takings {
+			takings = append(takings, TakingResponse{At: taking, Dose: newDosageResponse(s.DosageAt(taking))})
+		}
 		response = append(response, TakingsResponse{
 			Medication: s.Medication,
-			Takings:    s.Takings,
+			Takings:    takings,
+		})
+	}
+	return response
+}
+
+// GetTakings перечисляет все запланированные приёмы пользователя в
+// [from, to) в хронологическом порядке; from и to обязательны.
+func (h *ScheduleHandler) GetTakings(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	from, to, err := parseTimeRange(c, time.Time{}, time.Time{})
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	occurrences, err := h.service.GetTakings(c.Request.Context(), userID, from, to)
+	if err != nil {
+		h.logger.Error("Failed to list takings", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]OccurrenceResponse, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		response = append(response, OccurrenceResponse{
+			ScheduleID: occurrence.ScheduleID,
+			Medication: occurrence.Medication,
+			At:         occurrence.At,
+			Dose:       newDosageResponse(occurrence.Dosage),
 		})
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// ExportCalendar отдаёт календарь расписаний пользователя из подписки (см.
+// AuthenticateFeed); чужие расписания через неё недоступны.
+func (h *ScheduleHandler) ExportCalendar(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	schedules, err := h.service.GetSchedulesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to fetch schedules", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	var calendar bytes.Buffer
+	if err := ical.WriteCalendar(&calendar, schedules, now, ical.DefaultHorizonDays, now); err != nil {
+		h.logger.Error("Failed to build calendar", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="schedules.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.Bytes())
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsService interface {
+	GetSettings(ctx context.Context, userID int) (*domain.UserSettings, error)
+	UpdateSettings(ctx context.Context, settings *domain.UserSettings) error
+}
+
+type SettingsHandler struct {
+	service SettingsService
+	logger  *slog.Logger
+}
+
+func NewSettingsHandler(service SettingsService, logger *slog.Logger) *SettingsHandler {
+	return &SettingsHandler{service: service, logger: logger}
+}
+
+type SettingsRequest struct {
+	UserID      int    `json:"user_id"`
+	WindowStart string `json:"window_start"`
+	WindowEnd   string `json:"window_end"`
+	// Events — время событий дня "HH:MM" по названию: breakfast, lunch,
+	// dinner, bedtime. Не указанные события берутся по умолчанию.
+	Events map[string]string `json:"events"`
+}
+
+type SettingsResponse struct {
+	UserID      int    `json:"user_id"`
+	WindowStart string `json:"window_start"`
+	WindowEnd   string `json:"window_end"`
+	// Events — время всех событий дня с учётом значений по умолчанию.
+	Events map[string]string `json:"events"`
+}
+
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to fetch settings", "userID", userID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSettingsResponse(settings))
+}
+
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	userID, err := requestUserID(c, req.UserID)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+	req.UserID = userID
+
+	window, err := parseWindow(req.WindowStart, req.WindowEnd)
+	if err != nil || window.IsZero() {
+		myerrors.HandleError(c, myerrors.ErrInvalidTimeWindow)
+		return
+	}
+
+	events, err := parseEvents(req.Events)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	settings := &domain.UserSettings{UserID: req.UserID, Window: window, Events: events}
+	if err := h.service.UpdateSettings(c.Request.Context(), settings); err != nil {
+		h.logger.Error("Failed to update settings", "userID", req.UserID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSettingsResponse(settings))
+}
+
+func newSettingsResponse(settings *domain.UserSettings) SettingsResponse {
+	events := make(map[string]string, len(domain.AllDailyEvents))
+	for _, event := range domain.AllDailyEvents {
+		events[string(event)] = domain.FormatClock(settings.Events.Clock(event))
+	}
+
+	return SettingsResponse{
+		UserID:      settings.UserID,
+		WindowStart: domain.FormatClock(settings.Window.Start),
+		WindowEnd:   domain.FormatClock(settings.Window.End),
+		Events:      events,
+	}
+}
+
+// parseEvents разбирает время событий дня; nil — события не заданы.
+func parseEvents(raw map[string]string) (domain.DailyEvents, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	events := make(domain.DailyEvents, len(raw))
+	for name, value := range raw {
+		clock, err := domain.ParseClock(value)
+		if err != nil {
+			return nil, domain.ErrInvalidEvents
+		}
+		events[domain.DailyEvent(name)] = clock
+	}
+	return events, events.Validate()
+}
+
+// parseWindow разбирает пару "HH:MM"; пустая пара означает, что окно не задано.
+func parseWindow(start, end string) (domain.DayWindow, error) {
+	if start == "" && end == "" {
+		return domain.DayWindow{}, nil
+	}
+
+	from, err := domain.ParseClock(start)
+	if err != nil {
+		return domain.DayWindow{}, err
+	}
+	to, err := domain.ParseClock(end)
+	if err != nil {
+		return domain.DayWindow{}, err
+	}
+	return domain.DayWindow{Start: from, End: to}, nil
+}
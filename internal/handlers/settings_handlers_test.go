@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSettingsService struct {
+	mock.Mock
+}
+
+func (m *MockSettingsService) GetSettings(ctx context.Context, userID int) (*domain.UserSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*domain.UserSettings), args.Error(1)
+}
+
+func (m *MockSettingsService) UpdateSettings(ctx context.Context, settings *domain.UserSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func TestGetSettings_Success(t *testing.T) {
+	mockService := new(MockSettingsService)
+	handler := handlers.NewSettingsHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/settings", handler.GetSettings)
+
+	mockService.On("GetSettings", mock.Anything, 1).
+		Return(&domain.UserSettings{UserID: 1, Window: domain.DefaultDayWindow}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/settings?user_id=1", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response handlers.SettingsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "08:00", response.WindowStart)
+	assert.Equal(t, "22:00", response.WindowEnd)
+	assert.Equal(t, map[string]string{
+		"breakfast": "08:00", "lunch": "13:00", "dinner": "19:00", "bedtime": "22:00",
+	}, response.Events)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	mockService := new(MockSettingsService)
+	handler := handlers.NewSettingsHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.PUT("/settings", handler.UpdateSettings)
+
+	mockService.On("UpdateSettings", mock.Anything, &domain.UserSettings{
+		UserID: 1,
+		Window: domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+	}).Return(nil)
+	mockService.On("UpdateSettings", mock.Anything, &domain.UserSettings{
+		UserID: 2,
+		Window: domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+	}).Return(myerrors.ErrAccessDenied)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{
+			name:     "Night shift",
+			body:     `{"user_id": 1, "window_start": "20:00", "window_end": "08:00"}`,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Unknown event",
+			body:     `{"user_id": 1, "window_start": "20:00", "window_end": "08:00", "events": {"brunch": "11:00"}}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid event time",
+			body:     `{"user_id": 1, "window_start": "20:00", "window_end": "08:00", "events": {"dinner": "7pm"}}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Missing end",
+			body:     `{"user_id": 1, "window_start": "20:00"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Patient without grant",
+			body:     `{"user_id": 2, "window_start": "20:00", "window_end": "08:00"}`,
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/settings", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSettings_Events(t *testing.T) {
+	mockService := new(MockSettingsService)
+	handler := handlers.NewSettingsHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.PUT("/settings", handler.UpdateSettings)
+
+	mockService.On("UpdateSettings", mock.Anything, &domain.UserSettings{
+		UserID: 1,
+		Window: domain.DefaultDayWindow,
+		Events: domain.DailyEvents{domain.EventBreakfast: 7 * time.Hour, domain.EventBedtime: 21*time.Hour + 30*time.Minute},
+	}).Return(nil)
+
+	body := `{"user_id": 1, "window_start": "08:00", "window_end": "22:00",
+		"events": {"breakfast": "07:00", "bedtime": "21:30"}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/settings", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response handlers.SettingsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "07:00", response.Events["breakfast"])
+	assert.Equal(t, "13:00", response.Events["lunch"])
+	assert.Equal(t, "21:30", response.Events["bedtime"])
+	mockService.AssertExpectations(t)
+}
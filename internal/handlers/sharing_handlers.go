@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SharingService interface {
+	Invite(ctx context.Context, email string, access domain.AccessLevel) (*domain.Grant, error)
+	ListGranted(ctx context.Context) ([]domain.Grant, error)
+	ListReceived(ctx context.Context) ([]domain.Grant, error)
+	Accept(ctx context.Context, grantID int, now time.Time) (*domain.Grant, error)
+	Revoke(ctx context.Context, grantID int) error
+	GetPatients(ctx context.Context, now time.Time) ([]domain.PatientTakings, error)
+}
+
+type SharingHandler struct {
+	service SharingService
+	logger  *slog.Logger
+}
+
+func NewSharingHandler(service SharingService, logger *slog.Logger) *SharingHandler {
+	return &SharingHandler{service: service, logger: logger}
+}
+
+// GrantRequest — приглашение доверенного лица по адресу его учётной
+// записи с правами read или manage.
+type GrantRequest struct {
+	Email  string `json:"email"`
+	Access string `json:"access"`
+}
+
+type GrantResponse struct {
+	ID             int        `json:"id"`
+	PatientID      int        `json:"patient_id"`
+	PatientEmail   string     `json:"patient_email,omitempty"`
+	CaregiverID    int        `json:"caregiver_id"`
+	CaregiverEmail string     `json:"caregiver_email"`
+	Access         string     `json:"access"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+}
+
+// PatientResponse — пациент доверенного лица с ближайшими приёмами.
+type PatientResponse struct {
+	UserID      int               `json:"user_id"`
+	Email       string            `json:"email,omitempty"`
+	Access      string            `json:"access"`
+	NextTakings []TakingsResponse `json:"next_takings"`
+}
+
+func (h *SharingHandler) Invite(c *gin.Context) {
+	var req GrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Binding error", "error", err)
+		myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+		return
+	}
+
+	grant, err := h.service.Invite(c.Request.Context(), req.Email, domain.AccessLevel(req.Access))
+	if err != nil {
+		h.logger.Error("Failed to share access", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newGrantResponse(*grant))
+}
+
+// GetGranted перечисляет доступы, выданные пользователем.
+func (h *SharingHandler) GetGranted(c *gin.Context) {
+	grants, err := h.service.ListGranted(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list grants", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newGrantsResponse(grants))
+}
+
+// GetReceived перечисляет доступы и приглашения, полученные пользователем.
+func (h *SharingHandler) GetReceived(c *gin.Context) {
+	grants, err := h.service.ListReceived(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list received grants", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newGrantsResponse(grants))
+}
+
+func (h *SharingHandler) Accept(c *gin.Context) {
+	grantID, err := parseGrantID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	grant, err := h.service.Accept(c.Request.Context(), grantID, time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to accept grant", "grantID", grantID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newGrantResponse(*grant))
+}
+
+// Revoke отзывает доступ (пациент) или отказывается от него (доверенное
+// лицо).
+func (h *SharingHandler) Revoke(c *gin.Context) {
+	grantID, err := parseGrantID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), grantID); err != nil {
+		h.logger.Error("Failed to revoke grant", "grantID", grantID, "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPatients — сводка для доверенного лица: все его пациенты с
+// ближайшими приёмами.
+func (h *SharingHandler) GetPatients(c *gin.Context) {
+	patients, err := h.service.GetPatients(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		h.logger.Error("Failed to list patients", "error", err)
+		myerrors.HandleError(c, err)
+		return
+	}
+
+	response := make([]PatientResponse, 0, len(patients))
+	for _, patient := range patients {
+		response = append(response, PatientResponse{
+			UserID:      patient.Grant.PatientID,
+			Email:       patient.Grant.PatientEmail,
+			Access:      string(patient.Grant.Access),
+			NextTakings: newTakingsResponse(patient.Schedules),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func parseGrantID(c *gin.Context) (int, error) {
+	grantID, err := strconv.Atoi(c.Query("grant_id"))
+	if err != nil || grantID <= 0 {
+		return 0, myerrors.ErrInvalidRequest
+	}
+	return grantID, nil
+}
+
+func newGrantsResponse(grants []domain.Grant) []GrantResponse {
+	response := make([]GrantResponse, 0, len(grants))
+	for _, grant := range grants {
+		response = append(response, newGrantResponse(grant))
+	}
+	return response
+}
+
+func newGrantResponse(grant domain.Grant) GrantResponse {
+	response := GrantResponse{
+		ID:             grant.ID,
+		PatientID:      grant.PatientID,
+		PatientEmail:   grant.PatientEmail,
+		CaregiverID:    grant.CaregiverID,
+		CaregiverEmail: grant.CaregiverEmail,
+		Access:         string(grant.Access),
+		Status:         string(grant.Status),
+		CreatedAt:      grant.CreatedAt,
+	}
+	if !grant.AcceptedAt.IsZero() {
+		response.AcceptedAt = &grant.AcceptedAt
+	}
+	return response
+}
@@ -0,0 +1,183 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSharingService struct {
+	mock.Mock
+}
+
+func (m *MockSharingService) Invite(ctx context.Context, email string, access domain.AccessLevel) (*domain.Grant, error) {
+	args := m.Called(ctx, email, access)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Grant), args.Error(1)
+}
+
+func (m *MockSharingService) ListGranted(ctx context.Context) ([]domain.Grant, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Grant), args.Error(1)
+}
+
+func (m *MockSharingService) ListReceived(ctx context.Context) ([]domain.Grant, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Grant), args.Error(1)
+}
+
+func (m *MockSharingService) Accept(ctx context.Context, grantID int, now time.Time) (*domain.Grant, error) {
+	args := m.Called(ctx, grantID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Grant), args.Error(1)
+}
+
+func (m *MockSharingService) Revoke(ctx context.Context, grantID int) error {
+	return m.Called(ctx, grantID).Error(0)
+}
+
+func (m *MockSharingService) GetPatients(ctx context.Context, now time.Time) ([]domain.PatientTakings, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]domain.PatientTakings), args.Error(1)
+}
+
+func TestInviteCaregiver(t *testing.T) {
+	mockService := new(MockSharingService)
+	handler := handlers.NewSharingHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/grants", handler.Invite)
+
+	mockService.On("Invite", mock.Anything, "mom@example.com", domain.AccessRead).Return(&domain.Grant{
+		ID:             5,
+		PatientID:      1,
+		CaregiverID:    2,
+		CaregiverEmail: "mom@example.com",
+		Access:         domain.AccessRead,
+		Status:         domain.GrantPending,
+	}, nil)
+	mockService.On("Invite", mock.Anything, "mom@example.com", domain.AccessLevel("owner")).
+		Return(nil, domain.ErrInvalidAccess)
+	mockService.On("Invite", mock.Anything, "nobody@example.com", domain.AccessRead).
+		Return(nil, myerrors.ErrUserNotFound)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"Success", `{"email": "mom@example.com", "access": "read"}`, http.StatusCreated},
+		{"Invalid access", `{"email": "mom@example.com", "access": "owner"}`, http.StatusBadRequest},
+		{"Unknown email", `{"email": "nobody@example.com", "access": "read"}`, http.StatusNotFound},
+		{"Malformed body", `{"email": 5}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/grants", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected == http.StatusCreated {
+				var response handlers.GrantResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "pending", response.Status)
+				assert.Nil(t, response.AcceptedAt)
+			}
+		})
+	}
+}
+
+func TestAcceptAndRevokeGrant(t *testing.T) {
+	mockService := new(MockSharingService)
+	handler := handlers.NewSharingHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.POST("/grants/accept", handler.Accept)
+	router.DELETE("/grants", handler.Revoke)
+
+	acceptedAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockService.On("Accept", mock.Anything, 5, mock.Anything).Return(&domain.Grant{
+		ID: 5, PatientID: 1, CaregiverID: 2, Access: domain.AccessManage,
+		Status: domain.GrantAccepted, AcceptedAt: acceptedAt,
+	}, nil)
+	mockService.On("Accept", mock.Anything, 6, mock.Anything).Return(nil, myerrors.ErrGrantNotFound)
+	mockService.On("Revoke", mock.Anything, 5).Return(nil)
+
+	testCases := []struct {
+		name     string
+		method   string
+		url      string
+		expected int
+	}{
+		{"Accept", "POST", "/grants/accept?grant_id=5", http.StatusOK},
+		{"Accept foreign", "POST", "/grants/accept?grant_id=6", http.StatusNotFound},
+		{"Accept without ID", "POST", "/grants/accept", http.StatusBadRequest},
+		{"Revoke", "DELETE", "/grants?grant_id=5", http.StatusNoContent},
+		{"Revoke invalid ID", "DELETE", "/grants?grant_id=-1", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+func TestGetPatients(t *testing.T) {
+	mockService := new(MockSharingService)
+	handler := handlers.NewSharingHandler(mockService, slog.Default())
+
+	router := setupRouter()
+	router.GET("/patients", handler.GetPatients)
+
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	mockService.On("GetPatients", mock.Anything, mock.Anything).Return([]domain.PatientTakings{{
+		Grant: domain.Grant{PatientID: 3, PatientEmail: "son@example.com", Access: domain.AccessRead},
+		Schedules: []domain.Schedule{{
+			ID: 7, UserID: 3, Medication: "Aspirin", Frequency: 4 * time.Hour, Duration: 48 * time.Hour,
+			StartTime: start, Takings: []time.Time{start.Add(4 * time.Hour)},
+		}},
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/patients", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []handlers.PatientResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, 3, response[0].UserID)
+		assert.Equal(t, "read", response[0].Access)
+		if assert.Len(t, response[0].NextTakings, 1) {
+			assert.Equal(t, "Aspirin", response[0].NextTakings[0].Medication)
+			assert.Len(t, response[0].NextTakings[0].Takings, 1)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/stream"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reconnectDelay — через сколько клиенту переподключаться после обрыва
+// (поле retry в Server-Sent Events).
+const reconnectDelay = 3 * time.Second
+
+type EventStream interface {
+	Subscribe(identity auth.Identity, lastEventID int64) *stream.Subscription
+}
+
+type StreamHandler struct {
+	events    EventStream
+	heartbeat time.Duration
+	logger    *slog.Logger
+}
+
+func NewStreamHandler(events EventStream, heartbeat time.Duration, logger *slog.Logger) *StreamHandler {
+	return &StreamHandler{events: events, heartbeat: heartbeat, logger: logger}
+}
+
+// StreamEventResponse — данные события в потоке; вид события передаётся
+// в поле event, идентификатор — в поле id.
+type StreamEventResponse struct {
+	ScheduleID int             `json:"schedule_id"`
+	Medication string          `json:"medication"`
+	At         time.Time       `json:"at"`
+	Dose       *DosageResponse `json:"dose,omitempty"`
+	Status     string          `json:"status,omitempty"`
+}
+
+// Stream отдаёт события пользователя по Server-Sent Events: taking_due,
+// schedule_changed и dose_recorded. Клиент, переподключаясь с заголовком
+// Last-Event-ID (или параметром last_event_id), получает пропущенные
+// события. Пока событий нет, раз в heartbeat уходит комментарий.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		myerrors.HandleError(c, err)
+		return
+	}
+	// Поток только свой: события пациента доверенным лицам не рассылаются.
+	if current, _ := currentUserID(c); userID != current {
+		myerrors.HandleError(c, myerrors.ErrAccessDenied)
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var since int64
+	if lastEventID != "" {
+		if since, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || since < 0 {
+			myerrors.HandleError(c, myerrors.ErrInvalidRequest)
+			return
+		}
+	}
+
+	organizationID, _ := auth.OrganizationIDFrom(c.Request.Context())
+	sub := h.events.Subscribe(auth.Identity{UserID: userID, OrganizationID: organizationID}, since)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	for _, event := range sub.Replay {
+		if err := writeStreamEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Сервер останавливается или клиент не успевает читать:
+				// клиент переподключится с Last-Event-ID.
+				return
+			}
+			if err := writeStreamEvent(c.Writer, event); err != nil {
+				h.logger.Error("Failed to write stream event", "userID", userID, "error", err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeStreamEvent(w io.Writer, event domain.StreamEvent) error {
+	data, err := json.Marshal(StreamEventResponse{
+		ScheduleID: event.ScheduleID,
+		Medication: event.Medication,
+		At:         event.At,
+		Dose:       newDosageResponse(event.Dosage),
+		Status:     string(event.Status),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
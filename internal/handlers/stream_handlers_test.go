@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/handlers"
+	"medication-scheduler/internal/stream"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStreamServer(hub *stream.Hub, heartbeat time.Duration) *httptest.Server {
+	handler := handlers.NewStreamHandler(hub, heartbeat, slog.Default())
+	router := setupRouter()
+	router.GET("/stream", handler.Stream)
+	return httptest.NewServer(router)
+}
+
+// readUntil читает поток до строки с префиксом prefix и возвращает
+// прочитанные строки.
+func readUntil(t *testing.T, r *bufio.Reader, prefix string) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended before %q: %v", prefix, err)
+		}
+		line = strings.TrimRight(line, "\n")
+		lines = append(lines, line)
+		if strings.HasPrefix(line, prefix) {
+			return lines
+		}
+	}
+}
+
+func TestStream_DeliversEvents(t *testing.T) {
+	hub := stream.NewHub(stream.Config{History: 10})
+	server := newStreamServer(hub, time.Hour)
+	defer server.Close()
+	defer hub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/stream?user_id=1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	body := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"retry: 3000"}, readUntil(t, body, "retry:"))
+
+	hub.Publish(domain.StreamEvent{
+		Type:       domain.StreamTakingDue,
+		UserID:     1,
+		ScheduleID: 7,
+		Medication: "Aspirin",
+		At:         time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+		Dosage:     domain.Dosage{Amount: 500, Unit: domain.UnitMg},
+	})
+
+	lines := readUntil(t, body, "data:")
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, "", lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], "id: "))
+		assert.Equal(t, "event: taking_due", lines[2])
+		assert.JSONEq(t, `{"schedule_id":7,"medication":"Aspirin","at":"2025-01-02T09:00:00Z","dose":{"amount":500,"unit":"mg"}}`,
+			strings.TrimPrefix(lines[3], "data: "))
+	}
+}
+
+func TestStream_ReplaysAfterLastEventID(t *testing.T) {
+	hub := stream.NewHub(stream.Config{History: 10})
+	server := newStreamServer(hub, time.Hour)
+	defer server.Close()
+	defer hub.Close()
+
+	first := hub.Subscribe(auth.Identity{UserID: 1, OrganizationID: 1}, 0)
+	hub.Publish(domain.StreamEvent{Type: domain.StreamScheduleChanged, UserID: 1, ScheduleID: 1, Medication: "Aspirin"})
+	hub.Publish(domain.StreamEvent{Type: domain.StreamDoseRecorded, UserID: 1, ScheduleID: 2, Medication: "Ibuprofen", Status: domain.DoseTaken})
+	seen := <-first.Events
+	first.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/stream?user_id=1", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(seen.ID, 10))
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	lines := readUntil(t, bufio.NewReader(resp.Body), "data:")
+	assert.Contains(t, lines, "event: dose_recorded")
+	assert.NotContains(t, lines, "event: schedule_changed")
+	assert.Contains(t, lines[len(lines)-1], `"status":"taken"`)
+}
+
+func TestStream_Heartbeat(t *testing.T) {
+	hub := stream.NewHub(stream.Config{History: 10})
+	server := newStreamServer(hub, 10*time.Millisecond)
+	defer server.Close()
+	defer hub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/stream?user_id=1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	readUntil(t, bufio.NewReader(resp.Body), ": heartbeat")
+}
+
+func TestStream_InvalidRequest(t *testing.T) {
+	hub := stream.NewHub(stream.Config{History: 10})
+	handler := handlers.NewStreamHandler(hub, time.Hour, slog.Default())
+	router := setupRouter()
+	router.GET("/stream", handler.Stream)
+
+	for _, url := range []string{"/stream?user_id=0", "/stream?user_id=abc", "/stream?user_id=1&last_event_id=x"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+	assert.Empty(t, hub.Users())
+}
@@ -0,0 +1,84 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+// DefaultHorizonDays — на сколько дней вперёд выгружаются приёмы.
+const DefaultHorizonDays = 30
+
+const (
+	utcLayout     = "20060102T150405Z"
+	maxLineOctets = 75
+)
+
+// WriteCalendar выгружает запланированные приёмы расписаний в формате
+// iCalendar (RFC 5545): по одному VEVENT на каждый приём в интервале
+// [from, from+days). Приёмы берутся из domain.Schedule.PlannedTakings, поэтому
+// совпадают с тем, что возвращает API.
+func WriteCalendar(w io.Writer, schedules []domain.Schedule, from time.Time, days int, stamp time.Time) error {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//medication-scheduler//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:Medications")
+
+	to := from.AddDate(0, 0, days)
+	for _, schedule := range schedules {
+		for _, taking := range schedule.PlannedTakings(from, to) {
+			writeLine(&b, "BEGIN:VEVENT")
+			writeLine(&b, fmt.Sprintf("UID:schedule-%d-%s@medication-scheduler", schedule.ID, taking.UTC().Format(utcLayout)))
+			writeLine(&b, "DTSTAMP:"+stamp.UTC().Format(utcLayout))
+			writeLine(&b, "DTSTART:"+taking.UTC().Format(utcLayout))
+			writeLine(&b, fmt.Sprintf("DURATION:PT%dM", domain.RoundTo))
+			writeLine(&b, "SUMMARY:"+escapeText(schedule.Medication))
+			if dosage := schedule.DosageAt(taking); !dosage.IsZero() {
+				writeLine(&b, "DESCRIPTION:"+escapeText(dosage.String()))
+			}
+			writeLine(&b, "END:VEVENT")
+		}
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// textEscaper экранирует TEXT по RFC 5545. Одиночный CR тоже считается
+// переводом строки: оставленный как есть, он разорвал бы строку файла.
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine дописывает строку с CRLF, перенося её по 75 октетов без
+// разрыва многобайтовых символов.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,95 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/ical"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	from := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{
+		{
+			ID:         7,
+			Medication: "Aspirin; 500 mg, tablet",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.WriteCalendar(&buf, schedules, from, 2, from))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	// 21:00 1 января, 09:00 и 21:00 2 января, 09:00 3 января
+	assert.Equal(t, 4, strings.Count(out, "BEGIN:VEVENT"))
+	assert.Contains(t, out, "DTSTART:20250101T210000Z\r\n")
+	assert.Contains(t, out, "DTSTART:20250103T090000Z\r\n")
+	assert.NotContains(t, out, "DTSTART:20250101T090000Z")
+	assert.Contains(t, out, `SUMMARY:Aspirin\; 500 mg\, tablet`)
+	assert.Contains(t, out, "UID:schedule-7-20250102T090000Z@medication-scheduler")
+	assert.NotContains(t, out, "DESCRIPTION:")
+}
+
+func TestWriteCalendar_Dosage(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{
+		{
+			Medication: "Amoxicillin",
+			Dosage:     domain.Dosage{Amount: 2.5, Unit: domain.UnitMl, Form: domain.FormSuspension, Route: domain.RouteOral},
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.WriteCalendar(&buf, schedules, from, 1, from))
+
+	assert.Contains(t, buf.String(), `DESCRIPTION:2.5 ml suspension\, oral`+"\r\n")
+}
+
+func TestWriteCalendar_EscapesLineBreaks(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{
+		{
+			Medication: "Aspirin\rmorning\r\nevening\nnight",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.WriteCalendar(&buf, schedules, from, 1, from))
+
+	assert.Contains(t, buf.String(), `SUMMARY:Aspirin\nmorning\nevening\nnight`+"\r\n")
+	assert.NotContains(t, strings.ReplaceAll(buf.String(), "\r\n", ""), "\r")
+}
+
+func TestWriteCalendar_FoldsLongLines(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []domain.Schedule{
+		{
+			Medication: strings.Repeat("Ацетилсалициловая кислота ", 5),
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.WriteCalendar(&buf, schedules, from, 1, from))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+schedules[0].Medication)
+}
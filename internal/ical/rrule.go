@@ -0,0 +1,221 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"medication-scheduler/internal/domain"
+)
+
+var ErrUnsupportedRRule = errors.New("unsupported recurrence rule")
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ApplyRRule переносит правило повторения RFC 5545 на расписание.
+// Поддерживается подмножество, которое выражается моделью расписания:
+//
+//   - FREQ=MINUTELY|HOURLY с INTERVAL — интервальный приём;
+//   - FREQ=DAILY с BYHOUR/BYMINUTE — приём в фиксированное время,
+//     INTERVAL=n превращается в цикл «1 день приёма, n-1 перерыва»;
+//   - FREQ=WEEKLY с BYDAY и BYHOUR/BYMINUTE;
+//   - BYDAY без числовых префиксов и UNTIL для любого FREQ.
+//
+// Остальные части правила отклоняются ошибкой ErrUnsupportedRRule с
+// указанием неподдерживаемой части. Расписание должно уже содержать
+// часовой пояс: по нему трактуются UNTIL без суффикса Z. Длительность по
+// UNTIL отсчитывается от start — начала расписания.
+func ApplyRRule(schedule *domain.Schedule, rule string, start time.Time) error {
+	parts, err := splitRRule(rule)
+	if err != nil {
+		return err
+	}
+
+	interval := 1
+	if value, ok := parts["INTERVAL"]; ok {
+		interval, err = strconv.Atoi(value)
+		if err != nil || interval < 1 {
+			return fmt.Errorf("%w: INTERVAL must be a positive integer", ErrUnsupportedRRule)
+		}
+	}
+
+	weekdays, err := parseByDay(parts["BYDAY"])
+	if err != nil {
+		return err
+	}
+
+	times, err := parseByTime(parts)
+	if err != nil {
+		return err
+	}
+
+	switch freq := parts["FREQ"]; freq {
+	case "MINUTELY", "HOURLY":
+		if len(times) > 0 {
+			return fmt.Errorf("%w: BYHOUR/BYMINUTE cannot be combined with FREQ=%s", ErrUnsupportedRRule, freq)
+		}
+		unit := time.Minute
+		if freq == "HOURLY" {
+			unit = time.Hour
+		}
+		schedule.Kind = domain.KindInterval
+		schedule.Frequency = time.Duration(interval) * unit
+	case "DAILY":
+		if len(times) == 0 {
+			return fmt.Errorf("%w: FREQ=DAILY requires BYHOUR", ErrUnsupportedRRule)
+		}
+		schedule.Kind = domain.KindFixedTimes
+		schedule.TimesOfDay = times
+		if interval > 1 {
+			schedule.Cycle = domain.Cycle{OnDays: 1, OffDays: interval - 1}
+		}
+	case "WEEKLY":
+		if weekdays == 0 || len(times) == 0 {
+			return fmt.Errorf("%w: FREQ=WEEKLY requires BYDAY and BYHOUR", ErrUnsupportedRRule)
+		}
+		if interval != 1 {
+			return fmt.Errorf("%w: FREQ=WEEKLY supports only INTERVAL=1", ErrUnsupportedRRule)
+		}
+		schedule.Kind = domain.KindFixedTimes
+		schedule.TimesOfDay = times
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrUnsupportedRRule)
+	default:
+		return fmt.Errorf("%w: FREQ=%s", ErrUnsupportedRRule, freq)
+	}
+	schedule.Weekdays = weekdays
+
+	if value, ok := parts["UNTIL"]; ok {
+		until, err := parseUntil(value, schedule.Location())
+		if err != nil {
+			return err
+		}
+		if !until.After(start) {
+			return fmt.Errorf("%w: UNTIL is before the start", ErrUnsupportedRRule)
+		}
+		schedule.Duration = until.Sub(start)
+	}
+
+	return nil
+}
+
+func splitRRule(rule string) (map[string]string, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("%w: rule is empty", ErrUnsupportedRRule)
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrUnsupportedRRule, part)
+		}
+		if _, dup := parts[key]; dup {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrUnsupportedRRule, key)
+		}
+
+		switch key {
+		case "FREQ", "INTERVAL", "BYDAY", "BYHOUR", "BYMINUTE", "UNTIL", "WKST":
+			parts[key] = strings.ToUpper(value)
+		case "COUNT":
+			return nil, fmt.Errorf("%w: COUNT is not supported, use UNTIL", ErrUnsupportedRRule)
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrUnsupportedRRule, key)
+		}
+	}
+	return parts, nil
+}
+
+func parseByDay(value string) (domain.Weekdays, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		day, ok := rruleWeekdays[name]
+		if !ok {
+			return 0, fmt.Errorf("%w: BYDAY=%s", ErrUnsupportedRRule, name)
+		}
+		days = append(days, day)
+	}
+	return domain.NewWeekdays(days...), nil
+}
+
+// parseByTime раскрывает BYHOUR × BYMINUTE во время суток.
+func parseByTime(parts map[string]string) ([]time.Duration, error) {
+	hours, err := parseIntList(parts["BYHOUR"], "BYHOUR", 23)
+	if err != nil {
+		return nil, err
+	}
+	minutes, err := parseIntList(parts["BYMINUTE"], "BYMINUTE", 59)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hours) == 0 {
+		if len(minutes) > 0 {
+			return nil, fmt.Errorf("%w: BYMINUTE requires BYHOUR", ErrUnsupportedRRule)
+		}
+		return nil, nil
+	}
+	if len(minutes) == 0 {
+		minutes = []int{0}
+	}
+
+	var times []time.Duration
+	for _, h := range hours {
+		for _, m := range minutes {
+			clock := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+			if !slices.Contains(times, clock) {
+				times = append(times, clock)
+			}
+		}
+	}
+	slices.Sort(times)
+	return times, nil
+}
+
+func parseIntList(value, name string, max int) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 || n > max {
+			return nil, fmt.Errorf("%w: %s=%s", ErrUnsupportedRRule, name, item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// parseUntil понимает дату (включительно до конца дня), время в UTC с
+// суффиксом Z и «плавающее» местное время пациента.
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL=%s", ErrUnsupportedRRule, value)
+}
@@ -0,0 +1,90 @@
+package ical_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/ical"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRRule(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     string
+		expected domain.Schedule
+	}{
+		{
+			"Hourly interval",
+			"FREQ=HOURLY;INTERVAL=6",
+			domain.Schedule{Kind: domain.KindInterval, Frequency: 6 * time.Hour},
+		},
+		{
+			"Breakfast and bedtime",
+			"RRULE:FREQ=DAILY;BYHOUR=8,21;BYMINUTE=30",
+			domain.Schedule{
+				Kind:       domain.KindFixedTimes,
+				TimesOfDay: []time.Duration{8*time.Hour + 30*time.Minute, 21*time.Hour + 30*time.Minute},
+			},
+		},
+		{
+			"Every other day",
+			"FREQ=DAILY;INTERVAL=2;BYHOUR=9",
+			domain.Schedule{
+				Kind:       domain.KindFixedTimes,
+				TimesOfDay: []time.Duration{9 * time.Hour},
+				Cycle:      domain.Cycle{OnDays: 1, OffDays: 1},
+			},
+		},
+		{
+			"Weekly with until",
+			"FREQ=WEEKLY;BYDAY=MO,WE,FR;BYHOUR=10;UNTIL=20250131",
+			domain.Schedule{
+				Kind:       domain.KindFixedTimes,
+				TimesOfDay: []time.Duration{10 * time.Hour},
+				Weekdays:   domain.NewWeekdays(time.Monday, time.Wednesday, time.Friday),
+				Duration:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Sub(now),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schedule domain.Schedule
+			require.NoError(t, ical.ApplyRRule(&schedule, tt.rule, now))
+			assert.Equal(t, tt.expected, schedule)
+		})
+	}
+}
+
+func TestApplyRRule_Unsupported(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=MONTHLY;BYMONTHDAY=1",
+		"FREQ=DAILY",
+		"FREQ=DAILY;BYHOUR=8;COUNT=10",
+		"FREQ=WEEKLY;BYDAY=1MO;BYHOUR=8",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;BYHOUR=8",
+		"FREQ=HOURLY;BYHOUR=8",
+		"FREQ=DAILY;BYHOUR=25",
+		"FREQ=DAILY;BYHOUR=8;UNTIL=20240101",
+		"FREQ=DAILY;FREQ=HOURLY",
+	}
+
+	for _, rule := range rules {
+		t.Run(rule, func(t *testing.T) {
+			var schedule domain.Schedule
+			err := ical.ApplyRRule(&schedule, rule, now)
+			assert.True(t, errors.Is(err, ical.ErrUnsupportedRRule), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package reminder
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"time"
+)
+
+type Config struct {
+	Interval     time.Duration
+	Lead         time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// MaxAge — насколько напоминание может опоздать: не доставленные за это
+	// время после приёма уходят в dead letter. Ноль — без ограничения.
+	MaxAge     time.Duration
+	WebhookURL string
+	SMTP       SMTPConfig
+}
+
+// Notifier доставляет напоминание по одному каналу (webhook, почта и т.д.).
+// Доставка идёт «как минимум один раз», поэтому получатель должен
+// отбрасывать повторы по reminder.IdempotencyKey().
+type Notifier interface {
+	Notify(ctx context.Context, reminder domain.Reminder) error
+}
+
+type ScheduleRepository interface {
+	GetActive(ctx context.Context, until time.Time) ([]domain.Schedule, error)
+}
+
+type OrganizationLister interface {
+	ListIDs(ctx context.Context) ([]int, error)
+}
+
+type Outbox interface {
+	Enqueue(ctx context.Context, reminders []domain.Reminder) error
+}
+
+// Dispatcher периодически ставит в очередь напоминания о приёмах на
+// domain.ReminderHorizon вперёд. Очередь сама отбрасывает повторы, так что
+// каждый проход может перекрывать предыдущий. Расписания читаются по
+// одной организации за запрос.
+type Dispatcher struct {
+	repo          ScheduleRepository
+	organizations OrganizationLister
+	outbox        Outbox
+	interval      time.Duration
+	logger        *slog.Logger
+}
+
+func NewDispatcher(repo ScheduleRepository, organizations OrganizationLister, outbox Outbox, cfg Config, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:          repo,
+		organizations: organizations,
+		outbox:        outbox,
+		interval:      cfg.Interval,
+		logger:        logger,
+	}
+}
+
+// Run работает до отмены ctx; текущий проход при этом завершается штатно.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.logger.Info("Reminder dispatcher started", "interval", d.interval)
+	for {
+		d.Dispatch(ctx, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Reminder dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch выполняет один проход и возвращает число напоминаний, переданных
+// в очередь (включая уже стоявшие в ней). Ошибка чтения расписаний одной
+// организации не мешает остальным.
+func (d *Dispatcher) Dispatch(ctx context.Context, now time.Time) int {
+	organizations, err := d.organizations.ListIDs(ctx)
+	if err != nil {
+		d.logger.Error("Failed to fetch organizations", "error", err)
+		return 0
+	}
+
+	until := now.Add(domain.ReminderHorizon)
+	var reminders []domain.Reminder
+	for _, organizationID := range organizations {
+		schedules, err := d.repo.GetActive(auth.WithOrganizationID(ctx, organizationID), until)
+		if err != nil {
+			d.logger.Error("Failed to fetch active schedules", "organizationID", organizationID, "error", err)
+			continue
+		}
+		for _, schedule := range schedules {
+			reminders = append(reminders, schedule.RemindersBetween(now, until)...)
+		}
+	}
+	if len(reminders) == 0 {
+		return 0
+	}
+
+	if err := d.outbox.Enqueue(ctx, reminders); err != nil {
+		d.logger.Error("Failed to enqueue reminders", "error", err)
+		return 0
+	}
+	return len(reminders)
+}
@@ -0,0 +1,140 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) GetActive(ctx context.Context, until time.Time) ([]domain.Schedule, error) {
+	args := m.Called(ctx, until)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+type MockOutbox struct {
+	mock.Mock
+}
+
+func (m *MockOutbox) Enqueue(ctx context.Context, reminders []domain.Reminder) error {
+	return m.Called(ctx, reminders).Error(0)
+}
+
+// stubOrganizations — ID организаций, которые обходит Dispatcher.
+type stubOrganizations []int
+
+func (s stubOrganizations) ListIDs(context.Context) ([]int, error) {
+	return s, nil
+}
+
+func newDispatcher(repo reminder.ScheduleRepository, outbox reminder.Outbox) *reminder.Dispatcher {
+	return reminder.NewDispatcher(repo, stubOrganizations{1}, outbox, reminder.Config{Interval: time.Minute}, discardLogger())
+}
+
+// inOrganization — контекст запроса в организации organizationID.
+func inOrganization(organizationID int) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		id, ok := auth.OrganizationIDFrom(ctx)
+		return ok && id == organizationID
+	})
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestDispatch(t *testing.T) {
+	schedule := domain.Schedule{
+		ID:         1,
+		UserID:     42,
+		Medication: "Aspirin",
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+		Timezone:   "UTC",
+		StartTime:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	t.Run("enqueues takings within horizon", func(t *testing.T) {
+		repo := new(MockScheduleRepository)
+		repo.On("GetActive", inOrganization(1), now.Add(domain.ReminderHorizon)).Return([]domain.Schedule{schedule}, nil)
+
+		first, second := time.Date(2025, 1, 2, 21, 0, 0, 0, time.UTC), time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)
+		outbox := new(MockOutbox)
+		outbox.On("Enqueue", mock.Anything, []domain.Reminder{
+			{UserID: 42, ScheduleID: 1, Medication: "Aspirin", DueAt: first, PlannedAt: first},
+			{UserID: 42, ScheduleID: 1, Medication: "Aspirin", DueAt: second, PlannedAt: second},
+		}).Return(nil)
+
+		assert.Equal(t, 2, newDispatcher(repo, outbox).Dispatch(context.Background(), now))
+		repo.AssertExpectations(t)
+		outbox.AssertExpectations(t)
+	})
+
+	t.Run("enqueue failure", func(t *testing.T) {
+		repo := new(MockScheduleRepository)
+		repo.On("GetActive", mock.Anything, mock.Anything).Return([]domain.Schedule{schedule}, nil)
+
+		outbox := new(MockOutbox)
+		outbox.On("Enqueue", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		assert.Equal(t, 0, newDispatcher(repo, outbox).Dispatch(context.Background(), now))
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockScheduleRepository)
+		repo.On("GetActive", mock.Anything, mock.Anything).Return([]domain.Schedule(nil), errors.New("db error"))
+		outbox := new(MockOutbox)
+
+		assert.Equal(t, 0, newDispatcher(repo, outbox).Dispatch(context.Background(), now))
+		outbox.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("organization error does not stop others", func(t *testing.T) {
+		repo := new(MockScheduleRepository)
+		repo.On("GetActive", inOrganization(1), mock.Anything).Return([]domain.Schedule(nil), errors.New("db error"))
+		repo.On("GetActive", inOrganization(2), mock.Anything).Return([]domain.Schedule{schedule}, nil)
+		outbox := new(MockOutbox)
+		outbox.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+
+		d := reminder.NewDispatcher(repo, stubOrganizations{1, 2}, outbox, reminder.Config{Interval: time.Minute}, discardLogger())
+		assert.Equal(t, 2, d.Dispatch(context.Background(), now))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	repo := new(MockScheduleRepository)
+	repo.On("GetActive", mock.Anything, mock.Anything).Return([]domain.Schedule{}, nil)
+	outbox := new(MockOutbox)
+	outbox.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+
+	d := newDispatcher(repo, outbox)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher did not stop after cancel")
+	}
+}
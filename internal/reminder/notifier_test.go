@@ -0,0 +1,172 @@
+package reminder_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testReminder = domain.Reminder{
+	UserID:     42,
+	ScheduleID: 7,
+	Medication: "Aspirin",
+	DueAt:      time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("posts reminder as JSON", func(t *testing.T) {
+		var got map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "schedule-7-2025-01-02T09:00:00Z", r.Header.Get("Idempotency-Key"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		err := reminder.NewWebhookNotifier(server.URL).Notify(context.Background(), testReminder)
+
+		require.NoError(t, err)
+		assert.Equal(t, float64(42), got["user_id"])
+		assert.Equal(t, float64(7), got["schedule_id"])
+		assert.Equal(t, "Aspirin", got["medication"])
+		assert.Equal(t, "2025-01-02T09:00:00Z", got["due_at"])
+	})
+
+	t.Run("non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := reminder.NewWebhookNotifier(server.URL).Notify(context.Background(), testReminder)
+
+		assert.ErrorContains(t, err, "502")
+	})
+}
+
+// smtpStandIn — минимальный SMTP-сервер без TLS и аутентификации,
+// принимающий одно письмо и отдающий его текст в канал.
+func smtpStandIn(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					body.WriteString(l)
+				}
+				messages <- body.String()
+				reply("250 queued")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), messages
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("sends to the patient", func(t *testing.T) {
+		addr, messages := smtpStandIn(t)
+
+		notifier := reminder.NewSMTPNotifier(reminder.SMTPConfig{
+			Addr: addr,
+			From: "scheduler@example.com",
+		})
+
+		patient := testReminder
+		patient.Email = "patient@example.com"
+		require.NoError(t, notifier.Notify(context.Background(), patient))
+
+		select {
+		case msg := <-messages:
+			assert.Contains(t, msg, "To: patient@example.com")
+			assert.Contains(t, msg, "Subject: Medication reminder: Aspirin")
+			assert.Contains(t, msg, "Message-ID: <schedule-7-2025-01-02T09:00:00Z@medication-scheduler>")
+			assert.Contains(t, msg, "Time to take Aspirin at 09:00 (UTC).")
+		case <-time.After(time.Second):
+			t.Fatal("message was not delivered")
+		}
+	})
+
+	t.Run("patient without email", func(t *testing.T) {
+		// Без адреса сервер не нужен: пустой Addr дал бы ошибку при подключении.
+		notifier := reminder.NewSMTPNotifier(reminder.SMTPConfig{})
+
+		require.NoError(t, notifier.Notify(context.Background(), testReminder))
+	})
+
+	t.Run("stuck server is interrupted by ctx", func(t *testing.T) {
+		// Сервер принимает соединение, но не отвечает приветствием.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			conn, err := listener.Accept()
+			if err == nil {
+				defer conn.Close()
+				time.Sleep(5 * time.Second)
+			}
+		}()
+
+		notifier := reminder.NewSMTPNotifier(reminder.SMTPConfig{Addr: listener.Addr().String()})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		patient := testReminder
+		patient.Email = "patient@example.com"
+		started := time.Now()
+		err = notifier.Notify(ctx, patient)
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(started), time.Second)
+	})
+}
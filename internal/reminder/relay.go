@@ -0,0 +1,156 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"medication-scheduler/internal/domain"
+	"time"
+)
+
+const (
+	relayBatchSize = 100
+	// relayLease — на сколько сообщение скрывается от других проходов, пока
+	// идёт доставка. Должно превышать время работы всех каналов.
+	relayLease      = 5 * time.Minute
+	maxRetryBackoff = time.Hour
+)
+
+type OutboxRepository interface {
+	Claim(ctx context.Context, now time.Time, lead, lease time.Duration, limit int) ([]domain.OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string, dead bool) error
+	Expire(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Relay доставляет напоминания из очереди за Lead до приёма. Неудачная
+// доставка повторяется с экспоненциальной задержкой; после MaxAttempts
+// попыток сообщение уходит в dead letter, как и напоминание, опоздавшее
+// больше чем на MaxAge: после простоя воркера пациент не получает пачку
+// напоминаний о давно прошедших приёмах.
+type Relay struct {
+	outbox      OutboxRepository
+	notifiers   []Notifier
+	interval    time.Duration
+	lead        time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	maxAge      time.Duration
+	logger      *slog.Logger
+}
+
+func NewRelay(outbox OutboxRepository, notifiers []Notifier, cfg Config, logger *slog.Logger) *Relay {
+	return &Relay{
+		outbox:      outbox,
+		notifiers:   notifiers,
+		interval:    cfg.Interval,
+		lead:        cfg.Lead,
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     cfg.RetryBackoff,
+		maxAge:      cfg.MaxAge,
+		logger:      logger,
+	}
+}
+
+// Run работает до отмены ctx. Отмена прерывает и текущий проход (см.
+// Deliver), чтобы зависший канал не задерживал остановку сервера.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Info("Reminder relay started", "interval", r.interval, "lead", r.lead)
+	for {
+		r.Deliver(ctx, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Reminder relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver выполняет один проход по очереди и возвращает число доставленных
+// напоминаний. После отмены ctx проход останавливается: прерванные и
+// оставшиеся сообщения не считаются неудачными и вернутся в очередь по
+// истечении relayLease. Результаты уже завершённых доставок записываются
+// и после отмены.
+func (r *Relay) Deliver(ctx context.Context, now time.Time) int {
+	if r.maxAge > 0 {
+		expired, err := r.outbox.Expire(ctx, now.Add(-r.maxAge))
+		if err != nil {
+			r.logger.Error("Failed to expire stale reminders", "error", err)
+			return 0
+		}
+		if expired > 0 {
+			r.logger.Warn("Stale reminders moved to dead letter", "count", expired, "maxAge", r.maxAge)
+		}
+	}
+
+	messages, err := r.outbox.Claim(ctx, now, r.lead, relayLease, relayBatchSize)
+	if err != nil {
+		r.logger.Error("Failed to claim reminders", "error", err)
+		return 0
+	}
+
+	store := context.WithoutCancel(ctx)
+	var delivered int
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := r.notify(ctx, message.Reminder); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			r.fail(store, message, now, err)
+			continue
+		}
+
+		if err := r.outbox.MarkDelivered(store, message.ID); err != nil {
+			r.logger.Error("Failed to mark reminder delivered", "id", message.ID, "error", err)
+			continue
+		}
+		delivered++
+	}
+	return delivered
+}
+
+// notify отправляет напоминание во все каналы. Ошибка любого канала
+// приводит к повтору во всех: дубли отсекаются ключом идемпотентности.
+func (r *Relay) notify(ctx context.Context, reminder domain.Reminder) error {
+	var errs []error
+	for _, notifier := range r.notifiers {
+		if err := notifier.Notify(ctx, reminder); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Relay) fail(ctx context.Context, message domain.OutboxMessage, now time.Time, cause error) {
+	attempt := message.Attempts + 1
+	dead := attempt >= r.maxAttempts
+	next := now.Add(RetryDelay(r.backoff, attempt))
+
+	if dead {
+		r.logger.Error("Reminder moved to dead letter", "id", message.ID, "attempts", attempt, "error", cause)
+	} else {
+		r.logger.Warn("Reminder delivery failed", "id", message.ID, "attempt", attempt, "retryAt", next, "error", cause)
+	}
+
+	if err := r.outbox.MarkFailed(ctx, message.ID, next, cause.Error(), dead); err != nil {
+		r.logger.Error("Failed to record reminder failure", "id", message.ID, "error", err)
+	}
+}
+
+// RetryDelay возвращает задержку перед повтором после attempt-й неудачной
+// попытки: base, 2·base, 4·base… но не больше часа.
+func RetryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
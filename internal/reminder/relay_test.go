@@ -0,0 +1,167 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Claim(ctx context.Context, now time.Time, lead, lease time.Duration, limit int) ([]domain.OutboxMessage, error) {
+	args := m.Called(ctx, now, lead, lease, limit)
+	return args.Get(0).([]domain.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string, dead bool) error {
+	return m.Called(ctx, id, nextAttemptAt, lastError, dead).Error(0)
+}
+
+func (m *MockOutboxRepository) Expire(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, r domain.Reminder) error {
+	return m.Called(ctx, r).Error(0)
+}
+
+func newRelay(outbox reminder.OutboxRepository, notifiers ...reminder.Notifier) *reminder.Relay {
+	cfg := reminder.Config{
+		Interval:     time.Minute,
+		Lead:         15 * time.Minute,
+		MaxAttempts:  3,
+		RetryBackoff: 30 * time.Second,
+	}
+	return reminder.NewRelay(outbox, notifiers, cfg, discardLogger())
+}
+
+func TestDeliver(t *testing.T) {
+	now := time.Date(2025, 1, 2, 8, 50, 0, 0, time.UTC)
+	message := func(attempts int) domain.OutboxMessage {
+		return domain.OutboxMessage{
+			ID:       10,
+			Attempts: attempts,
+			Reminder: domain.Reminder{UserID: 42, ScheduleID: 1, Medication: "Aspirin", DueAt: now.Add(10 * time.Minute)},
+		}
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Claim", mock.Anything, now, 15*time.Minute, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage{message(0)}, nil)
+		outbox.On("MarkDelivered", mock.Anything, int64(10)).Return(nil)
+
+		notifier := new(MockNotifier)
+		notifier.On("Notify", mock.Anything, message(0).Reminder).Return(nil)
+
+		assert.Equal(t, 1, newRelay(outbox, notifier).Deliver(context.Background(), now))
+		outbox.AssertExpectations(t)
+	})
+
+	t.Run("failure is retried with backoff", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage{message(1)}, nil)
+		outbox.On("MarkFailed", mock.Anything, int64(10), now.Add(time.Minute), "connection refused", false).Return(nil)
+
+		working := new(MockNotifier)
+		working.On("Notify", mock.Anything, mock.Anything).Return(nil)
+		failing := new(MockNotifier)
+		failing.On("Notify", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		assert.Equal(t, 0, newRelay(outbox, working, failing).Deliver(context.Background(), now))
+		outbox.AssertExpectations(t)
+		outbox.AssertNotCalled(t, "MarkDelivered", mock.Anything, mock.Anything)
+	})
+
+	t.Run("last attempt goes to dead letter", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage{message(2)}, nil)
+		outbox.On("MarkFailed", mock.Anything, int64(10), mock.Anything, "timeout", true).Return(nil)
+
+		failing := new(MockNotifier)
+		failing.On("Notify", mock.Anything, mock.Anything).Return(errors.New("timeout"))
+
+		assert.Equal(t, 0, newRelay(outbox, failing).Deliver(context.Background(), now))
+		outbox.AssertExpectations(t)
+	})
+
+	t.Run("shutdown interrupts delivery", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		second := message(0)
+		second.ID = 11
+
+		outbox := new(MockOutboxRepository)
+		outbox.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage{message(0), second}, nil)
+
+		stuck := new(MockNotifier)
+		stuck.On("Notify", mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+			cancel()
+		}).Return(context.Canceled)
+
+		assert.Equal(t, 0, newRelay(outbox, stuck).Deliver(ctx, now))
+		stuck.AssertNumberOfCalls(t, "Notify", 1)
+		outbox.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage(nil), errors.New("db error"))
+
+		assert.Equal(t, 0, newRelay(outbox, new(MockNotifier)).Deliver(context.Background(), now))
+	})
+
+	t.Run("stale reminders expire before claim", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Expire", mock.Anything, now.Add(-time.Hour)).Return(int64(3), nil)
+		outbox.On("Claim", mock.Anything, now, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.OutboxMessage(nil), nil)
+
+		cfg := reminder.Config{Lead: 15 * time.Minute, MaxAttempts: 3, MaxAge: time.Hour}
+		relay := reminder.NewRelay(outbox, nil, cfg, discardLogger())
+
+		assert.Equal(t, 0, relay.Deliver(context.Background(), now))
+		outbox.AssertExpectations(t)
+	})
+
+	t.Run("expire error skips the pass", func(t *testing.T) {
+		outbox := new(MockOutboxRepository)
+		outbox.On("Expire", mock.Anything, mock.Anything).Return(int64(0), errors.New("db error"))
+
+		cfg := reminder.Config{Lead: 15 * time.Minute, MaxAttempts: 3, MaxAge: time.Hour}
+		relay := reminder.NewRelay(outbox, nil, cfg, discardLogger())
+
+		assert.Equal(t, 0, relay.Deliver(context.Background(), now))
+		outbox.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	base := 30 * time.Second
+
+	assert.Equal(t, 30*time.Second, reminder.RetryDelay(base, 1))
+	assert.Equal(t, time.Minute, reminder.RetryDelay(base, 2))
+	assert.Equal(t, 2*time.Minute, reminder.RetryDelay(base, 3))
+	assert.Equal(t, time.Hour, reminder.RetryDelay(base, 20))
+}
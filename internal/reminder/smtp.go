@@ -0,0 +1,121 @@
+package reminder
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpTimeout ограничивает отправку одного письма: от соединения до QUIT.
+const smtpTimeout = 30 * time.Second
+
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+// Notify отправляет напоминание письмом на адрес пациента. Пациенту без
+// адреса письмо не отправляется. Отправка прерывается по отмене ctx и не
+// длится дольше smtpTimeout. STARTTLS используется, если сервер его
+// поддерживает; аутентификация — только если задано имя пользователя.
+func (n *SMTPNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	if reminder.Email == "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(n.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// Отмена ctx прерывает и уже начатый обмен с сервером.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if err := n.send(client, host, reminder); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) send(client *smtp.Client, host string, reminder domain.Reminder) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(reminder.Email); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.message(reminder)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) message(reminder domain.Reminder) []byte {
+	subject := mime.QEncoding.Encode("utf-8", "Medication reminder: "+reminder.Medication)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", reminder.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	// Повторная доставка даёт тот же Message-ID, по нему почтовые клиенты
+	// склеивают дубли.
+	fmt.Fprintf(&b, "Message-ID: <%s@medication-scheduler>\r\n", reminder.IdempotencyKey())
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Time to take %s at %s.\r\n", reminder.Medication, reminder.DueAt.Format("15:04 (MST)"))
+	return []byte(b.String())
+}
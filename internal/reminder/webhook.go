@@ -0,0 +1,61 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+type webhookPayload struct {
+	UserID     int       `json:"user_id"`
+	ScheduleID int       `json:"schedule_id"`
+	Medication string    `json:"medication"`
+	DueAt      time.Time `json:"due_at"`
+}
+
+// Notify отправляет напоминание POST-запросом с JSON и заголовком
+// Idempotency-Key; любой ответ, кроме 2xx, считается ошибкой доставки.
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	body, err := json.Marshal(webhookPayload{
+		UserID:     reminder.UserID,
+		ScheduleID: reminder.ScheduleID,
+		Medication: reminder.Medication,
+		DueAt:      reminder.DueAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", reminder.IdempotencyKey())
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"time"
+)
+
+// anchorRow — привязка приёма к событию в колонке anchors; смещение в минутах.
+type anchorRow struct {
+	Event     string `json:"event"`
+	OffsetMin int    `json:"offset_min"`
+}
+
+func encodeAnchors(anchors []domain.Anchor) ([]byte, error) {
+	rows := make([]anchorRow, 0, len(anchors))
+	for _, anchor := range anchors {
+		rows = append(rows, anchorRow{
+			Event:     string(anchor.Event),
+			OffsetMin: int(anchor.Offset / time.Minute),
+		})
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schedule anchors: %w", err)
+	}
+	return data, nil
+}
+
+func decodeAnchors(data []byte) ([]domain.Anchor, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var rows []anchorRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule anchors: %w", err)
+	}
+
+	var anchors []domain.Anchor
+	for _, row := range rows {
+		anchors = append(anchors, domain.Anchor{
+			Event:  domain.DailyEvent(row.Event),
+			Offset: time.Duration(row.OffsetMin) * time.Minute,
+		})
+	}
+	return anchors, nil
+}
+
+// encodeEvents сохраняет время событий дня в минутах от полуночи.
+func encodeEvents(events domain.DailyEvents) ([]byte, error) {
+	minutes := make(map[string]int, len(events))
+	for event, clock := range events {
+		minutes[string(event)] = int(clock / time.Minute)
+	}
+
+	data, err := json.Marshal(minutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode daily events: %w", err)
+	}
+	return data, nil
+}
+
+func decodeEvents(data []byte) (domain.DailyEvents, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var minutes map[string]int
+	if err := json.Unmarshal(data, &minutes); err != nil {
+		return nil, fmt.Errorf("failed to decode daily events: %w", err)
+	}
+
+	events := make(domain.DailyEvents, len(minutes))
+	for event, clock := range minutes {
+		events[domain.DailyEvent(event)] = time.Duration(clock) * time.Minute
+	}
+	return events, nil
+}
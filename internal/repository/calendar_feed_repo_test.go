@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveCalendarFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewCalendarFeedRepository(mockDB)
+
+		mockDB.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "ON CONFLICT (user_id)") }),
+			[]interface{}{2, "hash", 1},
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		require.NoError(t, repo.Save(clinic, 2, "hash"))
+	})
+
+	t.Run("User of another organization", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewCalendarFeedRepository(mockDB)
+
+		mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{9, "hash", 1}).
+			Return(pgconn.NewCommandTag("INSERT 0 0"), nil)
+
+		assert.ErrorIs(t, repo.Save(clinic, 9, "hash"), myerrors.ErrUserNotFound)
+	})
+
+	t.Run("Without organization", func(t *testing.T) {
+		repo := repository.NewCalendarFeedRepository(new(MockDB))
+
+		assert.ErrorIs(t, repo.Save(context.Background(), 2, "hash"), myerrors.ErrTenantRequired)
+	})
+}
+
+func TestDeleteCalendarFeed(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewCalendarFeedRepository(mockDB)
+
+	mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{2, 1}).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil)
+	mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{3, 1}).
+		Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	require.NoError(t, repo.Delete(clinic, 2))
+	assert.ErrorIs(t, repo.Delete(clinic, 3), myerrors.ErrCalendarFeedNotFound)
+}
+
+func TestResolveCalendarFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewCalendarFeedRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*int")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 2
+				*args.Get(1).(*int) = 5
+			}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{"hash"}).Return(mockRow)
+
+		identity, err := repo.Resolve(context.Background(), "hash")
+		require.NoError(t, err)
+		assert.Equal(t, auth.Identity{UserID: 2, OrganizationID: 5}, identity)
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewCalendarFeedRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{"stale"}).Return(mockRow)
+
+		_, err := repo.Resolve(context.Background(), "stale")
+		assert.ErrorIs(t, err, myerrors.ErrCalendarFeedNotFound)
+	})
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CalendarFeedRepository хранит подписки на календарь: у пользователя не
+// больше одной, от токена хранится только хеш.
+type CalendarFeedRepository struct {
+	db DB
+}
+
+func NewCalendarFeedRepository(db DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// Save выдаёт пользователю подписку с токеном tokenHash взамен прежней.
+// Пользователь другой организации — ErrUserNotFound.
+func (r *CalendarFeedRepository) Save(ctx context.Context, userID int, tokenHash string) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+        INSERT INTO calendar_feeds (user_id, token_hash)
+        SELECT id, $2 FROM users WHERE id = $1 AND organization_id = $3
+        ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()`,
+		userID, tokenHash, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete отзывает подписку пользователя.
+func (r *CalendarFeedRepository) Delete(ctx context.Context, userID int) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+        DELETE FROM calendar_feeds f USING users u
+        WHERE u.id = f.user_id AND f.user_id = $1 AND u.organization_id = $2`, userID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// Resolve возвращает владельца подписки с токеном tokenHash. Организация
+// в контексте не нужна: запрос приходит без входа, и её определяет токен.
+func (r *CalendarFeedRepository) Resolve(ctx context.Context, tokenHash string) (auth.Identity, error) {
+	var identity auth.Identity
+	err := r.db.QueryRow(ctx, `
+        SELECT u.id, u.organization_id
+        FROM calendar_feeds f
+        JOIN users u ON u.id = f.user_id
+        WHERE f.token_hash = $1`, tokenHash,
+	).Scan(&identity.UserID, &identity.OrganizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return auth.Identity{}, myerrors.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return auth.Identity{}, fmt.Errorf("failed to fetch calendar feed: %w", err)
+	}
+	return identity, nil
+}
@@ -0,0 +1,170 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDose(t *testing.T) {
+	mockDB, tx := newMockTx()
+	repo := repository.NewDoseRepository(mockDB)
+
+	event := &domain.DoseEvent{
+		UserID:     1,
+		ScheduleID: 2,
+		PlannedAt:  time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+		Status:     domain.DoseSkipped,
+		Reason:     "nausea",
+	}
+	recordedAt := time.Date(2025, 1, 1, 9, 5, 0, 0, time.UTC)
+
+	mockRow := new(MockRow)
+	mockRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*time.Time")).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 5
+			*args.Get(1).(*time.Time) = recordedAt
+		}).Return(nil)
+
+	tx.On("QueryRow",
+		mock.Anything,
+		mock.Anything,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 8 &&
+				args[0] == 1 &&
+				args[1] == 2 &&
+				args[3] == domain.DoseSkipped &&
+				args[5] == (*time.Time)(nil) &&
+				args[6] == (*time.Time)(nil) &&
+				args[7] == 1
+		}),
+	).Return(mockRow)
+
+	tx.On("Exec",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "SET status = 'cancelled'") }),
+		[]interface{}{2, event.PlannedAt, 1},
+	).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	require.NoError(t, repo.Record(clinic, event))
+	assert.Equal(t, 5, event.ID)
+	assert.Equal(t, recordedAt, event.RecordedAt)
+	mockDB.AssertExpectations(t)
+	tx.AssertExpectations(t)
+}
+
+func TestRecordDose_SnoozeEnqueuesReminder(t *testing.T) {
+	mockDB, tx := newMockTx()
+	repo := repository.NewDoseRepository(mockDB)
+
+	plannedAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	event := &domain.DoseEvent{
+		UserID:       1,
+		ScheduleID:   2,
+		PlannedAt:    plannedAt,
+		Status:       domain.DoseSnoozed,
+		SnoozedUntil: plannedAt.Add(30 * time.Minute),
+	}
+
+	mockRow := new(MockRow)
+	mockRow.On("Scan", mock.Anything, mock.Anything).Return(nil)
+	tx.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	tx.On("Exec", mock.Anything, mock.Anything, []interface{}{2, plannedAt, 1}).
+		Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	tx.On("Exec",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO outbox") }),
+		[]interface{}{"schedule-2-2025-01-01T09:30:00Z:snooze", event.SnoozedUntil, 2, 1, plannedAt, domain.ReminderSnooze},
+	).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	require.NoError(t, repo.Record(clinic, event))
+	tx.AssertExpectations(t)
+}
+
+func TestRecordDose_ScheduleOfAnotherOrganization(t *testing.T) {
+	mockDB, tx := newMockTx()
+	repo := repository.NewDoseRepository(mockDB)
+
+	mockRow := new(MockRow)
+	mockRow.On("Scan", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+	tx.On("QueryRow",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "WHERE id = $2 AND organization_id = $8") }),
+		mock.Anything,
+	).Return(mockRow)
+
+	event := &domain.DoseEvent{
+		UserID:     1,
+		ScheduleID: 2,
+		PlannedAt:  time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+		Status:     domain.DoseTaken,
+	}
+	assert.ErrorIs(t, repo.Record(clinic, event), myerrors.ErrScheduleNotFound)
+	tx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Отметка «принят» после отсрочки снимает с очереди и повторное напоминание:
+// оно ставится с тем же planned_at, по которому затем снимаются напоминания
+// о приёме.
+func TestRecordDose_TakenAfterSnoozeCancelsSnoozeReminder(t *testing.T) {
+	plannedAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockDB, tx := newMockTx()
+	repo := repository.NewDoseRepository(mockDB)
+
+	mockRow := new(MockRow)
+	mockRow.On("Scan", mock.Anything, mock.Anything).Return(nil)
+	tx.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	require.NoError(t, repo.Record(clinic, &domain.DoseEvent{
+		UserID:       1,
+		ScheduleID:   2,
+		PlannedAt:    plannedAt,
+		Status:       domain.DoseSnoozed,
+		SnoozedUntil: plannedAt.Add(30 * time.Minute),
+	}))
+	require.NoError(t, repo.Record(clinic, &domain.DoseEvent{
+		UserID:     1,
+		ScheduleID: 2,
+		PlannedAt:  plannedAt,
+		Status:     domain.DoseTaken,
+		TakenAt:    plannedAt.Add(35 * time.Minute),
+	}))
+
+	// pending — ключ (schedule_id, planned_at) напоминаний, оставшихся в
+	// очереди после каждой операции.
+	type reminderOf struct {
+		scheduleID int
+		plannedAt  time.Time
+	}
+	pending := map[reminderOf]int{}
+	for _, call := range tx.Calls {
+		if call.Method != "Exec" {
+			continue
+		}
+		sql, args := call.Arguments.String(1), call.Arguments.Get(2).([]interface{})
+		switch {
+		case strings.Contains(sql, "INSERT INTO outbox"):
+			assert.Equal(t, domain.ReminderSnooze, args[5])
+			pending[reminderOf{args[2].(int), args[4].(time.Time)}]++
+		case strings.Contains(sql, "SET status = 'cancelled'"):
+			assert.Contains(t, sql, "planned_at = $2")
+			assert.NotContains(t, sql, "idempotency_key")
+			delete(pending, reminderOf{args[0].(int), args[1].(time.Time)})
+		}
+	}
+	assert.Empty(t, pending, "Expected no reminder left pending")
+}
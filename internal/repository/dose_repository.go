@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type DoseRepository struct {
+	db DB
+}
+
+func NewDoseRepository(db DB) *DoseRepository {
+	return &DoseRepository{db: db}
+}
+
+// Record сохраняет отметку о приёме; повторная отметка того же приёма
+// заменяет предыдущую. В той же транзакции снимаются с очереди все
+// напоминания об этом приёме, включая повторное после прежней отсрочки, а
+// при новой отсрочке ставится повторное — на время её окончания.
+func (r *DoseRepository) Record(ctx context.Context, event *domain.DoseEvent) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+        INSERT INTO dose_events
+            (user_id, schedule_id, planned_at, status, reason, snoozed_until, taken_at, organization_id)
+        SELECT $1, id, $3, $4, $5, $6, $7, organization_id FROM schedules
+        WHERE id = $2 AND organization_id = $8
+        ON CONFLICT (schedule_id, planned_at) DO UPDATE
+        SET status = EXCLUDED.status, reason = EXCLUDED.reason,
+            snoozed_until = EXCLUDED.snoozed_until, taken_at = EXCLUDED.taken_at,
+            recorded_at = NOW()
+        RETURNING id, recorded_at`,
+			event.UserID,
+			event.ScheduleID,
+			event.PlannedAt,
+			event.Status,
+			event.Reason,
+			nullableTime(event.SnoozedUntil),
+			nullableTime(event.TakenAt),
+			organizationID,
+		).Scan(&event.ID, &event.RecordedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return myerrors.ErrScheduleNotFound
+			}
+			return fmt.Errorf("failed to record dose: %w", err)
+		}
+
+		if err := cancelReminders(ctx, tx, organizationID, event.ScheduleID, event.PlannedAt); err != nil {
+			return err
+		}
+		if event.Status != domain.DoseSnoozed {
+			return nil
+		}
+
+		// Повторная отсрочка до того же времени возвращает в очередь
+		// напоминание, только что снятое выше.
+		_, err = tx.Exec(ctx, `
+        INSERT INTO outbox (idempotency_key, user_id, schedule_id, medication, due_at, planned_at, kind, organization_id)
+        SELECT $1, user_id, id, medication, $2, $5, $6, organization_id FROM schedules
+        WHERE id = $3 AND organization_id = $4
+        ON CONFLICT (idempotency_key) DO UPDATE SET status = 'pending', planned_at = EXCLUDED.planned_at
+        WHERE outbox.status = 'cancelled'`,
+			domain.Reminder{ScheduleID: event.ScheduleID, DueAt: event.SnoozedUntil, Snooze: true}.IdempotencyKey(),
+			event.SnoozedUntil, event.ScheduleID, organizationID, event.PlannedAt, domain.ReminderSnooze)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue snoozed reminder: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListByUser возвращает отметки пользователя о приёмах, запланированных в
+// [from, to). Нулевой scheduleID означает все расписания.
+func (r *DoseRepository) ListByUser(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT id, user_id, schedule_id, planned_at, status, reason, snoozed_until, taken_at, recorded_at
+        FROM dose_events
+        WHERE organization_id = $5 AND user_id = $1 AND ($2 = 0 OR schedule_id = $2)
+            AND planned_at >= $3 AND planned_at < $4
+        ORDER BY planned_at`, userID, scheduleID, from, to, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch doses: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.DoseEvent
+	for rows.Next() {
+		var (
+			event        domain.DoseEvent
+			snoozedUntil *time.Time
+			takenAt      *time.Time
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.ScheduleID,
+			&event.PlannedAt,
+			&event.Status,
+			&event.Reason,
+			&snoozedUntil,
+			&takenAt,
+			&event.RecordedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan dose: %w", err)
+		}
+		if snoozedUntil != nil {
+			event.SnoozedUntil = *snoozedUntil
+		}
+		if takenAt != nil {
+			event.TakenAt = *takenAt
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,142 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func grantScanArgs() []interface{} {
+	return []interface{}{
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("*domain.AccessLevel"),
+		mock.AnythingOfType("*domain.GrantStatus"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("**time.Time"),
+	}
+}
+
+func TestUpsertGrant(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewGrantRepository(mockDB)
+	createdAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	mockRow := new(MockRow)
+	mockRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*domain.GrantStatus"),
+		mock.AnythingOfType("*time.Time"), mock.AnythingOfType("**time.Time")).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(1).(*domain.GrantStatus) = domain.GrantPending
+			*args.Get(2).(*time.Time) = createdAt
+		}).Return(nil)
+	mockDB.On("QueryRow",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "ON CONFLICT (patient_id, caregiver_id)") }),
+		[]interface{}{1, 2, domain.AccessRead, 1},
+	).Return(mockRow)
+
+	grant := &domain.Grant{PatientID: 1, CaregiverID: 2, Access: domain.AccessRead}
+	require.NoError(t, repo.Upsert(clinic, grant))
+	assert.Equal(t, 3, grant.ID)
+	assert.Equal(t, domain.GrantPending, grant.Status)
+	assert.Equal(t, createdAt, grant.CreatedAt)
+	assert.True(t, grant.AcceptedAt.IsZero())
+}
+
+func TestGetGrant(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewGrantRepository(mockDB)
+		acceptedAt := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", grantScanArgs()...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(1).(*int) = 1
+			*args.Get(3).(*int) = 2
+			*args.Get(4).(*string) = "nurse@example.com"
+			*args.Get(5).(*domain.AccessLevel) = domain.AccessManage
+			*args.Get(6).(*domain.GrantStatus) = domain.GrantAccepted
+			*args.Get(8).(**time.Time) = &acceptedAt
+		}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{1, 1, 2}).Return(mockRow)
+
+		grant, err := repo.Get(clinic, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "nurse@example.com", grant.CaregiverEmail)
+		assert.True(t, grant.Allows(domain.AccessManage))
+		assert.Equal(t, acceptedAt, grant.AcceptedAt)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewGrantRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", grantScanArgs()...).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+
+		_, err := repo.Get(clinic, 1, 2)
+		assert.ErrorIs(t, err, myerrors.ErrGrantNotFound)
+	})
+}
+
+func TestListGrantsByCaregiver(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewGrantRepository(mockDB)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Return(true).Twice()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", grantScanArgs()...).Run(func(args mock.Arguments) {
+		*args.Get(3).(*int) = 2
+	}).Return(nil)
+	mockRows.On("Close").Return()
+	mockRows.On("Err").Return(nil)
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "AND g.caregiver_id = $2") }),
+		[]interface{}{1, 2},
+	).Return(mockRows, nil)
+
+	grants, err := repo.ListByCaregiver(clinic, 2)
+	require.NoError(t, err)
+	assert.Len(t, grants, 2)
+	mockRows.AssertExpectations(t)
+}
+
+func TestAcceptGrant(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewGrantRepository(mockDB)
+		mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{3, 2, now, 1}).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		assert.NoError(t, repo.Accept(clinic, 3, 2, now))
+	})
+
+	t.Run("Addressed to someone else", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewGrantRepository(mockDB)
+		mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{3, 5, now, 1}).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		assert.ErrorIs(t, repo.Accept(clinic, 3, 5, now), myerrors.ErrGrantNotFound)
+	})
+}
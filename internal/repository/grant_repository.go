@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// grantSelect выбирает колонки в порядке, который ожидает scanGrant. У
+// пациента может не быть учётной записи, если его user_id выдан до их
+// появления. Первый параметр запроса — организация.
+const grantSelect = `
+        SELECT g.id, g.patient_id, COALESCE(p.email, ''), g.caregiver_id, c.email,
+               g.access, g.status, g.created_at, g.accepted_at
+        FROM care_grants g
+        LEFT JOIN users p ON p.id = g.patient_id AND p.organization_id = g.organization_id
+        JOIN users c ON c.id = g.caregiver_id AND c.organization_id = g.organization_id
+        WHERE g.organization_id = $1`
+
+type GrantRepository struct {
+	db DB
+}
+
+func NewGrantRepository(db DB) *GrantRepository {
+	return &GrantRepository{db: db}
+}
+
+// Upsert создаёт приглашение или, если доступ уже выдан, меняет его права,
+// не меняя статус. Заполняет ID, Status, CreatedAt и AcceptedAt.
+func (r *GrantRepository) Upsert(ctx context.Context, grant *domain.Grant) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	var acceptedAt *time.Time
+	err = r.db.QueryRow(ctx, `
+        INSERT INTO care_grants (patient_id, caregiver_id, access, organization_id)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (patient_id, caregiver_id) DO UPDATE SET access = EXCLUDED.access
+        WHERE care_grants.organization_id = EXCLUDED.organization_id
+        RETURNING id, status, created_at, accepted_at`,
+		grant.PatientID, grant.CaregiverID, grant.Access, organizationID,
+	).Scan(&grant.ID, &grant.Status, &grant.CreatedAt, &acceptedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return myerrors.ErrGrantNotFound
+		}
+		return fmt.Errorf("failed to save grant: %w", err)
+	}
+	if acceptedAt != nil {
+		grant.AcceptedAt = *acceptedAt
+	}
+	return nil
+}
+
+func (r *GrantRepository) Get(ctx context.Context, patientID, caregiverID int) (*domain.Grant, error) {
+	return r.getOne(ctx, grantSelect+`
+        AND g.patient_id = $2 AND g.caregiver_id = $3`, patientID, caregiverID)
+}
+
+func (r *GrantRepository) GetByID(ctx context.Context, grantID int) (*domain.Grant, error) {
+	return r.getOne(ctx, grantSelect+`
+        AND g.id = $2`, grantID)
+}
+
+// ListByPatient возвращает доступы, выданные пациентом.
+func (r *GrantRepository) ListByPatient(ctx context.Context, patientID int) ([]domain.Grant, error) {
+	return r.list(ctx, grantSelect+`
+        AND g.patient_id = $2
+        ORDER BY g.created_at, g.id`, patientID)
+}
+
+// ListByCaregiver возвращает доступы и приглашения, полученные
+// доверенным лицом.
+func (r *GrantRepository) ListByCaregiver(ctx context.Context, caregiverID int) ([]domain.Grant, error) {
+	return r.list(ctx, grantSelect+`
+        AND g.caregiver_id = $2
+        ORDER BY g.created_at, g.id`, caregiverID)
+}
+
+// Accept принимает приглашение, адресованное caregiverID. Повторное
+// принятие не меняет исходный момент.
+func (r *GrantRepository) Accept(ctx context.Context, grantID, caregiverID int, now time.Time) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+        UPDATE care_grants
+        SET status = 'accepted', accepted_at = COALESCE(accepted_at, $3)
+        WHERE id = $1 AND caregiver_id = $2 AND organization_id = $4`,
+		grantID, caregiverID, now, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to accept grant: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrGrantNotFound
+	}
+	return nil
+}
+
+func (r *GrantRepository) Delete(ctx context.Context, grantID int) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM care_grants WHERE id = $1 AND organization_id = $2`,
+		grantID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete grant: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrGrantNotFound
+	}
+	return nil
+}
+
+// getOne и list выполняют запросы на основе grantSelect, подставляя
+// организацию из контекста первым параметром.
+func (r *GrantRepository) getOne(ctx context.Context, sql string, args ...interface{}) (*domain.Grant, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grant, err := scanGrant(r.db.QueryRow(ctx, sql, append([]interface{}{organizationID}, args...)...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, myerrors.ErrGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch grant: %w", err)
+	}
+	return grant, nil
+}
+
+func (r *GrantRepository) list(ctx context.Context, sql string, args ...interface{}) ([]domain.Grant, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, append([]interface{}{organizationID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch grants: %w", err)
+	}
+	defer rows.Close()
+
+	var grants []domain.Grant
+	for rows.Next() {
+		grant, err := scanGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan grant: %w", err)
+		}
+		grants = append(grants, *grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate grants: %w", err)
+	}
+	return grants, nil
+}
+
+func scanGrant(row pgx.Row) (*domain.Grant, error) {
+	var (
+		grant      domain.Grant
+		acceptedAt *time.Time
+	)
+	err := row.Scan(&grant.ID, &grant.PatientID, &grant.PatientEmail, &grant.CaregiverID, &grant.CaregiverEmail,
+		&grant.Access, &grant.Status, &grant.CreatedAt, &acceptedAt)
+	if err != nil {
+		return nil, err
+	}
+	if acceptedAt != nil {
+		grant.AcceptedAt = *acceptedAt
+	}
+	return &grant, nil
+}
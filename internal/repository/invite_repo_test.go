@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInvite(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewInviteRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 4
+				*args.Get(1).(*time.Time) = now
+			}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{1, "hash", 2, now.Add(domain.InviteTTL)}).
+			Return(mockRow)
+
+		invite := &domain.Invite{CreatedBy: 2, ExpiresAt: now.Add(domain.InviteTTL)}
+		require.NoError(t, repo.Create(clinic, invite, "hash"))
+		assert.Equal(t, 4, invite.ID)
+		assert.Equal(t, 1, invite.OrganizationID)
+		assert.Equal(t, now, invite.CreatedAt)
+	})
+
+	t.Run("Without organization", func(t *testing.T) {
+		repo := repository.NewInviteRepository(new(MockDB))
+
+		err := repo.Create(context.Background(), &domain.Invite{CreatedBy: 2}, "hash")
+		assert.ErrorIs(t, err, myerrors.ErrTenantRequired)
+	})
+}
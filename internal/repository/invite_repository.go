@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+)
+
+type InviteRepository struct {
+	db DB
+}
+
+func NewInviteRepository(db DB) *InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+// Create сохраняет приглашение в организацию из контекста с токеном
+// tokenHash. Заполняет ID, OrganizationID и CreatedAt.
+func (r *InviteRepository) Create(ctx context.Context, invite *domain.Invite, tokenHash string) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	invite.OrganizationID = organizationID
+	err = r.db.QueryRow(ctx, `
+        INSERT INTO organization_invites (organization_id, token_hash, created_by, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at`,
+		organizationID, tokenHash, invite.CreatedBy, invite.ExpiresAt,
+	).Scan(&invite.ID, &invite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
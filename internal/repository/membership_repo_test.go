@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertMembership(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewMembershipRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*time.Time"), mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*time.Time) = now
+				*args.Get(1).(*time.Time) = now
+			}).Return(nil)
+		mockDB.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "ON CONFLICT (user_id)") }),
+			[]interface{}{2, domain.RoleNurse, 1},
+		).Return(mockRow)
+
+		membership := &domain.Membership{UserID: 2, Role: domain.RoleNurse}
+		require.NoError(t, repo.Upsert(clinic, membership))
+		assert.Equal(t, now, membership.CreatedAt)
+	})
+
+	t.Run("User of another organization", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewMembershipRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{9, domain.RoleNurse, 1}).Return(mockRow)
+
+		err := repo.Upsert(clinic, &domain.Membership{UserID: 9, Role: domain.RoleNurse})
+		assert.ErrorIs(t, err, myerrors.ErrUserNotFound)
+	})
+
+	t.Run("Without organization", func(t *testing.T) {
+		repo := repository.NewMembershipRepository(new(MockDB))
+
+		err := repo.Upsert(context.Background(), &domain.Membership{UserID: 2, Role: domain.RoleNurse})
+		assert.ErrorIs(t, err, myerrors.ErrTenantRequired)
+	})
+}
+
+func TestMembershipRole(t *testing.T) {
+	t.Run("Member", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewMembershipRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*domain.Role")).Run(func(args mock.Arguments) {
+			*args.Get(0).(*domain.Role) = domain.RoleDoctor
+		}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{2, 1}).Return(mockRow)
+
+		role, err := repo.Role(clinic, 2)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleDoctor, role)
+	})
+
+	t.Run("Outside clinic", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewMembershipRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{3, 1}).Return(mockRow)
+
+		role, err := repo.Role(clinic, 3)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleNone, role)
+	})
+}
+
+func TestListMembershipsByRole(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewMembershipRepository(mockDB)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Return(true).Once()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*string"), mock.AnythingOfType("*domain.Role"),
+		mock.AnythingOfType("*time.Time"), mock.AnythingOfType("*time.Time")).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 7
+			*args.Get(2).(*domain.Role) = domain.RolePatient
+		}).Return(nil)
+	mockRows.On("Close").Return()
+	mockRows.On("Err").Return(nil)
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "WHERE u.organization_id = $1 AND m.role = $2") }),
+		[]interface{}{1, domain.RolePatient},
+	).Return(mockRows, nil)
+
+	memberships, err := repo.ListByRole(clinic, domain.RolePatient)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, 7, memberships[0].UserID)
+}
+
+func TestDeleteMembership(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewMembershipRepository(mockDB)
+	mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{2, 1}).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil)
+	mockDB.On("Exec", mock.Anything, mock.Anything, []interface{}{3, 1}).
+		Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	assert.NoError(t, repo.Delete(clinic, 2))
+	assert.ErrorIs(t, repo.Delete(clinic, 3), myerrors.ErrMembershipNotFound)
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// membershipSelect выбирает колонки в порядке, который ожидает
+// scanMembership.
+const membershipSelect = `
+        SELECT m.user_id, u.email, m.role, m.created_at, m.updated_at
+        FROM memberships m
+        JOIN users u ON u.id = m.user_id`
+
+// MembershipRepository хранит роли в клинике. Запросы ограничены
+// организацией из контекста: роль пользователя другой клиники не видна и
+// не меняется.
+type MembershipRepository struct {
+	db DB
+}
+
+func NewMembershipRepository(db DB) *MembershipRepository {
+	return &MembershipRepository{db: db}
+}
+
+// Upsert назначает пользователю роль. Заполняет CreatedAt и UpdatedAt.
+// Пользователь другой организации — ErrUserNotFound.
+func (r *MembershipRepository) Upsert(ctx context.Context, membership *domain.Membership) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(ctx, `
+        INSERT INTO memberships (user_id, role)
+        SELECT id, $2 FROM users WHERE id = $1 AND organization_id = $3
+        ON CONFLICT (user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = NOW()
+        RETURNING created_at, updated_at`,
+		membership.UserID, membership.Role, organizationID,
+	).Scan(&membership.CreatedAt, &membership.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return myerrors.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save membership: %w", err)
+	}
+	return nil
+}
+
+// Role возвращает роль пользователя; для пользователя вне клиники —
+// domain.RoleNone.
+func (r *MembershipRepository) Role(ctx context.Context, userID int) (domain.Role, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return domain.RoleNone, err
+	}
+
+	var role domain.Role
+	err = r.db.QueryRow(ctx, `
+        SELECT m.role
+        FROM memberships m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.user_id = $1 AND u.organization_id = $2`, userID, organizationID,
+	).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.RoleNone, nil
+	}
+	if err != nil {
+		return domain.RoleNone, fmt.Errorf("failed to fetch role: %w", err)
+	}
+	return role, nil
+}
+
+func (r *MembershipRepository) List(ctx context.Context) ([]domain.Membership, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, membershipSelect+`
+        WHERE u.organization_id = $1
+        ORDER BY m.role, u.email`, organizationID)
+}
+
+func (r *MembershipRepository) ListByRole(ctx context.Context, role domain.Role) ([]domain.Membership, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, membershipSelect+`
+        WHERE u.organization_id = $1 AND m.role = $2
+        ORDER BY u.email`, organizationID, role)
+}
+
+func (r *MembershipRepository) Delete(ctx context.Context, userID int) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+        DELETE FROM memberships m
+        USING users u
+        WHERE u.id = m.user_id AND m.user_id = $1 AND u.organization_id = $2`, userID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete membership: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrMembershipNotFound
+	}
+	return nil
+}
+
+func (r *MembershipRepository) list(ctx context.Context, sql string, args ...interface{}) ([]domain.Membership, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []domain.Membership
+	for rows.Next() {
+		var membership domain.Membership
+		err := rows.Scan(&membership.UserID, &membership.Email, &membership.Role,
+			&membership.CreatedAt, &membership.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate memberships: %w", err)
+	}
+	return memberships, nil
+}
@@ -0,0 +1,34 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"medication-scheduler/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOrganizationIDs(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewOrganizationRepository(mockDB)
+
+	mockRows := new(MockRows)
+	for _, id := range []int{1, 2} {
+		id := id
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", mock.AnythingOfType("*int")).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = id
+		}).Return(nil).Once()
+	}
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Close").Return()
+	mockRows.On("Err").Return(nil)
+	mockDB.On("Query", mock.Anything, mock.Anything, []interface{}(nil)).Return(mockRows, nil)
+
+	ids, err := repo.ListIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+}
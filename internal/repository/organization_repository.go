@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+type OrganizationRepository struct {
+	db DB
+}
+
+func NewOrganizationRepository(db DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// ListIDs возвращает ID всех организаций: фоновые задачи обходят их по
+// одной, чтобы каждый запрос к расписаниям оставался в пределах клиники.
+func (r *OrganizationRepository) ListIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM organizations ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate organizations: %w", err)
+	}
+	return ids, nil
+}
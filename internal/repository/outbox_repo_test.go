@@ -0,0 +1,143 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxEnqueue(t *testing.T) {
+	t.Run("Nothing to enqueue", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewOutboxRepository(mockDB)
+
+		require.NoError(t, repo.Enqueue(context.Background(), nil))
+		mockDB.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Uses idempotency keys", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewOutboxRepository(mockDB)
+
+		dueAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		mockDB.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool {
+				return strings.Contains(sql, "JOIN schedules s ON s.id = r.schedule_id") &&
+					strings.Contains(sql, "ON CONFLICT (idempotency_key) DO NOTHING")
+			}),
+			mock.MatchedBy(func(args []interface{}) bool {
+				return assert.ObjectsAreEqual([]string{"schedule-7-2025-01-01T09:00:00Z"}, args[0]) &&
+					assert.ObjectsAreEqual([]int{7}, args[2]) &&
+					assert.ObjectsAreEqual([]time.Time{dueAt}, args[5]) &&
+					assert.ObjectsAreEqual([]string{"scheduled"}, args[6])
+			}),
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := repo.Enqueue(context.Background(), []domain.Reminder{
+			{UserID: 1, ScheduleID: 7, Medication: "Aspirin", DueAt: dueAt, PlannedAt: dueAt},
+		})
+
+		require.NoError(t, err)
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestOutboxClaim(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewOutboxRepository(mockDB)
+
+	now := time.Date(2025, 1, 1, 8, 50, 0, 0, time.UTC)
+	dueAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	plannedAt := dueAt.Add(-30 * time.Minute)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Once().Return(true)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Scan",
+		mock.AnythingOfType("*int64"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("*domain.ReminderKind"),
+		mock.AnythingOfType("*string"),
+	).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int64) = 10
+		*args.Get(1).(*int) = 1
+		*args.Get(2).(*int) = 7
+		*args.Get(3).(*string) = "Aspirin"
+		*args.Get(4).(*time.Time) = dueAt
+		*args.Get(5).(*time.Time) = plannedAt
+		*args.Get(6).(*int) = 2
+		*args.Get(7).(*string) = "Europe/Moscow"
+		*args.Get(8).(*domain.ReminderKind) = domain.ReminderSnooze
+		*args.Get(9).(*string) = "patient@example.com"
+	}).Return(nil)
+
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "FOR UPDATE SKIP LOCKED") && !strings.Contains(sql, "LIKE")
+		}),
+		[]interface{}{now, now.Add(time.Minute), now.Add(15 * time.Minute), 100},
+	).Return(mockRows, nil)
+
+	messages, err := repo.Claim(context.Background(), now, 15*time.Minute, time.Minute, 100)
+
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, int64(10), messages[0].ID)
+	assert.Equal(t, 2, messages[0].Attempts)
+	assert.True(t, messages[0].Reminder.DueAt.Equal(dueAt))
+	assert.Equal(t, "Europe/Moscow", messages[0].Reminder.DueAt.Location().String())
+	assert.Equal(t, "patient@example.com", messages[0].Reminder.Email)
+	assert.True(t, messages[0].Reminder.Snooze)
+	assert.True(t, messages[0].Reminder.PlannedAt.Equal(plannedAt))
+}
+
+func TestOutboxExpire(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewOutboxRepository(mockDB)
+
+	before := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	mockDB.On("Exec", mock.Anything,
+		mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "status = 'pending' AND due_at < $1")
+		}),
+		[]interface{}{before, domain.OutboxDead},
+	).Return(pgconn.NewCommandTag("UPDATE 2"), nil)
+
+	expired, err := repo.Expire(context.Background(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+	mockDB.AssertExpectations(t)
+}
+
+func TestOutboxMarkFailed(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.NewOutboxRepository(mockDB)
+
+	next := time.Date(2025, 1, 1, 9, 1, 0, 0, time.UTC)
+	mockDB.On("Exec", mock.Anything, mock.Anything,
+		[]interface{}{int64(10), domain.OutboxDead, next, "timeout"},
+	).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+	require.NoError(t, repo.MarkFailed(context.Background(), 10, next, "timeout", true))
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+type OutboxRepository struct {
+	db DB
+}
+
+func NewOutboxRepository(db DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue ставит напоминания в очередь. Уже поставленные (по ключу
+// идемпотентности) и уже отмеченные приёмы пропускаются. Организация
+// напоминания берётся из его расписания.
+func (r *OutboxRepository) Enqueue(ctx context.Context, reminders []domain.Reminder) error {
+	return enqueueReminders(ctx, r.db, reminders)
+}
+
+// Claim забирает до limit сообщений, приём по которым наступит не позже
+// now+lead, и откладывает их повторную выдачу на lease. Если воркер упадёт
+// до MarkDelivered/MarkFailed, сообщение будет выдано снова — доставка
+// «как минимум один раз».
+func (r *OutboxRepository) Claim(ctx context.Context, now time.Time, lead, lease time.Duration, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.Query(ctx, `
+        UPDATE outbox o
+        SET next_attempt_at = $2
+        FROM schedules s
+        WHERE s.id = o.schedule_id AND s.organization_id = o.organization_id AND o.id IN (
+            SELECT id FROM outbox
+            WHERE status = 'pending' AND next_attempt_at <= $1 AND due_at <= $3
+            ORDER BY due_at
+            LIMIT $4
+            FOR UPDATE SKIP LOCKED)
+        RETURNING o.id, o.user_id, o.schedule_id, o.medication, o.due_at, o.planned_at, o.attempts, s.timezone,
+            o.kind, COALESCE((SELECT u.email FROM users u
+                      WHERE u.id = o.user_id AND u.organization_id = o.organization_id), '')`,
+		now, now.Add(lease), now.Add(lead), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var (
+			message  domain.OutboxMessage
+			timezone string
+			kind     domain.ReminderKind
+		)
+		if err := rows.Scan(
+			&message.ID,
+			&message.Reminder.UserID,
+			&message.Reminder.ScheduleID,
+			&message.Reminder.Medication,
+			&message.Reminder.DueAt,
+			&message.Reminder.PlannedAt,
+			&message.Attempts,
+			&timezone,
+			&kind,
+			&message.Reminder.Email,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		schedule := domain.Schedule{Timezone: timezone}
+		message.Reminder.DueAt = message.Reminder.DueAt.In(schedule.Location())
+		message.Reminder.PlannedAt = message.Reminder.PlannedAt.In(schedule.Location())
+		message.Reminder.Snooze = kind == domain.ReminderSnooze
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE outbox SET status = 'delivered', attempts = attempts + 1, delivered_at = NOW()
+        WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed фиксирует неудачную попытку. Сообщение вернётся в очередь в
+// nextAttemptAt, а при dead = true уходит в dead letter.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status := domain.OutboxPending
+	if dead {
+		status = domain.OutboxDead
+	}
+
+	_, err := r.db.Exec(ctx, `
+        UPDATE outbox SET status = $2, attempts = attempts + 1, next_attempt_at = $3, last_error = $4
+        WHERE id = $1`, id, status, nextAttemptAt, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+	return nil
+}
+
+// Expire переносит в dead letter недоставленные напоминания о приёмах
+// раньше before и возвращает их число.
+func (r *OutboxRepository) Expire(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+        UPDATE outbox SET status = $2, last_error = 'expired: reminder is too late to deliver'
+        WHERE status = 'pending' AND due_at < $1`, before, domain.OutboxDead)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire outbox messages: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func enqueueReminders(ctx context.Context, db execer, reminders []domain.Reminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	var (
+		keys        = make([]string, 0, len(reminders))
+		userIDs     = make([]int, 0, len(reminders))
+		scheduleIDs = make([]int, 0, len(reminders))
+		medications = make([]string, 0, len(reminders))
+		dueAts      = make([]time.Time, 0, len(reminders))
+		plannedAts  = make([]time.Time, 0, len(reminders))
+		kinds       = make([]string, 0, len(reminders))
+	)
+	for _, reminder := range reminders {
+		keys = append(keys, reminder.IdempotencyKey())
+		userIDs = append(userIDs, reminder.UserID)
+		scheduleIDs = append(scheduleIDs, reminder.ScheduleID)
+		medications = append(medications, reminder.Medication)
+		dueAts = append(dueAts, reminder.DueAt)
+		plannedAts = append(plannedAts, reminder.PlannedAt)
+		kinds = append(kinds, string(reminder.Kind()))
+	}
+
+	_, err := db.Exec(ctx, `
+        INSERT INTO outbox (idempotency_key, user_id, schedule_id, medication, due_at, planned_at, kind, organization_id)
+        SELECT r.key, r.user_id, r.schedule_id, r.medication, r.due_at, r.planned_at, r.kind, s.organization_id
+        FROM unnest($1::text[], $2::int[], $3::int[], $4::text[], $5::timestamptz[], $6::timestamptz[], $7::text[])
+            AS r(key, user_id, schedule_id, medication, due_at, planned_at, kind)
+        JOIN schedules s ON s.id = r.schedule_id AND s.user_id = r.user_id
+        WHERE NOT EXISTS (
+            SELECT 1 FROM dose_events d
+            WHERE d.organization_id = s.organization_id AND d.schedule_id = r.schedule_id AND d.planned_at = r.planned_at)
+        ON CONFLICT (idempotency_key) DO NOTHING`,
+		keys, userIDs, scheduleIDs, medications, dueAts, plannedAts, kinds)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue reminders: %w", err)
+	}
+	return nil
+}
+
+// cancelReminders снимает с очереди ещё не доставленные напоминания о
+// приёме plannedAt — и запланированное, и повторные после отсрочки.
+func cancelReminders(ctx context.Context, db execer, organizationID, scheduleID int, plannedAt time.Time) error {
+	_, err := db.Exec(ctx, `
+        UPDATE outbox SET status = 'cancelled'
+        WHERE schedule_id = $1 AND planned_at = $2 AND organization_id = $3 AND status = 'pending'`,
+		scheduleID, plannedAt, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel reminders: %w", err)
+	}
+	return nil
+}
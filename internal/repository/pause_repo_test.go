@@ -0,0 +1,169 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseSchedule(t *testing.T) {
+	pausedAt := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.New(mockDB)
+
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "UPDATE schedules SET paused_at = $2") }),
+			[]interface{}{5, pausedAt, 1},
+		).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "DELETE FROM outbox") }),
+			[]interface{}{5, pausedAt, 1},
+		).Return(pgconn.NewCommandTag("DELETE 2"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		require.NoError(t, repo.Pause(clinic, 5, pausedAt))
+		tx.AssertExpectations(t)
+		assertNoRevisionChanges(t, tx)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.New(mockDB)
+
+		tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil).Once()
+
+		assert.ErrorIs(t, repo.Pause(clinic, 6, pausedAt), myerrors.ErrScheduleNotFound)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+func TestResumeSchedule(t *testing.T) {
+	schedule := domain.Schedule{
+		ID:         5,
+		UserID:     2,
+		Medication: "Ibuprofen",
+		Kind:       domain.KindInterval,
+		Frequency:  4 * time.Hour,
+		Timezone:   "UTC",
+		EndTime:    domain.PerpetualEndTime,
+	}
+
+	mockDB, tx := newMockTx()
+	repo := repository.New(mockDB)
+
+	tx.On("Exec",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO schedule_pauses") }),
+		mock.MatchedBy(func(args []interface{}) bool { return len(args) == 3 && args[0] == 5 && args[2] == 1 }),
+	).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+	tx.On("Exec",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "UPDATE schedules SET paused_at = NULL") }),
+		[]interface{}{5, 1},
+	).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+	storedRow := new(MockRow)
+	storedRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+		fillScheduleRow(args, schedule)
+	}).Return(nil)
+	tx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{5, 1}).Return(storedRow)
+	tx.On("Exec",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO outbox") }),
+		mock.Anything,
+	).Return(pgconn.NewCommandTag("INSERT 0 6"), nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	require.NoError(t, repo.Resume(clinic, 5))
+	tx.AssertExpectations(t)
+	assertNoRevisionChanges(t, tx)
+}
+
+// Редакция, открытая при изменении приостановленного расписания, не
+// наследует приостановку: после возобновления она бы продолжала действовать.
+func TestUpdatePausedSchedule(t *testing.T) {
+	schedule := &domain.Schedule{
+		ID:         5,
+		UserID:     2,
+		Medication: "Ibuprofen",
+		Kind:       domain.KindInterval,
+		Frequency:  4 * time.Hour,
+		Timezone:   "UTC",
+		EndTime:    domain.PerpetualEndTime,
+		PausedAt:   time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	mockDB, tx := newMockTx()
+	repo := repository.New(mockDB)
+
+	tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	storedRow := new(MockRow)
+	storedRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+		fillScheduleRow(args, *schedule)
+	}).Return(nil)
+	tx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{5, 1}).Return(storedRow)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	require.NoError(t, repo.Update(clinic, schedule))
+
+	var opened bool
+	for _, call := range tx.Calls {
+		if call.Method != "Exec" {
+			continue
+		}
+		if sql := call.Arguments.String(1); strings.Contains(sql, "INSERT INTO schedule_revisions") {
+			opened = true
+			assert.NotContains(t, sql, "paused_at")
+		}
+	}
+	assert.True(t, opened, "Expected a new schedule revision")
+}
+
+// assertNoRevisionChanges проверяет, что в транзакции не менялась история
+// редакций: приостановка и возобновление не должны её удлинять.
+func assertNoRevisionChanges(t *testing.T, tx *MockTx) {
+	t.Helper()
+	for _, call := range tx.Calls {
+		if call.Method != "Exec" {
+			continue
+		}
+		if sql := call.Arguments.String(1); strings.Contains(sql, "schedule_revisions") {
+			t.Errorf("Expected no schedule revision changes, got %q", sql)
+		}
+	}
+}
+
+// pauseRows возвращает MockRows с приостановками расписания scheduleID.
+func pauseRows(scheduleID int, pauses ...domain.Pause) *MockRows {
+	rows := new(MockRows)
+	for _, pause := range pauses {
+		rows.On("Next").Once().Return(true)
+		rows.On("Scan",
+			mock.AnythingOfType("*int"),
+			mock.AnythingOfType("*time.Time"),
+			mock.AnythingOfType("*time.Time"),
+		).Once().Run(func(a mock.Arguments) {
+			*a.Get(0).(*int) = scheduleID
+			*a.Get(1).(*time.Time) = pause.From
+			*a.Get(2).(*time.Time) = pause.To
+		}).Return(nil)
+	}
+	rows.On("Next").Once().Return(false)
+	rows.On("Close").Return(nil)
+	rows.On("Err").Return(nil)
+	return rows
+}
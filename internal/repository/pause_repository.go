@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Pause приостанавливает расписание с момента pausedAt. Назначение при этом
+// не меняется, поэтому новая редакция не создаётся; снимаются лишь ещё не
+// отправленные напоминания о приёмах после pausedAt.
+func (r *ScheduleRepository) Pause(ctx context.Context, scheduleID int, pausedAt time.Time) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+        UPDATE schedules SET paused_at = $2
+        WHERE id = $1 AND organization_id = $3`, scheduleID, pausedAt, organizationID)
+		if err != nil {
+			return fmt.Errorf("failed to pause schedule: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return myerrors.ErrScheduleNotFound
+		}
+
+		if _, err := tx.Exec(ctx, `
+        DELETE FROM outbox WHERE schedule_id = $1 AND organization_id = $3
+            AND status = 'pending' AND due_at >= $2`,
+			scheduleID, pausedAt, organizationID); err != nil {
+			return fmt.Errorf("failed to drop pending reminders: %w", err)
+		}
+		return nil
+	})
+}
+
+// Resume возобновляет расписание. Завершённая приостановка сохраняется
+// отдельно от редакций, чтобы приёмы за её время не считались пропущенными.
+func (r *ScheduleRepository) Resume(ctx context.Context, scheduleID int) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+        INSERT INTO schedule_pauses (schedule_id, paused_at, resumed_at)
+        SELECT id, paused_at, $2 FROM schedules
+        WHERE id = $1 AND organization_id = $3 AND paused_at IS NOT NULL`,
+			scheduleID, now, organizationID); err != nil {
+			return fmt.Errorf("failed to save schedule pause: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, `
+        UPDATE schedules SET paused_at = NULL
+        WHERE id = $1 AND organization_id = $2`, scheduleID, organizationID)
+		if err != nil {
+			return fmt.Errorf("failed to resume schedule: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return myerrors.ErrScheduleNotFound
+		}
+		return syncReminders(ctx, tx, organizationID, scheduleID)
+	})
+}
+
+// attachPauses дополняет расписания их завершёнными приостановками.
+func (r *ScheduleRepository) attachPauses(ctx context.Context, schedules []domain.Schedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(schedules))
+	for _, schedule := range schedules {
+		ids = append(ids, schedule.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT p.schedule_id, p.paused_at, p.resumed_at
+        FROM schedule_pauses p
+        JOIN schedules s ON s.id = p.schedule_id
+        WHERE p.schedule_id = ANY($1) AND s.organization_id = $2
+        ORDER BY p.schedule_id, p.paused_at`, ids, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch schedule pauses: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[int][]domain.Pause, len(schedules))
+	for rows.Next() {
+		var (
+			scheduleID int
+			pause      domain.Pause
+		)
+		if err := rows.Scan(&scheduleID, &pause.From, &pause.To); err != nil {
+			return fmt.Errorf("failed to scan schedule pause: %w", err)
+		}
+		byID[scheduleID] = append(byID[scheduleID], pause)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to fetch schedule pauses: %w", err)
+	}
+
+	for i := range schedules {
+		schedules[i].Pauses = byID[schedules[i].ID]
+	}
+	return nil
+}
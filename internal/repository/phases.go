@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"time"
+)
+
+// phaseRow — этап расписания в колонке phases. Единицы те же, что и в
+// колонках расписания: длительности в миллисекундах, время суток в минутах.
+type phaseRow struct {
+	OffsetMs    int64   `json:"offset_ms"`
+	DurationMs  int64   `json:"duration_ms"`
+	DoseAmount  float64 `json:"dose_amount,omitempty"`
+	DoseUnit    string  `json:"dose_unit,omitempty"`
+	DoseForm    string  `json:"dose_form,omitempty"`
+	DoseRoute   string  `json:"dose_route,omitempty"`
+	Kind        string  `json:"kind,omitempty"`
+	FrequencyMs int64   `json:"frequency_ms,omitempty"`
+	TimesOfDay  []int   `json:"times_of_day,omitempty"`
+}
+
+func encodePhases(phases []domain.Phase) ([]byte, error) {
+	rows := make([]phaseRow, 0, len(phases))
+	for _, phase := range phases {
+		rows = append(rows, phaseRow{
+			OffsetMs:    phase.Offset.Milliseconds(),
+			DurationMs:  phase.Duration.Milliseconds(),
+			DoseAmount:  phase.Dosage.Amount,
+			DoseUnit:    string(phase.Dosage.Unit),
+			DoseForm:    string(phase.Dosage.Form),
+			DoseRoute:   string(phase.Dosage.Route),
+			Kind:        string(phase.Regimen.Kind),
+			FrequencyMs: phase.Regimen.Frequency.Milliseconds(),
+			TimesOfDay:  clockMinutes(phase.Regimen.TimesOfDay),
+		})
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schedule phases: %w", err)
+	}
+	return data, nil
+}
+
+func decodePhases(data []byte) ([]domain.Phase, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var rows []phaseRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule phases: %w", err)
+	}
+
+	var phases []domain.Phase
+	for _, row := range rows {
+		phase := domain.Phase{
+			Offset:   time.Duration(row.OffsetMs) * time.Millisecond,
+			Duration: time.Duration(row.DurationMs) * time.Millisecond,
+			Dosage: domain.Dosage{
+				Amount: row.DoseAmount,
+				Unit:   domain.DoseUnit(row.DoseUnit),
+				Form:   domain.DoseForm(row.DoseForm),
+				Route:  domain.DoseRoute(row.DoseRoute),
+			},
+			Regimen: domain.Regimen{
+				Kind:      domain.ScheduleKind(row.Kind),
+				Frequency: time.Duration(row.FrequencyMs) * time.Millisecond,
+			},
+		}
+		for _, minutes := range row.TimesOfDay {
+			phase.Regimen.TimesOfDay = append(phase.Regimen.TimesOfDay, time.Duration(minutes)*time.Minute)
+		}
+		phases = append(phases, phase)
+	}
+	return phases, nil
+}
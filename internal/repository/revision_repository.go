@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// revisionSelect выбирает номер и срок действия редакции, а за ними —
+// колонки расписания в порядке scheduleScan. Приостановки в редакциях не
+// хранятся: они берутся из schedule_pauses (см. attachPauses).
+const revisionSelect = `
+        SELECT r.revision, r.effective_from, r.effective_to,
+            s.id, s.user_id, r.medication, r.kind, r.frequency, r.times_of_day, r.duration, r.timezone,
+            COALESCE(r.window_start, us.window_start), COALESCE(r.window_end, us.window_end),
+            r.window_start IS NULL, r.weekdays, r.cycle_on_days, r.cycle_off_days,
+            s.start_time, r.end_time, NULL::timestamptz, r.dose_amount, r.dose_unit, r.dose_form, r.dose_route,
+            r.phases, r.min_interval, r.max_per_day, r.anchors, us.events, r.rounding_mode, r.rounding_step
+        FROM schedule_revisions r
+        JOIN schedules s ON s.id = r.schedule_id
+        LEFT JOIN user_settings us ON us.user_id = s.user_id AND us.organization_id = s.organization_id`
+
+// GetHistory возвращает все редакции расписания, начиная с первой.
+func (r *ScheduleRepository) GetHistory(ctx context.Context, scheduleID int) ([]domain.ScheduleRevision, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.listRevisions(ctx, revisionSelect+`
+        WHERE r.schedule_id = $1 AND s.organization_id = $2
+        ORDER BY r.revision`, scheduleID, organizationID)
+}
+
+// attachRevisions дополняет расписания их прежними редакциями, чтобы
+// приёмы в прошлом считались по правилам, действовавшим тогда.
+func (r *ScheduleRepository) attachRevisions(ctx context.Context, schedules []domain.Schedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(schedules))
+	for _, schedule := range schedules {
+		ids = append(ids, schedule.ID)
+	}
+
+	revisions, err := r.listRevisions(ctx, revisionSelect+`
+        WHERE r.schedule_id = ANY($1) AND s.organization_id = $2 AND r.effective_to IS NOT NULL
+        ORDER BY r.schedule_id, r.revision`, ids, organizationID)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int][]domain.ScheduleRevision, len(schedules))
+	for _, revision := range revisions {
+		byID[revision.Schedule.ID] = append(byID[revision.Schedule.ID], revision)
+	}
+	for i := range schedules {
+		schedules[i].Revisions = byID[schedules[i].ID]
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) listRevisions(ctx context.Context, sql string, args ...interface{}) ([]domain.ScheduleRevision, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schedule revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []domain.ScheduleRevision
+	for rows.Next() {
+		var (
+			revision    domain.ScheduleRevision
+			effectiveTo *time.Time
+			sc          scheduleScan
+		)
+		dest := append([]interface{}{&revision.Number, &revision.EffectiveFrom, &effectiveTo}, sc.dest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan schedule revision: %w", err)
+		}
+		if effectiveTo != nil {
+			revision.EffectiveTo = *effectiveTo
+		}
+		if revision.Schedule, err = sc.result(); err != nil {
+			return nil, fmt.Errorf("failed to scan schedule revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// openRevision сохраняет текущее назначение расписания как новую редакцию,
+// действующую с effectiveFrom. Приостановка в редакцию не копируется,
+// иначе после возобновления она продолжала бы действовать в прошлом.
+func openRevision(ctx context.Context, tx pgx.Tx, scheduleID int, effectiveFrom time.Time) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO schedule_revisions
+            (schedule_id, revision, effective_from, medication, kind, frequency, times_of_day, duration, timezone,
+            window_start, window_end, weekdays, cycle_on_days, cycle_off_days, end_time,
+            dose_amount, dose_unit, dose_form, dose_route, phases, min_interval, max_per_day, anchors,
+            rounding_mode, rounding_step)
+        SELECT id, COALESCE((SELECT MAX(revision) FROM schedule_revisions WHERE schedule_id = $1), 0) + 1, $2,
+            medication, kind, frequency, times_of_day, duration, timezone,
+            window_start, window_end, weekdays, cycle_on_days, cycle_off_days, end_time,
+            dose_amount, dose_unit, dose_form, dose_route, phases, min_interval, max_per_day, anchors,
+            rounding_mode, rounding_step
+        FROM schedules WHERE id = $1`, scheduleID, effectiveFrom)
+	if err != nil {
+		return fmt.Errorf("failed to save schedule revision: %w", err)
+	}
+	return nil
+}
+
+func closeRevision(ctx context.Context, tx pgx.Tx, scheduleID int, effectiveTo time.Time) error {
+	_, err := tx.Exec(ctx, `
+        UPDATE schedule_revisions SET effective_to = $2
+        WHERE schedule_id = $1 AND effective_to IS NULL`, scheduleID, effectiveTo)
+	if err != nil {
+		return fmt.Errorf("failed to close schedule revision: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package repository_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"medication-scheduler/internal/auth"
 	"medication-scheduler/internal/domain"
 	myerrors "medication-scheduler/internal/errors"
 	"medication-scheduler/internal/repository"
@@ -16,6 +20,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// clinic — контекст запроса в организации 1.
+var clinic = auth.WithOrganizationID(context.Background(), 1)
+
 type MockDB struct {
 	mock.Mock
 }
@@ -35,22 +42,78 @@ func (m *MockDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgc
 	return argsMock.Get(0).(pgconn.CommandTag), argsMock.Error(1)
 }
 
+func (m *MockDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	argsMock := m.Called(ctx)
+	return argsMock.Get(0).(pgx.Tx), argsMock.Error(1)
+}
+
+// MockTx — транзакция поверх MockDB; запросы внутри неё ожидаются на самом MockTx.
+type MockTx struct {
+	MockDB
+}
+
+func (m *MockTx) Commit(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockTx) Rollback(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockTx) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	panic("not implemented")
+}
+
+func (m *MockTx) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	panic("not implemented")
+}
+
+func (m *MockTx) LargeObjects() pgx.LargeObjects {
+	panic("not implemented")
+}
+
+func (m *MockTx) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	panic("not implemented")
+}
+
+func (m *MockTx) Conn() *pgx.Conn {
+	return nil
+}
+
+// newMockTx возвращает MockDB, открывающий транзакцию tx.
+func newMockTx() (*MockDB, *MockTx) {
+	mockDB := new(MockDB)
+	tx := new(MockTx)
+	mockDB.On("Begin", mock.Anything).Return(tx, nil)
+	tx.On("Rollback", mock.Anything).Return(nil)
+	return mockDB, tx
+}
+
 func TestCreateSchedule(t *testing.T) {
 	baseSchedule := &domain.Schedule{
 		UserID:     1,
 		Medication: "Aspirin",
-		Frequency:  time.Hour,
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{8 * time.Hour, 13*time.Hour + 30*time.Minute},
 		Duration:   24 * time.Hour,
+		Timezone:   "Europe/Moscow",
+		Window:     domain.DayWindow{Start: 6 * time.Hour, End: 14 * time.Hour},
+		Weekdays:   domain.NewWeekdays(time.Monday, time.Friday),
+		Cycle:      domain.Cycle{OnDays: 21, OffDays: 7},
+		Dosage:     domain.Dosage{Amount: 500, Unit: domain.UnitMg, Form: domain.FormTablet, Route: domain.RouteOral},
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mockDB := new(MockDB)
+		mockDB, tx := newMockTx()
 		repo := repository.New(mockDB)
 
 		expectedSQL := `
         INSERT INTO schedules 
-            (user_id, medication, frequency, duration, start_time, end_time)
-        VALUES ($1, $2, $3, $4, $5, $6)
+            (organization_id, user_id, medication, kind, frequency, times_of_day, duration, timezone, window_start, window_end,
+            weekdays, cycle_on_days, cycle_off_days, start_time, end_time, dose_amount, dose_unit, dose_form, dose_route,
+            phases, min_interval, max_per_day, anchors, rounding_mode, rounding_step)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
+            $23, $24, $25)
         RETURNING id`
 
 		mockRow := new(MockRow)
@@ -58,22 +121,86 @@ func TestCreateSchedule(t *testing.T) {
 			*args.Get(0).(*int) = 123
 		}).Return(nil)
 
-		mockDB.On("QueryRow",
+		tx.On("QueryRow",
 			mock.Anything,
 			expectedSQL,
 			mock.MatchedBy(func(args []interface{}) bool {
-				return len(args) == 6 &&
-					args[0] == baseSchedule.UserID &&
-					args[1] == baseSchedule.Medication &&
-					args[2] == baseSchedule.Frequency.Milliseconds() &&
-					args[3] == baseSchedule.Duration.Milliseconds()
+				return len(args) == 25 &&
+					args[0] == 1 &&
+					args[1] == baseSchedule.UserID &&
+					args[2] == baseSchedule.Medication &&
+					args[3] == baseSchedule.Kind &&
+					args[4] == baseSchedule.Frequency.Milliseconds() &&
+					assert.ObjectsAreEqual([]int{8 * 60, 13*60 + 30}, args[5]) &&
+					args[6] == baseSchedule.Duration.Milliseconds() &&
+					args[7] == baseSchedule.Timezone &&
+					*args[8].(*int) == 6*60 &&
+					*args[9].(*int) == 14*60 &&
+					args[10] == int(domain.NewWeekdays(time.Monday, time.Friday)) &&
+					args[11] == 21 &&
+					args[12] == 7 &&
+					args[15] == 500.0 &&
+					args[16] == domain.UnitMg &&
+					args[17] == domain.FormTablet &&
+					args[18] == domain.RouteOral &&
+					string(args[19].([]byte)) == "[]" &&
+					string(args[22].([]byte)) == "[]" &&
+					args[23] == domain.RoundUp &&
+					args[24] == int64(15*60*1000)
 			}),
 		).Return(mockRow)
 
-		err := repo.Create(context.Background(), baseSchedule)
+		storedRow := new(MockRow)
+		storedRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+			stored := *baseSchedule
+			stored.Weekdays = 0
+			stored.Cycle = domain.Cycle{}
+			stored.StartTime = time.Now().UTC().Add(-time.Hour)
+			stored.EndTime = stored.StartTime.Add(stored.Duration)
+			fillScheduleRow(args, stored)
+		}).Return(nil)
+
+		tx.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.HasSuffix(sql, "WHERE s.id = $1 AND s.organization_id = $2") }),
+			[]interface{}{123, 1},
+		).Return(storedRow)
+
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO outbox") }),
+			mock.MatchedBy(func(args []interface{}) bool {
+				keys := args[0].([]string)
+				return len(keys) > 0 && strings.HasPrefix(keys[0], "schedule-123-")
+			}),
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO schedule_revisions") }),
+			[]interface{}{123, baseSchedule.StartTime},
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		err := repo.Create(clinic, baseSchedule)
 		require.NoError(t, err)
 		assert.Equal(t, 123, baseSchedule.ID)
 		mockDB.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("Insert failure rolls back", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.New(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*int")).Return(errors.New("db error"))
+		tx.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+
+		err := repo.Create(clinic, baseSchedule)
+		require.Error(t, err)
+		tx.AssertCalled(t, "Rollback", mock.Anything)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+		tx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
@@ -83,8 +210,10 @@ func TestGetByIDs(t *testing.T) {
 		ID:         1,
 		UserID:     1,
 		Medication: "Aspirin",
+		Kind:       domain.KindInterval,
 		Frequency:  time.Hour,
 		Duration:   24 * time.Hour,
+		Timezone:   "Asia/Tokyo",
 		StartTime:  now,
 		EndTime:    now.Add(24 * time.Hour),
 	}
@@ -94,22 +223,8 @@ func TestGetByIDs(t *testing.T) {
 		repo := repository.New(mockDB)
 
 		mockRow := new(MockRow)
-		mockRow.On("Scan",
-			mock.AnythingOfType("*int"),
-			mock.AnythingOfType("*int"),
-			mock.AnythingOfType("*string"),
-			mock.AnythingOfType("*int64"),
-			mock.AnythingOfType("*int64"),
-			mock.AnythingOfType("*time.Time"),
-			mock.AnythingOfType("*time.Time"),
-		).Run(func(args mock.Arguments) {
-			*args.Get(0).(*int) = validSchedule.ID
-			*args.Get(1).(*int) = validSchedule.UserID
-			*args.Get(2).(*string) = validSchedule.Medication
-			*args.Get(3).(*int64) = validSchedule.Frequency.Milliseconds()
-			*args.Get(4).(*int64) = validSchedule.Duration.Milliseconds()
-			*args.Get(5).(*time.Time) = validSchedule.StartTime
-			*args.Get(6).(*time.Time) = validSchedule.EndTime
+		mockRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+			fillScheduleRow(args, validSchedule)
 		}).Return(nil)
 
 		mockDB.On("QueryRow",
@@ -118,9 +233,37 @@ func TestGetByIDs(t *testing.T) {
 			mock.Anything,
 		).Return(mockRow)
 
-		schedule, err := repo.GetByIDs(context.Background(), 1, 1)
+		previous := validSchedule
+		previous.Medication = "Paracetamol"
+		revisionRows := revisionRows(domain.ScheduleRevision{
+			Number:        1,
+			EffectiveFrom: now.Add(-time.Hour),
+			EffectiveTo:   now,
+			Schedule:      previous,
+		})
+		mockDB.On("Query",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "r.effective_to IS NOT NULL") }),
+			[]interface{}{[]int{1}, 1},
+		).Return(revisionRows, nil)
+		pause := domain.Pause{From: now.Add(-30 * time.Minute), To: now.Add(-10 * time.Minute)}
+		mockDB.On("Query",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "FROM schedule_pauses") }),
+			[]interface{}{[]int{1}, 1},
+		).Return(pauseRows(1, pause), nil)
+
+		schedule, err := repo.GetByIDs(clinic, 1, 1)
 		require.NoError(t, err)
+		require.Len(t, schedule.Revisions, 1)
+		assert.Equal(t, []domain.Pause{pause}, schedule.Pauses)
+		assert.Equal(t, "Paracetamol", schedule.Revisions[0].Schedule.Medication)
+		assert.Equal(t, now, schedule.RevisedAt())
 		assert.Equal(t, "Aspirin", schedule.Medication)
+		assert.Equal(t, domain.KindInterval, schedule.Kind)
+		assert.Equal(t, time.Hour, schedule.Frequency)
+		assert.Equal(t, "Asia/Tokyo", schedule.Timezone)
+		assert.True(t, schedule.Window.IsZero())
 	})
 
 	t.Run("Not found", func(t *testing.T) {
@@ -128,20 +271,12 @@ func TestGetByIDs(t *testing.T) {
 		repo := repository.New(mockDB)
 
 		mockRow := new(MockRow)
-		mockRow.On("Scan",
-			mock.AnythingOfType("*int"),
-			mock.AnythingOfType("*int"),
-			mock.AnythingOfType("*string"),
-			mock.AnythingOfType("*int64"),
-			mock.AnythingOfType("*int64"),
-			mock.AnythingOfType("*time.Time"),
-			mock.AnythingOfType("*time.Time"),
-		).Return(pgx.ErrNoRows)
+		mockRow.On("Scan", scheduleScanArgs()...).Return(pgx.ErrNoRows)
 
 		mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRow)
 
-		_, err := repo.GetByIDs(context.Background(), 1, 999)
+		_, err := repo.GetByIDs(clinic, 1, 999)
 		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
 	})
 }
@@ -151,51 +286,553 @@ func TestGetByUserID_Success(t *testing.T) {
 	repo := repository.New(mockDB)
 
 	expectedSQL := `
-        SELECT id, user_id, medication, frequency, duration, start_time, end_time
-        FROM schedules
-        WHERE user_id = $1 AND (end_time > NOW() OR duration = 0)`
+        SELECT s.id, s.user_id, s.medication, s.kind, s.frequency, s.times_of_day, s.duration, s.timezone,
+            COALESCE(s.window_start, us.window_start), COALESCE(s.window_end, us.window_end),
+            s.window_start IS NULL, s.weekdays, s.cycle_on_days, s.cycle_off_days,
+            s.start_time, s.end_time, s.paused_at, s.dose_amount, s.dose_unit, s.dose_form, s.dose_route,
+            s.phases, s.min_interval, s.max_per_day, s.anchors, us.events, s.rounding_mode, s.rounding_step
+        FROM schedules s
+        LEFT JOIN user_settings us ON us.user_id = s.user_id AND us.organization_id = s.organization_id
+        WHERE s.organization_id = $1 AND s.user_id = $2 AND (s.end_time > NOW() OR s.duration = 0)`
 
 	mockRows := new(MockRows)
 	mockRows.On("Next").Once().Return(true)
 	mockRows.On("Next").Once().Return(false)
 	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
 
-	mockRows.On("Scan",
-		mock.AnythingOfType("*int"),
-		mock.AnythingOfType("*int"),
-		mock.AnythingOfType("*string"),
-		mock.AnythingOfType("*time.Duration"),
-		mock.AnythingOfType("*time.Duration"),
-		mock.AnythingOfType("*time.Time"),
-		mock.AnythingOfType("*time.Time")).
+	mockRows.On("Scan", scheduleScanArgs()...).
 		Run(func(args mock.Arguments) {
-			*args.Get(0).(*int) = 1
-			*args.Get(1).(*int) = 1
-			*args.Get(2).(*string) = "Aspirin"
-			*args.Get(3).(*time.Duration) = time.Hour
-			*args.Get(4).(*time.Duration) = 24 * time.Hour
-			*args.Get(5).(*time.Time) = time.Now().Add(-1 * time.Hour)
-			*args.Get(6).(*time.Time) = time.Now().Add(23 * time.Hour)
+			fillScheduleRow(args, domain.Schedule{
+				ID:         1,
+				UserID:     1,
+				Medication: "Aspirin",
+				Kind:       domain.KindFixedTimes,
+				TimesOfDay: []time.Duration{21 * time.Hour, 7 * time.Hour},
+				Duration:   24 * time.Hour,
+				Timezone:   "UTC",
+				Window:     domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+				Weekdays:   domain.NewWeekdays(time.Saturday, time.Sunday),
+				Cycle:      domain.Cycle{OnDays: 1, OffDays: 1},
+				StartTime:  time.Now().Add(-1 * time.Hour),
+				EndTime:    time.Now().Add(23 * time.Hour),
+			})
 		}).Return(nil)
 
 	mockDB.On("Query",
 		mock.Anything,
 		expectedSQL,
-		[]interface{}{1}).
+		[]interface{}{1, 1}).
 		Return(mockRows, nil)
 
-	schedules, err := repo.GetByUserID(context.Background(), 1)
+	schedules, err := repo.GetByUserID(clinic, 1)
 
 	require.NoError(t, err)
 	require.Len(t, schedules, 1)
 	assert.Equal(t, 1, schedules[0].ID)
 	assert.Equal(t, "Aspirin", schedules[0].Medication)
-	assert.Equal(t, time.Hour, schedules[0].Frequency)
+	assert.Equal(t, domain.KindFixedTimes, schedules[0].Kind)
+	assert.Equal(t, []time.Duration{21 * time.Hour, 7 * time.Hour}, schedules[0].TimesOfDay)
+	assert.Equal(t, domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour}, schedules[0].Window)
+	assert.Equal(t, domain.NewWeekdays(time.Saturday, time.Sunday), schedules[0].Weekdays)
+	assert.Equal(t, domain.Cycle{OnDays: 1, OffDays: 1}, schedules[0].Cycle)
 
 	mockDB.AssertExpectations(t)
 	mockRows.AssertExpectations(t)
 }
 
+func TestGetByUserID_IterationError(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(errors.New("connection reset"))
+	mockDB.On("Query", mock.Anything, mock.Anything, []interface{}{1, 1}).Return(mockRows, nil)
+
+	_, err := repo.GetByUserID(clinic, 1)
+
+	assert.Error(t, err)
+}
+
+func TestGetByUserID_EventRelative(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	stored := domain.Schedule{
+		ID:         2,
+		UserID:     1,
+		Medication: "Omeprazole",
+		Kind:       domain.KindEventRelative,
+		Anchors: []domain.Anchor{
+			{Event: domain.EventBreakfast, Offset: -30 * time.Minute},
+			{Event: domain.EventBedtime},
+		},
+		Events:    domain.DailyEvents{domain.EventBreakfast: 7*time.Hour + 30*time.Minute},
+		Timezone:  "UTC",
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   domain.PerpetualEndTime,
+	}
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Once().Return(true)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Scan", scheduleScanArgs()...).
+		Run(func(args mock.Arguments) { fillScheduleRow(args, stored) }).
+		Return(nil)
+	mockDB.On("Query", mock.Anything, mock.Anything, []interface{}{1, 1}).Return(mockRows, nil)
+
+	schedules, err := repo.GetByUserID(clinic, 1)
+
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, stored.Anchors, schedules[0].Anchors)
+	assert.Equal(t, stored.Events, schedules[0].Events)
+}
+
+func TestGetByUserIDs(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Times(2).Return(true)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Scan", scheduleScanArgs()...).Once().
+		Run(func(args mock.Arguments) {
+			fillScheduleRow(args, domain.Schedule{ID: 1, UserID: 1, Medication: "Aspirin"})
+		}).
+		Return(nil)
+	mockRows.On("Scan", scheduleScanArgs()...).Once().
+		Run(func(args mock.Arguments) {
+			fillScheduleRow(args, domain.Schedule{ID: 2, UserID: 3, Medication: "Insulin"})
+		}).
+		Return(nil)
+
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "s.organization_id = $1 AND s.user_id = ANY($2)")
+		}),
+		[]interface{}{1, []int{1, 3}}).
+		Return(mockRows, nil)
+
+	schedules, err := repo.GetByUserIDs(clinic, []int{1, 3})
+
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, 1, schedules[0].UserID)
+	assert.Equal(t, 3, schedules[1].UserID)
+	mockDB.AssertNumberOfCalls(t, "Query", 1)
+}
+
+func TestGetByUserIDInRange(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
+
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "s.start_time < $4 AND (s.end_time > $3 OR s.duration = 0)")
+		}),
+		[]interface{}{1, 1, from, to}).
+		Return(mockRows, nil)
+
+	schedules, err := repo.GetByUserIDInRange(clinic, 1, from, to)
+
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetActive(t *testing.T) {
+	until := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	mockRows := new(MockRows)
+	mockRows.On("Next").Once().Return(true)
+	mockRows.On("Next").Once().Return(false)
+	mockRows.On("Scan", scheduleScanArgs()...).
+		Run(func(args mock.Arguments) {
+			fillScheduleRow(args, domain.Schedule{ID: 1, UserID: 2, Medication: "Aspirin", Kind: domain.KindInterval, Frequency: time.Hour, Timezone: "UTC"})
+		}).
+		Return(nil)
+	mockRows.On("Close").Return(nil)
+	mockRows.On("Err").Return(nil)
+
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "WHERE s.organization_id = $1 AND s.start_time < $2 AND (s.end_time > NOW() OR s.duration = 0)")
+		}),
+		[]interface{}{1, until}).
+		Return(mockRows, nil)
+
+	schedules, err := repo.GetActive(clinic, until)
+
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, 2, schedules[0].UserID)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetByID(t *testing.T) {
+	pausedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	dosage := domain.Dosage{Amount: 2.5, Unit: domain.UnitMl, Form: domain.FormSuspension, Route: domain.RouteOral}
+	phases := []domain.Phase{
+		{Duration: 72 * time.Hour, Dosage: domain.Dosage{Amount: 40, Unit: domain.UnitMg}, Regimen: domain.Regimen{Kind: domain.KindInterval, Frequency: 12 * time.Hour}},
+		{Offset: 72 * time.Hour, Dosage: domain.Dosage{Amount: 20, Unit: domain.UnitMg}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.New(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+			fillScheduleRow(args, domain.Schedule{
+				ID:             5,
+				UserID:         2,
+				InheritsWindow: true,
+				PausedAt:       pausedAt,
+				Dosage:         dosage,
+				Phases:         phases,
+				MinInterval:    4 * time.Hour,
+				MaxPerDay:      4,
+			})
+		}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{5, 1}).Return(mockRow)
+
+		schedule, err := repo.GetByID(clinic, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, schedule.UserID)
+		assert.True(t, schedule.InheritsWindow)
+		assert.Equal(t, pausedAt, schedule.PausedAt)
+		assert.Equal(t, dosage, schedule.Dosage)
+		assert.Equal(t, phases, schedule.Phases)
+		assert.Equal(t, 4*time.Hour, schedule.MinInterval)
+		assert.Equal(t, 4, schedule.MaxPerDay)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.New(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", scheduleScanArgs()...).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+
+		_, err := repo.GetByID(clinic, 5)
+		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
+	})
+
+	t.Run("Other organization", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.New(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", scheduleScanArgs()...).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.HasSuffix(sql, "WHERE s.id = $1 AND s.organization_id = $2") }),
+			[]interface{}{5, 2},
+		).Return(mockRow)
+
+		_, err := repo.GetByID(auth.WithOrganizationID(context.Background(), 2), 5)
+		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
+	})
+
+	t.Run("Without organization", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.New(mockDB)
+
+		_, err := repo.GetByID(context.Background(), 5)
+		assert.ErrorIs(t, err, myerrors.ErrTenantRequired)
+		_, err = repo.GetActive(context.Background(), time.Now())
+		assert.ErrorIs(t, err, myerrors.ErrTenantRequired)
+		mockDB.AssertNotCalled(t, "QueryRow", mock.Anything, mock.Anything, mock.Anything)
+		mockDB.AssertNotCalled(t, "Query", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	schedule := &domain.Schedule{
+		ID:             5,
+		UserID:         2,
+		Medication:     "Ibuprofen",
+		Kind:           domain.KindInterval,
+		Frequency:      4 * time.Hour,
+		Timezone:       "UTC",
+		Window:         domain.DayWindow{Start: 9 * time.Hour, End: 21 * time.Hour},
+		InheritsWindow: true,
+		EndTime:        domain.PerpetualEndTime,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.New(mockDB)
+
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "UPDATE schedules") }),
+			mock.MatchedBy(func(args []interface{}) bool {
+				return len(args) == 25 &&
+					args[0] == 5 &&
+					args[1] == "Ibuprofen" &&
+					args[7] == (*int)(nil) &&
+					args[8] == (*int)(nil) &&
+					args[13] == (*time.Time)(nil) &&
+					args[24] == 1
+			}),
+		).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "UPDATE schedule_revisions SET effective_to") }),
+			mock.Anything,
+		).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO schedule_revisions") }),
+			mock.Anything,
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "DELETE FROM outbox") }),
+			[]interface{}{5, 1},
+		).Return(pgconn.NewCommandTag("DELETE 3"), nil)
+
+		storedRow := new(MockRow)
+		storedRow.On("Scan", scheduleScanArgs()...).Run(func(args mock.Arguments) {
+			fillScheduleRow(args, *schedule)
+		}).Return(nil)
+		tx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{5, 1}).Return(storedRow)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO outbox") }),
+			mock.Anything,
+		).Return(pgconn.NewCommandTag("INSERT 0 3"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		require.NoError(t, repo.Update(clinic, schedule))
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.New(mockDB)
+
+		tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil).Once()
+
+		err := repo.Update(clinic, schedule)
+		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	mockDB.On("Exec", mock.Anything, "DELETE FROM schedules WHERE id = $1 AND organization_id = $2", []interface{}{5, 1}).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil).Once()
+	mockDB.On("Exec", mock.Anything, "DELETE FROM schedules WHERE id = $1 AND organization_id = $2", []interface{}{6, 1}).
+		Return(pgconn.NewCommandTag("DELETE 0"), nil).Once()
+
+	require.NoError(t, repo.Delete(clinic, 5))
+	assert.ErrorIs(t, repo.Delete(clinic, 6), myerrors.ErrScheduleNotFound)
+}
+
+func TestGetHistory(t *testing.T) {
+	mockDB := new(MockDB)
+	repo := repository.New(mockDB)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	revisedAt := start.AddDate(0, 0, 5)
+	rows := revisionRows(
+		domain.ScheduleRevision{Number: 1, EffectiveFrom: start, EffectiveTo: revisedAt,
+			Schedule: domain.Schedule{ID: 3, Medication: "Aspirin", Frequency: time.Hour}},
+		domain.ScheduleRevision{Number: 2, EffectiveFrom: revisedAt,
+			Schedule: domain.Schedule{ID: 3, Medication: "Aspirin", Frequency: 2 * time.Hour}},
+	)
+	mockDB.On("Query",
+		mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.HasSuffix(sql, "ORDER BY r.revision") }),
+		[]interface{}{3, 1},
+	).Return(rows, nil)
+
+	history, err := repo.GetHistory(clinic, 3)
+
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, revisedAt, history[0].EffectiveTo)
+	assert.True(t, history[1].EffectiveTo.IsZero())
+	assert.Equal(t, 2*time.Hour, history[1].Schedule.Frequency)
+}
+
+// revisionRows возвращает MockRows с редакциями в порядке колонок revisionSelect.
+func revisionRows(revisions ...domain.ScheduleRevision) *MockRows {
+	rows := new(MockRows)
+	args := append([]interface{}{
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("**time.Time"),
+	}, scheduleScanArgs()...)
+
+	for _, revision := range revisions {
+		rows.On("Next").Once().Return(true)
+		rows.On("Scan", args...).Once().Run(func(a mock.Arguments) {
+			*a.Get(0).(*int) = revision.Number
+			*a.Get(1).(*time.Time) = revision.EffectiveFrom
+			if !revision.EffectiveTo.IsZero() {
+				effectiveTo := revision.EffectiveTo
+				*a.Get(2).(**time.Time) = &effectiveTo
+			}
+			fillScheduleRow(a[3:], revision.Schedule)
+		}).Return(nil)
+	}
+	rows.On("Next").Once().Return(false)
+	rows.On("Close").Return(nil)
+	rows.On("Err").Return(nil)
+	return rows
+}
+
+// scheduleScanArgs — типы аргументов Scan в порядке колонок SELECT расписаний.
+func scheduleScanArgs() []interface{} {
+	return []interface{}{
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("*domain.ScheduleKind"),
+		mock.AnythingOfType("*int64"),
+		mock.AnythingOfType("*[]int"),
+		mock.AnythingOfType("*int64"),
+		mock.AnythingOfType("*string"),
+		mock.AnythingOfType("**int"),
+		mock.AnythingOfType("**int"),
+		mock.AnythingOfType("*bool"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("**time.Time"),
+		mock.AnythingOfType("*float64"),
+		mock.AnythingOfType("*domain.DoseUnit"),
+		mock.AnythingOfType("*domain.DoseForm"),
+		mock.AnythingOfType("*domain.DoseRoute"),
+		mock.AnythingOfType("*[]uint8"),
+		mock.AnythingOfType("*int64"),
+		mock.AnythingOfType("*int"),
+		mock.AnythingOfType("*[]uint8"),
+		mock.AnythingOfType("*[]uint8"),
+		mock.AnythingOfType("*domain.RoundingMode"),
+		mock.AnythingOfType("*int64"),
+	}
+}
+
+// fillScheduleRow заполняет аргументы Scan так, как их вернула бы база.
+func fillScheduleRow(args mock.Arguments, s domain.Schedule) {
+	*args.Get(0).(*int) = s.ID
+	*args.Get(1).(*int) = s.UserID
+	*args.Get(2).(*string) = s.Medication
+	*args.Get(3).(*domain.ScheduleKind) = s.Kind
+	*args.Get(4).(*int64) = s.Frequency.Milliseconds()
+	for _, clock := range s.TimesOfDay {
+		*args.Get(5).(*[]int) = append(*args.Get(5).(*[]int), int(clock/time.Minute))
+	}
+	*args.Get(6).(*int64) = s.Duration.Milliseconds()
+	*args.Get(7).(*string) = s.Timezone
+	if !s.Window.IsZero() {
+		start, end := int(s.Window.Start/time.Minute), int(s.Window.End/time.Minute)
+		*args.Get(8).(**int) = &start
+		*args.Get(9).(**int) = &end
+	}
+	*args.Get(10).(*bool) = s.InheritsWindow
+	*args.Get(11).(*int) = int(s.Weekdays)
+	*args.Get(12).(*int) = s.Cycle.OnDays
+	*args.Get(13).(*int) = s.Cycle.OffDays
+	*args.Get(14).(*time.Time) = s.StartTime
+	*args.Get(15).(*time.Time) = s.EndTime
+	if !s.PausedAt.IsZero() {
+		pausedAt := s.PausedAt
+		*args.Get(16).(**time.Time) = &pausedAt
+	}
+	*args.Get(17).(*float64) = s.Dosage.Amount
+	*args.Get(18).(*domain.DoseUnit) = s.Dosage.Unit
+	*args.Get(19).(*domain.DoseForm) = s.Dosage.Form
+	*args.Get(20).(*domain.DoseRoute) = s.Dosage.Route
+	*args.Get(21).(*[]byte) = phasesJSON(s.Phases)
+	*args.Get(22).(*int64) = s.MinInterval.Milliseconds()
+	*args.Get(23).(*int) = s.MaxPerDay
+	*args.Get(24).(*[]byte) = anchorsJSON(s.Anchors)
+	if s.Events != nil {
+		*args.Get(25).(*[]byte) = eventsJSON(s.Events)
+	}
+	rounding := s.RoundingPolicy()
+	*args.Get(26).(*domain.RoundingMode) = rounding.Mode
+	*args.Get(27).(*int64) = rounding.Step.Milliseconds()
+}
+
+// anchorsJSON кодирует привязки так, как они лежат в колонке anchors.
+func anchorsJSON(anchors []domain.Anchor) []byte {
+	rows := make([]map[string]interface{}, 0, len(anchors))
+	for _, anchor := range anchors {
+		rows = append(rows, map[string]interface{}{
+			"event":      anchor.Event,
+			"offset_min": int(anchor.Offset / time.Minute),
+		})
+	}
+	data, _ := json.Marshal(rows)
+	return data
+}
+
+// eventsJSON кодирует время событий дня так, как оно лежит в user_settings.events.
+func eventsJSON(events domain.DailyEvents) []byte {
+	minutes := make(map[string]int, len(events))
+	for event, clock := range events {
+		minutes[string(event)] = int(clock / time.Minute)
+	}
+	data, _ := json.Marshal(minutes)
+	return data
+}
+
+// phasesJSON кодирует этапы так, как они лежат в колонке phases.
+func phasesJSON(phases []domain.Phase) []byte {
+	rows := make([]map[string]interface{}, 0, len(phases))
+	for _, phase := range phases {
+		row := map[string]interface{}{
+			"offset_ms":   phase.Offset.Milliseconds(),
+			"duration_ms": phase.Duration.Milliseconds(),
+		}
+		if !phase.Dosage.IsZero() {
+			row["dose_amount"] = phase.Dosage.Amount
+			row["dose_unit"] = phase.Dosage.Unit
+		}
+		if phase.Regimen.Kind != "" {
+			row["kind"] = phase.Regimen.Kind
+			row["frequency_ms"] = phase.Regimen.Frequency.Milliseconds()
+		}
+		rows = append(rows, row)
+	}
+	data, _ := json.Marshal(rows)
+	return data
+}
+
 type MockRow struct {
 	mock.Mock
 }
@@ -16,8 +16,24 @@ type DB interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
+// scheduleSelect выбирает колонки в порядке, который ожидает scanSchedule.
+// Окно приёма, не заданное в расписании, и время событий дня берутся из
+// настроек пользователя.
+const scheduleSelect = `
+        SELECT s.id, s.user_id, s.medication, s.kind, s.frequency, s.times_of_day, s.duration, s.timezone,
+            COALESCE(s.window_start, us.window_start), COALESCE(s.window_end, us.window_end),
+            s.window_start IS NULL, s.weekdays, s.cycle_on_days, s.cycle_off_days,
+            s.start_time, s.end_time, s.paused_at, s.dose_amount, s.dose_unit, s.dose_form, s.dose_route,
+            s.phases, s.min_interval, s.max_per_day, s.anchors, us.events, s.rounding_mode, s.rounding_step
+        FROM schedules s
+        LEFT JOIN user_settings us ON us.user_id = s.user_id AND us.organization_id = s.organization_id`
+
+// ScheduleRepository хранит расписания. Все запросы ограничены
+// организацией из контекста (auth.WithOrganizationID): расписание другой
+// клиники не найдётся даже по известному ID.
 type ScheduleRepository struct {
 	db DB
 }
@@ -26,48 +42,220 @@ func New(db DB) *ScheduleRepository {
 	return &ScheduleRepository{db: db}
 }
 
+// Create сохраняет расписание и в той же транзакции ставит в очередь
+// напоминания о приёмах на ближайшие domain.ReminderHorizon.
 func (r *ScheduleRepository) Create(ctx context.Context, schedule *domain.Schedule) error {
-	err := r.db.QueryRow(ctx, `
+	phases, err := encodePhases(schedule.Phases)
+	if err != nil {
+		return err
+	}
+	anchors, err := encodeAnchors(schedule.Anchors)
+	if err != nil {
+		return err
+	}
+	rounding := schedule.RoundingPolicy()
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
         INSERT INTO schedules 
-            (user_id, medication, frequency, duration, start_time, end_time)
-        VALUES ($1, $2, $3, $4, $5, $6)
+            (organization_id, user_id, medication, kind, frequency, times_of_day, duration, timezone, window_start, window_end,
+            weekdays, cycle_on_days, cycle_off_days, start_time, end_time, dose_amount, dose_unit, dose_form, dose_route,
+            phases, min_interval, max_per_day, anchors, rounding_mode, rounding_step)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
+            $23, $24, $25)
         RETURNING id`,
-		schedule.UserID,
-		schedule.Medication,
-		schedule.Frequency.Milliseconds(),
-		schedule.Duration.Milliseconds(),
-		schedule.StartTime,
-		schedule.EndTime,
-	).Scan(&schedule.ID)
+			organizationID,
+			schedule.UserID,
+			schedule.Medication,
+			schedule.Kind,
+			schedule.Frequency.Milliseconds(),
+			clockMinutes(schedule.TimesOfDay),
+			schedule.Duration.Milliseconds(),
+			schedule.Timezone,
+			windowMinutes(schedule.Window, schedule.Window.Start),
+			windowMinutes(schedule.Window, schedule.Window.End),
+			int(schedule.Weekdays),
+			schedule.Cycle.OnDays,
+			schedule.Cycle.OffDays,
+			schedule.StartTime,
+			schedule.EndTime,
+			schedule.Dosage.Amount,
+			schedule.Dosage.Unit,
+			schedule.Dosage.Form,
+			schedule.Dosage.Route,
+			phases,
+			schedule.MinInterval.Milliseconds(),
+			schedule.MaxPerDay,
+			anchors,
+			rounding.Mode,
+			rounding.Step.Milliseconds(),
+		).Scan(&schedule.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := openRevision(ctx, tx, schedule.ID, schedule.StartTime); err != nil {
+			return err
+		}
+		return syncReminders(ctx, tx, organizationID, schedule.ID)
+	})
+}
+
+// Update сохраняет изменённое расписание как новую редакцию, действующую с
+// текущего момента. Ещё не доставленные напоминания по нему пересоздаются в
+// той же транзакции.
+func (r *ScheduleRepository) Update(ctx context.Context, schedule *domain.Schedule) error {
+	now := time.Now().UTC()
+	windowStart := windowMinutes(schedule.Window, schedule.Window.Start)
+	windowEnd := windowMinutes(schedule.Window, schedule.Window.End)
+	if schedule.InheritsWindow {
+		windowStart, windowEnd = nil, nil
+	}
+	phases, err := encodePhases(schedule.Phases)
+	if err != nil {
+		return err
+	}
+	anchors, err := encodeAnchors(schedule.Anchors)
+	if err != nil {
+		return err
+	}
+	rounding := schedule.RoundingPolicy()
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+        UPDATE schedules
+        SET medication = $2, kind = $3, frequency = $4, times_of_day = $5, duration = $6, timezone = $7,
+            window_start = $8, window_end = $9, weekdays = $10, cycle_on_days = $11, cycle_off_days = $12,
+            end_time = $13, paused_at = $14, dose_amount = $15, dose_unit = $16, dose_form = $17, dose_route = $18,
+            phases = $19, min_interval = $20, max_per_day = $21, anchors = $22, rounding_mode = $23,
+            rounding_step = $24
+        WHERE id = $1 AND organization_id = $25`,
+			schedule.ID,
+			schedule.Medication,
+			schedule.Kind,
+			schedule.Frequency.Milliseconds(),
+			clockMinutes(schedule.TimesOfDay),
+			schedule.Duration.Milliseconds(),
+			schedule.Timezone,
+			windowStart,
+			windowEnd,
+			int(schedule.Weekdays),
+			schedule.Cycle.OnDays,
+			schedule.Cycle.OffDays,
+			schedule.EndTime,
+			nullableTime(schedule.PausedAt),
+			schedule.Dosage.Amount,
+			schedule.Dosage.Unit,
+			schedule.Dosage.Form,
+			schedule.Dosage.Route,
+			phases,
+			schedule.MinInterval.Milliseconds(),
+			schedule.MaxPerDay,
+			anchors,
+			rounding.Mode,
+			rounding.Step.Milliseconds(),
+			organizationID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update schedule: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return myerrors.ErrScheduleNotFound
+		}
 
-	return err
+		if err := closeRevision(ctx, tx, schedule.ID, now); err != nil {
+			return err
+		}
+		if err := openRevision(ctx, tx, schedule.ID, now); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+        DELETE FROM outbox WHERE schedule_id = $1 AND organization_id = $2 AND status = 'pending'`,
+			schedule.ID, organizationID); err != nil {
+			return fmt.Errorf("failed to drop pending reminders: %w", err)
+		}
+		return syncReminders(ctx, tx, organizationID, schedule.ID)
+	})
+}
+
+// Delete удаляет расписание вместе с отметками о приёмах и напоминаниями.
+func (r *ScheduleRepository) Delete(ctx context.Context, scheduleID int) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM schedules WHERE id = $1 AND organization_id = $2`,
+		scheduleID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrScheduleNotFound
+	}
+	return nil
+}
+
+// syncReminders ставит в очередь напоминания по сохранённому расписанию на
+// ближайшие domain.ReminderHorizon. Окно могло быть унаследовано из
+// настроек пользователя, поэтому расписание перечитывается.
+func syncReminders(ctx context.Context, tx pgx.Tx, organizationID, scheduleID int) error {
+	stored, err := scanSchedule(tx.QueryRow(ctx, scheduleSelect+`
+        WHERE s.id = $1 AND s.organization_id = $2`, scheduleID, organizationID))
+	if err != nil {
+		return fmt.Errorf("failed to fetch schedule: %w", err)
+	}
+
+	from := time.Now().UTC()
+	return enqueueReminders(ctx, tx, stored.RemindersBetween(from, from.Add(domain.ReminderHorizon)))
 }
 
 func (r *ScheduleRepository) GetByIDs(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error) {
-	var (
-		freqMs   int64
-		durMs    int64
-		schedule domain.Schedule
-	)
-
-	err := r.db.QueryRow(ctx, `
-        SELECT id, user_id, medication, frequency, duration, start_time, end_time
-        FROM schedules
-        WHERE user_id = $1 AND id = $2`,
-		userID, scheduleID,
-	).Scan(
-		&schedule.ID,
-		&schedule.UserID,
-		&schedule.Medication,
-		&freqMs,
-		&durMs,
-		&schedule.StartTime,
-		&schedule.EndTime,
-	)
-
-	schedule.Frequency = time.Duration(freqMs) * time.Millisecond
-	schedule.Duration = time.Duration(durMs) * time.Millisecond
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, scheduleSelect+`
+        WHERE s.user_id = $1 AND s.id = $2 AND s.organization_id = $3`,
+		userID, scheduleID, organizationID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, myerrors.ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch schedule: %w", err)
+	}
+
+	schedules := []domain.Schedule{schedule}
+	if err := r.attachRevisions(ctx, schedules); err != nil {
+		return nil, err
+	}
+	if err := r.attachPauses(ctx, schedules); err != nil {
+		return nil, err
+	}
+	return &schedules[0], nil
+}
+
+// GetByID возвращает расписание организации независимо от владельца;
+// проверка доступа — на стороне вызывающего.
+func (r *ScheduleRepository) GetByID(ctx context.Context, scheduleID int) (*domain.Schedule, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, scheduleSelect+`
+        WHERE s.id = $1 AND s.organization_id = $2`, scheduleID, organizationID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, myerrors.ErrScheduleNotFound
@@ -77,11 +265,71 @@ func (r *ScheduleRepository) GetByIDs(ctx context.Context, userID, scheduleID in
 	return &schedule, nil
 }
 
+// GetByUserID возвращает незавершённые расписания пользователя, включая
+// ещё не начавшиеся.
 func (r *ScheduleRepository) GetByUserID(ctx context.Context, userID int) ([]domain.Schedule, error) {
-	rows, err := r.db.Query(ctx, `
-        SELECT id, user_id, medication, frequency, duration, start_time, end_time
-        FROM schedules
-        WHERE user_id = $1 AND (end_time > NOW() OR duration = 0)`, userID)
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, scheduleSelect+`
+        WHERE s.organization_id = $1 AND s.user_id = $2 AND (s.end_time > NOW() OR s.duration = 0)`,
+		organizationID, userID)
+}
+
+// GetByUserIDs — GetByUserID для нескольких пользователей одним запросом.
+func (r *ScheduleRepository) GetByUserIDs(ctx context.Context, userIDs []int) ([]domain.Schedule, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, scheduleSelect+`
+        WHERE s.organization_id = $1 AND s.user_id = ANY($2) AND (s.end_time > NOW() OR s.duration = 0)`,
+		organizationID, userIDs)
+}
+
+// GetActive возвращает расписания всех пользователей организации, которые
+// действуют сейчас или начнутся раньше until.
+func (r *ScheduleRepository) GetActive(ctx context.Context, until time.Time) ([]domain.Schedule, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, scheduleSelect+`
+        WHERE s.organization_id = $1 AND s.start_time < $2 AND (s.end_time > NOW() OR s.duration = 0)`,
+		organizationID, until)
+}
+
+// GetByUserIDInRange возвращает расписания пользователя, действовавшие хотя
+// бы часть интервала [from, to), включая уже завершённые, вместе с их
+// прежними редакциями.
+func (r *ScheduleRepository) GetByUserIDInRange(ctx context.Context, userID int, from, to time.Time) ([]domain.Schedule, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := r.list(ctx, scheduleSelect+`
+        WHERE s.organization_id = $1 AND s.user_id = $2 AND s.start_time < $4 AND (s.end_time > $3 OR s.duration = 0)`,
+		organizationID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachRevisions(ctx, schedules); err != nil {
+		return nil, err
+	}
+	if err := r.attachPauses(ctx, schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ScheduleRepository) list(ctx context.Context, sql string, args ...interface{}) ([]domain.Schedule, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch schedules: %w", err)
 	}
@@ -89,19 +337,125 @@ func (r *ScheduleRepository) GetByUserID(ctx context.Context, userID int) ([]dom
 
 	var schedules []domain.Schedule
 	for rows.Next() {
-		var schedule domain.Schedule
-		if err := rows.Scan(&schedule.ID,
-			&schedule.UserID,
-			&schedule.Medication,
-			&schedule.Frequency,
-			&schedule.Duration,
-			&schedule.StartTime,
-			&schedule.EndTime,
-		); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
 		schedules = append(schedules, schedule)
 	}
 
-	return schedules, nil
+	return schedules, rows.Err()
+}
+
+// scanSchedule читает строку в порядке колонок scheduleSelect.
+func scanSchedule(row pgx.Row) (domain.Schedule, error) {
+	var sc scheduleScan
+	if err := row.Scan(sc.dest()...); err != nil {
+		return domain.Schedule{}, err
+	}
+	return sc.result()
+}
+
+// scheduleScan — приёмник для колонок расписания. Частота и длительность
+// хранятся в миллисекундах, границы окна — в минутах, этапы и привязки — в JSON.
+type scheduleScan struct {
+	freqMs      int64
+	durMs       int64
+	timesOfDay  []int
+	windowStart *int
+	windowEnd   *int
+	weekdays    int
+	pausedAt    *time.Time
+	phases      []byte
+	minInterval int64
+	anchors     []byte
+	events      []byte
+	roundingMs  int64
+	schedule    domain.Schedule
+}
+
+func (sc *scheduleScan) dest() []interface{} {
+	return []interface{}{
+		&sc.schedule.ID,
+		&sc.schedule.UserID,
+		&sc.schedule.Medication,
+		&sc.schedule.Kind,
+		&sc.freqMs,
+		&sc.timesOfDay,
+		&sc.durMs,
+		&sc.schedule.Timezone,
+		&sc.windowStart,
+		&sc.windowEnd,
+		&sc.schedule.InheritsWindow,
+		&sc.weekdays,
+		&sc.schedule.Cycle.OnDays,
+		&sc.schedule.Cycle.OffDays,
+		&sc.schedule.StartTime,
+		&sc.schedule.EndTime,
+		&sc.pausedAt,
+		&sc.schedule.Dosage.Amount,
+		&sc.schedule.Dosage.Unit,
+		&sc.schedule.Dosage.Form,
+		&sc.schedule.Dosage.Route,
+		&sc.phases,
+		&sc.minInterval,
+		&sc.schedule.MaxPerDay,
+		&sc.anchors,
+		&sc.events,
+		&sc.schedule.Rounding.Mode,
+		&sc.roundingMs,
+	}
+}
+
+func (sc *scheduleScan) result() (domain.Schedule, error) {
+	schedule := sc.schedule
+	schedule.Frequency = time.Duration(sc.freqMs) * time.Millisecond
+	schedule.Duration = time.Duration(sc.durMs) * time.Millisecond
+	schedule.MinInterval = time.Duration(sc.minInterval) * time.Millisecond
+	schedule.Rounding.Step = time.Duration(sc.roundingMs) * time.Millisecond
+	schedule.Weekdays = domain.Weekdays(sc.weekdays)
+	if sc.pausedAt != nil {
+		schedule.PausedAt = *sc.pausedAt
+	}
+	for _, minutes := range sc.timesOfDay {
+		schedule.TimesOfDay = append(schedule.TimesOfDay, time.Duration(minutes)*time.Minute)
+	}
+	if sc.windowStart != nil && sc.windowEnd != nil {
+		schedule.Window = domain.DayWindow{
+			Start: time.Duration(*sc.windowStart) * time.Minute,
+			End:   time.Duration(*sc.windowEnd) * time.Minute,
+		}
+	}
+
+	phases, err := decodePhases(sc.phases)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+	schedule.Phases = phases
+
+	if schedule.Anchors, err = decodeAnchors(sc.anchors); err != nil {
+		return domain.Schedule{}, err
+	}
+	if schedule.Events, err = decodeEvents(sc.events); err != nil {
+		return domain.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// windowMinutes возвращает границу окна в минутах или nil, если окно
+// не задано и должно наследоваться из настроек пользователя.
+func windowMinutes(w domain.DayWindow, bound time.Duration) *int {
+	if w.IsZero() {
+		return nil
+	}
+	minutes := int(bound / time.Minute)
+	return &minutes
+}
+
+func clockMinutes(clocks []time.Duration) []int {
+	minutes := make([]int, 0, len(clocks))
+	for _, clock := range clocks {
+		minutes = append(minutes, int(clock/time.Minute))
+	}
+	return minutes
 }
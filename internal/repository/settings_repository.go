@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SettingsRepository struct {
+	db DB
+}
+
+func NewSettingsRepository(db DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+func (r *SettingsRepository) Get(ctx context.Context, userID int) (*domain.UserSettings, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		windowStart int
+		windowEnd   int
+		eventsJSON  []byte
+	)
+
+	err = r.db.QueryRow(ctx, `
+        SELECT window_start, window_end, events
+        FROM user_settings
+        WHERE user_id = $1 AND organization_id = $2`, userID, organizationID,
+	).Scan(&windowStart, &windowEnd, &eventsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, myerrors.ErrSettingsNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch settings: %w", err)
+	}
+
+	events, err := decodeEvents(eventsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserSettings{
+		UserID: userID,
+		Window: domain.DayWindow{
+			Start: time.Duration(windowStart) * time.Minute,
+			End:   time.Duration(windowEnd) * time.Minute,
+		},
+		Events: events,
+	}, nil
+}
+
+// Upsert сохраняет настройки пользователя. Настройки, сохранённые в другой
+// организации, не перезаписываются.
+func (r *SettingsRepository) Upsert(ctx context.Context, settings *domain.UserSettings) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	events, err := encodeEvents(settings.Events)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+        INSERT INTO user_settings (user_id, window_start, window_end, events, organization_id)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id) DO UPDATE
+        SET window_start = EXCLUDED.window_start, window_end = EXCLUDED.window_end, events = EXCLUDED.events
+        WHERE user_settings.organization_id = EXCLUDED.organization_id`,
+		settings.UserID,
+		int(settings.Window.Start/time.Minute),
+		int(settings.Window.End/time.Minute),
+		events,
+		organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return myerrors.ErrSettingsNotFound
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+)
+
+// tenant возвращает организацию, в которой выполняется запрос. Без неё
+// запросы к расписаниям не выполняются: забытый auth.WithOrganizationID
+// должен приводить к ошибке, а не к чтению данных всех клиник.
+func tenant(ctx context.Context) (int, error) {
+	organizationID, ok := auth.OrganizationIDFrom(ctx)
+	if !ok {
+		return 0, myerrors.ErrTenantRequired
+	}
+	return organizationID, nil
+}
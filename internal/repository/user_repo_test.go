@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserWithOrganization(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.NewUserRepository(mockDB)
+
+		orgRow := new(MockRow)
+		orgRow.On("Scan", mock.AnythingOfType("*int")).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+		}).Return(nil)
+		tx.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO organizations") }),
+			mock.MatchedBy(func(args []interface{}) bool {
+				slug, _ := args[0].(string)
+				return len(args) == 2 &&
+					regexp.MustCompile(`^org-[0-9a-f]{16}$`).MatchString(slug) &&
+					args[1] == domain.PersonalOrganizationName
+			}),
+		).Return(orgRow)
+
+		userRow := new(MockRow)
+		userRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 7
+				*args.Get(1).(*time.Time) = createdAt
+			}).Return(nil)
+		tx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{3, "patient@example.com", "hash"}).Return(userRow)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO memberships") }),
+			[]interface{}{7, domain.RoleOwner},
+		).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		user := &domain.User{Email: "patient@example.com", PasswordHash: []byte("hash")}
+		require.NoError(t, repo.CreateWithOrganization(context.Background(), user))
+		assert.Equal(t, 7, user.ID)
+		assert.Equal(t, 3, user.OrganizationID)
+		assert.Equal(t, createdAt, user.CreatedAt)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("Email taken", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.NewUserRepository(mockDB)
+
+		orgRow := new(MockRow)
+		orgRow.On("Scan", mock.Anything).Return(nil)
+		tx.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO organizations") }),
+			mock.Anything,
+		).Return(orgRow)
+		userRow := new(MockRow)
+		userRow.On("Scan", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "23505"})
+		tx.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "INSERT INTO users") }),
+			mock.Anything,
+		).Return(userRow)
+
+		err := repo.CreateWithOrganization(context.Background(), &domain.User{Email: "patient@example.com", PasswordHash: []byte("hash")})
+		assert.ErrorIs(t, err, myerrors.ErrEmailTaken)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+func TestCreateInvitedUser(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.NewUserRepository(mockDB)
+
+		inviteRow := new(MockRow)
+		inviteRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*int")).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 11
+			*args.Get(1).(*int) = 2
+		}).Return(nil)
+		tx.On("QueryRow",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "used_at IS NULL AND expires_at > $2") }),
+			[]interface{}{"invite-hash", now},
+		).Return(inviteRow)
+
+		userRow := new(MockRow)
+		userRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 7
+			}).Return(nil)
+		tx.On("QueryRow", mock.Anything, mock.Anything, []interface{}{2, "nurse@example.com", "hash"}).Return(userRow)
+		tx.On("Exec",
+			mock.Anything,
+			mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "SET used_by = $2") }),
+			[]interface{}{11, 7},
+		).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		user := &domain.User{Email: "nurse@example.com", PasswordHash: []byte("hash")}
+		require.NoError(t, repo.CreateInvited(context.Background(), user, "invite-hash", now))
+		assert.Equal(t, 7, user.ID)
+		assert.Equal(t, 2, user.OrganizationID)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("Invalid invite", func(t *testing.T) {
+		mockDB, tx := newMockTx()
+		repo := repository.NewUserRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+		tx.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+
+		err := repo.CreateInvited(context.Background(), &domain.User{Email: "nurse@example.com"}, "used", now)
+		assert.ErrorIs(t, err, myerrors.ErrInvalidInvite)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewUserRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.AnythingOfType("*int"), mock.AnythingOfType("*int"), mock.AnythingOfType("*string"),
+			mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 7
+				*args.Get(1).(*int) = 3
+				*args.Get(2).(*string) = "hash"
+			}).Return(nil)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, []interface{}{"patient@example.com"}).Return(mockRow)
+
+		user, err := repo.GetByEmail(context.Background(), "patient@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 7, user.ID)
+		assert.Equal(t, 3, user.OrganizationID)
+		assert.Equal(t, "patient@example.com", user.Email)
+		assert.Equal(t, []byte("hash"), user.PasswordHash)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockDB := new(MockDB)
+		repo := repository.NewUserRepository(mockDB)
+
+		mockRow := new(MockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+		mockDB.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+
+		_, err := repo.GetByEmail(context.Background(), "patient@example.com")
+		assert.ErrorIs(t, err, myerrors.ErrUserNotFound)
+	})
+}
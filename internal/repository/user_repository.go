@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation — код ошибки PostgreSQL при нарушении UNIQUE.
+const uniqueViolation = "23505"
+
+type UserRepository struct {
+	db DB
+}
+
+func NewUserRepository(db DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// CreateWithOrganization сохраняет пользователя вместе с его собственной
+// организацией, в которой у него роль domain.RoleOwner. Заполняет ID, OrganizationID и
+// CreatedAt. Занятый адрес возвращает ErrEmailTaken.
+func (r *UserRepository) CreateWithOrganization(ctx context.Context, user *domain.User) error {
+	slug, err := personalSlug()
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+        INSERT INTO organizations (slug, name) VALUES ($1, $2)
+        RETURNING id`, slug, domain.PersonalOrganizationName,
+		).Scan(&user.OrganizationID)
+		if err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+
+		if err := insertUser(ctx, tx, user); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+        INSERT INTO memberships (user_id, role) VALUES ($1, $2)`, user.ID, domain.RoleOwner); err != nil {
+			return fmt.Errorf("failed to save membership: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateInvited сохраняет пользователя в организации приглашения с токеном
+// inviteHash и погашает приглашение. Заполняет ID, OrganizationID и
+// CreatedAt. Неизвестное, просроченное или уже использованное приглашение
+// возвращает ErrInvalidInvite, занятый адрес — ErrEmailTaken; приглашение
+// при этом остаётся действительным.
+func (r *UserRepository) CreateInvited(ctx context.Context, user *domain.User, inviteHash string, now time.Time) error {
+	return inTx(ctx, r.db, func(tx pgx.Tx) error {
+		var inviteID int
+		err := tx.QueryRow(ctx, `
+        UPDATE organization_invites SET used_at = $2
+        WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+        RETURNING id, organization_id`, inviteHash, now,
+		).Scan(&inviteID, &user.OrganizationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return myerrors.ErrInvalidInvite
+		}
+		if err != nil {
+			return fmt.Errorf("failed to use invite: %w", err)
+		}
+
+		if err := insertUser(ctx, tx, user); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+        UPDATE organization_invites SET used_by = $2 WHERE id = $1`, inviteID, user.ID); err != nil {
+			return fmt.Errorf("failed to use invite: %w", err)
+		}
+		return nil
+	})
+}
+
+func insertUser(ctx context.Context, tx pgx.Tx, user *domain.User) error {
+	err := tx.QueryRow(ctx, `
+        INSERT INTO users (organization_id, email, password_hash)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at`,
+		user.OrganizationID, user.Email, string(user.PasswordHash),
+	).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return myerrors.ErrEmailTaken
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// personalSlug возвращает случайный непрозрачный slug для собственной
+// организации пользователя.
+func personalSlug() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate organization slug: %w", err)
+	}
+	return "org-" + hex.EncodeToString(suffix), nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var (
+		user         = domain.User{Email: email}
+		passwordHash string
+	)
+
+	err := r.db.QueryRow(ctx, `
+        SELECT id, organization_id, password_hash, created_at
+        FROM users
+        WHERE email = $1`, email,
+	).Scan(&user.ID, &user.OrganizationID, &passwordHash, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, myerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	user.PasswordHash = []byte(passwordHash)
+	return &user, nil
+}
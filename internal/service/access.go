@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"slices"
+)
+
+type GrantLookup interface {
+	Get(ctx context.Context, patientID, caregiverID int) (*domain.Grant, error)
+	ListByCaregiver(ctx context.Context, caregiverID int) ([]domain.Grant, error)
+}
+
+type RoleLookup interface {
+	Role(ctx context.Context, userID int) (domain.Role, error)
+	ListByRole(ctx context.Context, role domain.Role) ([]domain.Membership, error)
+}
+
+// Access проверяет, может ли пользователь из ctx выполнить действие с
+// данными пациента. Свои данные доступны всегда, чужие — по принятому
+// доступу с достаточными правами или сотруднику клиники, если пациент в ней
+// наблюдается, а роль сотрудника разрешает действие. Нулевой *Access
+// разрешает только свои данные.
+type Access struct {
+	grants  GrantLookup
+	members RoleLookup
+}
+
+func NewAccess(grants GrantLookup, members RoleLookup) *Access {
+	return &Access{grants: grants, members: members}
+}
+
+func (a *Access) Check(ctx context.Context, patientID int, need domain.Permission) error {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return myerrors.ErrUnauthorized
+	}
+	if actorID == patientID {
+		return nil
+	}
+	if a == nil {
+		return myerrors.ErrAccessDenied
+	}
+
+	role, clinic, err := a.clinicPatient(ctx, actorID, patientID)
+	if err != nil {
+		return err
+	}
+	if clinic && role.Can(need) {
+		return nil
+	}
+
+	// Сотруднику, чья роль не разрешает действие, его может разрешить
+	// доступ, выданный пациентом лично.
+	denied := error(myerrors.ErrAccessDenied)
+	if clinic {
+		denied = &myerrors.PermissionError{Role: role, Permission: need}
+	}
+	grant, err := a.grants.Get(ctx, patientID, actorID)
+	if errors.Is(err, myerrors.ErrGrantNotFound) {
+		return denied
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check access: %w", err)
+	}
+	if !grant.Allows(need.GrantAccess()) {
+		return denied
+	}
+	return nil
+}
+
+// CheckAll — Check для нескольких пациентов сразу: доступы пользователя
+// читаются одним запросом.
+func (a *Access) CheckAll(ctx context.Context, patientIDs []int, need domain.Permission) error {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return myerrors.ErrUnauthorized
+	}
+	if !slices.ContainsFunc(patientIDs, func(id int) bool { return id != actorID }) {
+		return nil
+	}
+
+	allowed, err := a.Patients(ctx, need)
+	if err != nil {
+		return err
+	}
+	for _, patientID := range patientIDs {
+		if !slices.Contains(allowed, patientID) {
+			return myerrors.ErrAccessDenied
+		}
+	}
+	return nil
+}
+
+// Patients возвращает пользователя из ctx и всех пациентов, с чьими данными
+// он может выполнить действие need: по приглашениям и, для сотрудника, чья
+// роль разрешает need, пациентов клиники.
+func (a *Access) Patients(ctx context.Context, need domain.Permission) ([]int, error) {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	patients := []int{actorID}
+	if a == nil {
+		return patients, nil
+	}
+
+	grants, err := a.grants.ListByCaregiver(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	for _, grant := range grants {
+		if grant.Allows(need.GrantAccess()) {
+			patients = append(patients, grant.PatientID)
+		}
+	}
+
+	role, err := a.members.Role(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if role.Staff() && role.Can(need) {
+		members, err := a.members.ListByRole(ctx, domain.RolePatient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check access: %w", err)
+		}
+		for _, member := range members {
+			if !slices.Contains(patients, member.UserID) {
+				patients = append(patients, member.UserID)
+			}
+		}
+	}
+	return patients, nil
+}
+
+// clinicPatient возвращает роль пользователя и сообщает, наблюдается ли
+// пациент в клинике, где пользователь работает.
+func (a *Access) clinicPatient(ctx context.Context, actorID, patientID int) (domain.Role, bool, error) {
+	role, err := a.members.Role(ctx, actorID)
+	if err != nil {
+		return domain.RoleNone, false, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !role.Staff() {
+		return role, false, nil
+	}
+
+	patientRole, err := a.members.Role(ctx, patientID)
+	if err != nil {
+		return role, false, fmt.Errorf("failed to check access: %w", err)
+	}
+	return role, patientRole == domain.RolePatient, nil
+}
+
+// inOrganization сообщает, состоит ли user в организации из ctx. Учётные
+// записи других клиник для пользователя не существуют: приглашения и роли
+// для них отклоняются так же, как для неизвестного адреса.
+func inOrganization(ctx context.Context, user *domain.User) bool {
+	organizationID, ok := auth.OrganizationIDFrom(ctx)
+	return ok && user.OrganizationID == organizationID
+}
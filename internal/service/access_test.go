@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccessCheck(t *testing.T) {
+	grants := new(MockGrantRepository)
+	members := new(MockMembershipRepository)
+	access := service.NewAccess(grants, members)
+
+	reader := actor(2)
+	pending := actor(3)
+	stranger := actor(4)
+	nurse := actor(5)
+	members.On("Role", mock.Anything, 1).Return(domain.RolePatient, nil)
+	members.On("Role", mock.Anything, 5).Return(domain.RoleNurse, nil)
+	members.On("Role", mock.Anything, mock.Anything).Return(domain.RoleNone, nil)
+	grants.On("Get", nurse, 6, 5).Return(nil, myerrors.ErrGrantNotFound)
+	grants.On("Get", nurse, 1, 5).Return(nil, myerrors.ErrGrantNotFound)
+	grants.On("Get", reader, 1, 2).Return(&domain.Grant{Access: domain.AccessRead, Status: domain.GrantAccepted}, nil)
+	grants.On("Get", pending, 1, 3).Return(&domain.Grant{Access: domain.AccessManage, Status: domain.GrantPending}, nil)
+	grants.On("Get", stranger, 1, 4).Return(nil, myerrors.ErrGrantNotFound)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		need    domain.Permission
+		wantErr error
+	}{
+		{"Own data", actor(1), domain.PermPrescribe, nil},
+		{"Accepted read", reader, domain.PermView, nil},
+		{"Insufficient level", reader, domain.PermRecordDoses, myerrors.ErrAccessDenied},
+		{"Pending invitation", pending, domain.PermView, myerrors.ErrAccessDenied},
+		{"No grant", stranger, domain.PermView, myerrors.ErrAccessDenied},
+		{"No actor", context.Background(), domain.PermView, myerrors.ErrUnauthorized},
+		{"Clinic staff", nurse, domain.PermRecordDoses, nil},
+		{"Nurse cannot prescribe", nurse, domain.PermPrescribe, myerrors.ErrForbidden},
+		{"Nurse cannot share", nurse, domain.PermShare, myerrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := access.Check(tt.ctx, 1, tt.need)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Staff outside clinic patients", func(t *testing.T) {
+		assert.ErrorIs(t, access.Check(nurse, 6, domain.PermView), myerrors.ErrAccessDenied)
+	})
+
+	t.Run("Without grants", func(t *testing.T) {
+		var none *service.Access
+		assert.NoError(t, none.Check(actor(1), 1, domain.PermPrescribe))
+		assert.ErrorIs(t, none.Check(reader, 1, domain.PermView), myerrors.ErrAccessDenied)
+	})
+}
+
+func TestAccessPatients(t *testing.T) {
+	grants := new(MockGrantRepository)
+	members := new(MockMembershipRepository)
+	access := service.NewAccess(grants, members)
+	nurse := actor(5)
+
+	members.On("Role", nurse, 5).Return(domain.RoleNurse, nil)
+	members.On("ListByRole", nurse, domain.RolePatient).Return([]domain.Membership{{UserID: 1}, {UserID: 7}}, nil)
+	grants.On("ListByCaregiver", nurse, 5).Return([]domain.Grant{
+		{PatientID: 7, Access: domain.AccessRead, Status: domain.GrantAccepted},
+		{PatientID: 9, Access: domain.AccessRead, Status: domain.GrantAccepted},
+	}, nil)
+
+	patients, err := access.Patients(nurse, domain.PermView)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 7, 9, 1}, patients)
+
+	t.Run("Without staff permission", func(t *testing.T) {
+		patients, err := access.Patients(nurse, domain.PermPrescribe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{5}, patients)
+	})
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+)
+
+// MaxAdherenceRange ограничивает интервал отчёта, чтобы не перебирать
+// приёмы за произвольно долгий срок.
+const MaxAdherenceRange = 366 * 24 * time.Hour
+
+type AdherenceScheduleRepository interface {
+	GetByUserIDInRange(ctx context.Context, userID int, from, to time.Time) ([]domain.Schedule, error)
+}
+
+type AdherenceService struct {
+	schedules AdherenceScheduleRepository
+	doses     DoseRepository
+	access    *Access
+}
+
+func NewAdherenceService(schedules AdherenceScheduleRepository, doses DoseRepository, access *Access) *AdherenceService {
+	return &AdherenceService{schedules: schedules, doses: doses, access: access}
+}
+
+// GetAdherence строит отчёт о соблюдении режима за [from, to). Нулевой
+// scheduleID означает все расписания пользователя.
+func (s *AdherenceService) GetAdherence(ctx context.Context, userID, scheduleID int, from, to, now time.Time, group domain.Grouping, loc *time.Location) (*domain.AdherenceReport, error) {
+	if !from.Before(to) || to.Sub(from) > MaxAdherenceRange {
+		return nil, myerrors.ErrInvalidTimeRange
+	}
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.schedules.GetByUserIDInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+
+	if scheduleID != 0 {
+		var selected []domain.Schedule
+		for _, schedule := range schedules {
+			if schedule.ID == scheduleID {
+				selected = append(selected, schedule)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, myerrors.ErrScheduleNotFound
+		}
+		schedules = selected
+	}
+
+	events, err := s.doses.ListByUser(ctx, userID, scheduleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get doses: %w", err)
+	}
+
+	return domain.BuildAdherence(schedules, events, from, to, now, group, loc)
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *MockScheduleRepository) GetByUserIDInRange(ctx context.Context, userID int, from, to time.Time) ([]domain.Schedule, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func TestGetAdherence(t *testing.T) {
+	ctx := actor(1)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	schedules := []domain.Schedule{
+		{ID: 1, UserID: 1, Medication: "Aspirin", Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{9 * time.Hour}},
+		{ID: 2, UserID: 1, Medication: "Ibuprofen", Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{12 * time.Hour}},
+	}
+	events := []domain.DoseEvent{
+		{ScheduleID: 1, PlannedAt: from.Add(9 * time.Hour), Status: domain.DoseTaken, TakenAt: from.Add(9 * time.Hour)},
+	}
+
+	t.Run("Single schedule", func(t *testing.T) {
+		mockSchedules := new(MockScheduleRepository)
+		mockDoses := new(MockDoseRepository)
+		svc := service.NewAdherenceService(mockSchedules, mockDoses, nil)
+
+		mockSchedules.On("GetByUserIDInRange", ctx, 1, from, to).Return(schedules, nil)
+		mockDoses.On("ListByUser", ctx, 1, 1, from, to).Return(events, nil)
+
+		report, err := svc.GetAdherence(ctx, 1, 1, from, to, to, domain.GroupByDay, time.UTC)
+		require.NoError(t, err)
+		assert.Len(t, report.Schedules, 1)
+		assert.Equal(t, 2, report.Summary.Due)
+		assert.Equal(t, 50.0, report.Summary.AdherencePct)
+	})
+
+	t.Run("Unknown schedule", func(t *testing.T) {
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewAdherenceService(mockSchedules, new(MockDoseRepository), nil)
+
+		mockSchedules.On("GetByUserIDInRange", ctx, 1, from, to).Return(schedules, nil)
+
+		_, err := svc.GetAdherence(ctx, 1, 3, from, to, to, domain.GroupByDay, time.UTC)
+		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
+	})
+
+	t.Run("Range too long", func(t *testing.T) {
+		svc := service.NewAdherenceService(new(MockScheduleRepository), new(MockDoseRepository), nil)
+
+		_, err := svc.GetAdherence(ctx, 1, 0, from, from.AddDate(2, 0, 0), to, domain.GroupByDay, time.UTC)
+		assert.ErrorIs(t, err, myerrors.ErrInvalidTimeRange)
+	})
+}
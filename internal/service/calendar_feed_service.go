@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+)
+
+type CalendarFeedRepository interface {
+	Save(ctx context.Context, userID int, tokenHash string) error
+	Delete(ctx context.Context, userID int) error
+	Resolve(ctx context.Context, tokenHash string) (auth.Identity, error)
+}
+
+// CalendarFeedService выдаёт ссылки подписки на календарь. Ссылка
+// действует от имени выдавшего её пользователя, пока он её не отзовёт или
+// не выпустит новую.
+type CalendarFeedService struct {
+	feeds CalendarFeedRepository
+}
+
+func NewCalendarFeedService(feeds CalendarFeedRepository) *CalendarFeedService {
+	return &CalendarFeedService{feeds: feeds}
+}
+
+// IssueFeed выдаёт пользователю из ctx новый токен подписки; прежняя
+// ссылка перестаёт действовать.
+func (s *CalendarFeedService) IssueFeed(ctx context.Context) (string, error) {
+	userID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return "", myerrors.ErrUnauthorized
+	}
+
+	token, err := auth.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	if err := s.feeds.Save(ctx, userID, auth.HashSecret(token)); err != nil {
+		return "", fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return token, nil
+}
+
+// RevokeFeed отзывает ссылку подписки пользователя из ctx.
+func (s *CalendarFeedService) RevokeFeed(ctx context.Context) error {
+	userID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return myerrors.ErrUnauthorized
+	}
+	return s.feeds.Delete(ctx, userID)
+}
+
+// ResolveFeed возвращает пользователя, которому выдан token.
+func (s *CalendarFeedService) ResolveFeed(ctx context.Context, token string) (auth.Identity, error) {
+	if token == "" {
+		return auth.Identity{}, myerrors.ErrCalendarFeedNotFound
+	}
+	return s.feeds.Resolve(ctx, auth.HashSecret(token))
+}
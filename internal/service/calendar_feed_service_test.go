@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/auth"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedRepository) Save(ctx context.Context, userID int, tokenHash string) error {
+	return m.Called(ctx, userID, tokenHash).Error(0)
+}
+
+func (m *MockCalendarFeedRepository) Delete(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockCalendarFeedRepository) Resolve(ctx context.Context, tokenHash string) (auth.Identity, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(auth.Identity), args.Error(1)
+}
+
+func TestCalendarFeed(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Issued token resolves to its owner", func(t *testing.T) {
+		feeds := new(MockCalendarFeedRepository)
+		svc := service.NewCalendarFeedService(feeds)
+
+		var saved string
+		feeds.On("Save", ctx, 1, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			saved = args.String(2)
+		}).Return(nil)
+
+		token, err := svc.IssueFeed(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, token, saved, "only the token hash is stored")
+
+		owner := auth.Identity{UserID: 1, OrganizationID: 1}
+		feeds.On("Resolve", context.Background(), saved).Return(owner, nil)
+
+		identity, err := svc.ResolveFeed(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, owner, identity)
+	})
+
+	t.Run("Empty token", func(t *testing.T) {
+		feeds := new(MockCalendarFeedRepository)
+		svc := service.NewCalendarFeedService(feeds)
+
+		_, err := svc.ResolveFeed(context.Background(), "")
+		assert.ErrorIs(t, err, myerrors.ErrCalendarFeedNotFound)
+		feeds.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		feeds := new(MockCalendarFeedRepository)
+		svc := service.NewCalendarFeedService(feeds)
+
+		feeds.On("Delete", ctx, 1).Return(nil)
+
+		require.NoError(t, svc.RevokeFeed(ctx))
+		feeds.AssertExpectations(t)
+	})
+
+	t.Run("Without actor", func(t *testing.T) {
+		svc := service.NewCalendarFeedService(new(MockCalendarFeedRepository))
+
+		_, err := svc.IssueFeed(context.Background())
+		assert.ErrorIs(t, err, myerrors.ErrUnauthorized)
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+)
+
+type DoseRepository interface {
+	Record(ctx context.Context, event *domain.DoseEvent) error
+	ListByUser(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error)
+}
+
+type DoseService struct {
+	doses     DoseRepository
+	schedules ScheduleRepository
+	events    EventPublisher
+	access    *Access
+}
+
+func NewDoseService(doses DoseRepository, schedules ScheduleRepository, events EventPublisher, access *Access) *DoseService {
+	return &DoseService{doses: doses, schedules: schedules, events: events, access: access}
+}
+
+// RecordDose отмечает запланированный приём. Время PlannedAt должно
+// совпадать с одним из приёмов расписания пользователя. У расписания по
+// требованию плановых приёмов нет: отмечается только сам приём, и PlannedAt
+// приравнивается к TakenAt.
+func (s *DoseService) RecordDose(ctx context.Context, event *domain.DoseEvent) error {
+	if err := s.access.Check(ctx, event.UserID, domain.PermRecordDoses); err != nil {
+		return err
+	}
+	if event.Status == domain.DoseTaken && event.TakenAt.IsZero() {
+		event.TakenAt = time.Now().UTC()
+	}
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("invalid dose: %w", err)
+	}
+
+	schedule, err := s.schedules.GetByIDs(ctx, event.UserID, event.ScheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if schedule.Kind == domain.KindAsNeeded {
+		if event.Status != domain.DoseTaken {
+			return domain.ErrAsNeededStatus
+		}
+		event.PlannedAt = event.TakenAt
+	} else if !schedule.IsPlannedTaking(event.PlannedAt) {
+		return myerrors.ErrTakingNotPlanned
+	}
+
+	if err := s.doses.Record(ctx, event); err != nil {
+		return err
+	}
+	if s.events != nil {
+		s.events.Publish(domain.StreamEvent{
+			Type:       domain.StreamDoseRecorded,
+			UserID:     event.UserID,
+			ScheduleID: event.ScheduleID,
+			Medication: schedule.Medication,
+			At:         event.PlannedAt,
+			Dosage:     schedule.DosageAt(event.PlannedAt),
+			Status:     event.Status,
+		})
+	}
+	return nil
+}
+
+// CheckIntake отвечает, можно ли принять дозу по требованию в момент now,
+// и если нельзя — когда станет можно.
+func (s *DoseService) CheckIntake(ctx context.Context, userID, scheduleID int, now time.Time) (domain.IntakeDecision, error) {
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return domain.IntakeDecision{}, err
+	}
+	schedule, err := s.schedules.GetByIDs(ctx, userID, scheduleID)
+	if err != nil {
+		return domain.IntakeDecision{}, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if schedule.Kind != domain.KindAsNeeded {
+		return domain.IntakeDecision{}, domain.ErrNotAsNeeded
+	}
+
+	lookback := max(domain.IntakeLimitPeriod, schedule.MinInterval)
+	events, err := s.doses.ListByUser(ctx, userID, scheduleID, now.Add(-lookback), now.Add(time.Nanosecond))
+	if err != nil {
+		return domain.IntakeDecision{}, err
+	}
+
+	var intakes []time.Time
+	for _, event := range events {
+		if event.Status == domain.DoseTaken {
+			intakes = append(intakes, event.TakenAt)
+		}
+	}
+	return schedule.CheckIntake(intakes, now), nil
+}
+
+func (s *DoseService) GetDoses(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error) {
+	if !from.Before(to) {
+		return nil, myerrors.ErrInvalidTimeRange
+	}
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
+	return s.doses.ListByUser(ctx, userID, scheduleID, from, to)
+}
@@ -0,0 +1,204 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDoseRepository struct {
+	mock.Mock
+}
+
+func (m *MockDoseRepository) Record(ctx context.Context, event *domain.DoseEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockDoseRepository) ListByUser(ctx context.Context, userID, scheduleID int, from, to time.Time) ([]domain.DoseEvent, error) {
+	args := m.Called(ctx, userID, scheduleID, from, to)
+	return args.Get(0).([]domain.DoseEvent), args.Error(1)
+}
+
+func TestRecordDose(t *testing.T) {
+	ctx := actor(1)
+	schedule := &domain.Schedule{
+		ID:         1,
+		UserID:     1,
+		Medication: "Aspirin",
+		Kind:       domain.KindFixedTimes,
+		TimesOfDay: []time.Duration{9 * time.Hour, 21 * time.Hour},
+	}
+
+	t.Run("Taken", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		event := &domain.DoseEvent{
+			UserID:     1,
+			ScheduleID: 1,
+			PlannedAt:  time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC),
+			Status:     domain.DoseTaken,
+		}
+
+		mockSchedules.On("GetByIDs", ctx, 1, 1).Return(schedule, nil)
+		mockDoses.On("Record", ctx, event).Return(nil)
+
+		assert.NoError(t, svc.RecordDose(ctx, event))
+		assert.False(t, event.TakenAt.IsZero())
+		mockDoses.AssertExpectations(t)
+		mockSchedules.AssertExpectations(t)
+	})
+
+	t.Run("Publishes recorded dose", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		events := new(MockEventPublisher)
+		svc := service.NewDoseService(mockDoses, mockSchedules, events, nil)
+
+		event := &domain.DoseEvent{
+			UserID:     1,
+			ScheduleID: 1,
+			PlannedAt:  time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			Status:     domain.DoseSkipped,
+			Reason:     "nausea",
+		}
+
+		mockSchedules.On("GetByIDs", ctx, 1, 1).Return(schedule, nil)
+		mockDoses.On("Record", ctx, event).Return(nil)
+		events.On("Publish", mock.MatchedBy(func(e domain.StreamEvent) bool {
+			return e.Type == domain.StreamDoseRecorded && e.Medication == "Aspirin" &&
+				e.At.Equal(event.PlannedAt) && e.Status == domain.DoseSkipped
+		})).Return()
+
+		assert.NoError(t, svc.RecordDose(ctx, event))
+		events.AssertExpectations(t)
+	})
+
+	t.Run("Not a planned taking", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		event := &domain.DoseEvent{
+			UserID:     1,
+			ScheduleID: 1,
+			PlannedAt:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			Status:     domain.DoseSkipped,
+			Reason:     "forgot",
+		}
+
+		mockSchedules.On("GetByIDs", ctx, 1, 1).Return(schedule, nil)
+
+		assert.ErrorIs(t, svc.RecordDose(ctx, event), myerrors.ErrTakingNotPlanned)
+		mockDoses.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Foreign schedule", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		event := &domain.DoseEvent{
+			UserID:     2,
+			ScheduleID: 1,
+			PlannedAt:  time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			Status:     domain.DoseTaken,
+		}
+
+		foreign := actor(2)
+		mockSchedules.On("GetByIDs", foreign, 2, 1).Return((*domain.Schedule)(nil), myerrors.ErrScheduleNotFound)
+
+		assert.ErrorIs(t, svc.RecordDose(foreign, event), myerrors.ErrScheduleNotFound)
+	})
+}
+
+func TestRecordDose_AsNeeded(t *testing.T) {
+	ctx := actor(1)
+	schedule := &domain.Schedule{
+		ID:          3,
+		UserID:      1,
+		Medication:  "Ibuprofen",
+		Kind:        domain.KindAsNeeded,
+		MinInterval: 6 * time.Hour,
+	}
+	takenAt := time.Date(2025, 1, 1, 10, 17, 0, 0, time.UTC)
+
+	t.Run("Taken", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		event := &domain.DoseEvent{UserID: 1, ScheduleID: 3, Status: domain.DoseTaken, TakenAt: takenAt}
+
+		mockSchedules.On("GetByIDs", ctx, 1, 3).Return(schedule, nil)
+		mockDoses.On("Record", ctx, event).Return(nil)
+
+		assert.NoError(t, svc.RecordDose(ctx, event))
+		assert.Equal(t, takenAt, event.PlannedAt)
+		mockDoses.AssertExpectations(t)
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		event := &domain.DoseEvent{UserID: 1, ScheduleID: 3, PlannedAt: takenAt, Status: domain.DoseSkipped, Reason: "no pain"}
+
+		mockSchedules.On("GetByIDs", ctx, 1, 3).Return(schedule, nil)
+
+		assert.ErrorIs(t, svc.RecordDose(ctx, event), domain.ErrAsNeededStatus)
+		mockDoses.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckIntake(t *testing.T) {
+	ctx := actor(1)
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Uses taken doses within the limit period", func(t *testing.T) {
+		mockDoses := new(MockDoseRepository)
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(mockDoses, mockSchedules, nil, nil)
+
+		schedule := &domain.Schedule{ID: 3, UserID: 1, Kind: domain.KindAsNeeded, MinInterval: 6 * time.Hour, MaxPerDay: 4}
+		mockSchedules.On("GetByIDs", ctx, 1, 3).Return(schedule, nil)
+		mockDoses.On("ListByUser", ctx, 1, 3, now.Add(-24*time.Hour), now.Add(time.Nanosecond)).Return([]domain.DoseEvent{
+			{Status: domain.DoseTaken, PlannedAt: now.Add(-2 * time.Hour), TakenAt: now.Add(-2 * time.Hour)},
+		}, nil)
+
+		decision, err := svc.CheckIntake(ctx, 1, 3, now)
+
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, now.Add(4*time.Hour), decision.EarliestAt)
+		assert.Equal(t, 1, decision.Taken)
+		mockDoses.AssertExpectations(t)
+	})
+
+	t.Run("Scheduled medication", func(t *testing.T) {
+		mockSchedules := new(MockScheduleRepository)
+		svc := service.NewDoseService(new(MockDoseRepository), mockSchedules, nil, nil)
+
+		mockSchedules.On("GetByIDs", ctx, 1, 1).Return(&domain.Schedule{ID: 1, UserID: 1, Kind: domain.KindInterval}, nil)
+
+		_, err := svc.CheckIntake(ctx, 1, 1, now)
+		assert.ErrorIs(t, err, domain.ErrNotAsNeeded)
+	})
+}
+
+func TestGetDoses_InvalidRange(t *testing.T) {
+	svc := service.NewDoseService(new(MockDoseRepository), new(MockScheduleRepository), nil, nil)
+	now := time.Now()
+
+	_, err := svc.GetDoses(actor(1), 1, 0, now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, myerrors.ErrInvalidTimeRange)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+)
+
+type InviteRepository interface {
+	Create(ctx context.Context, invite *domain.Invite, tokenHash string) error
+}
+
+// InviteService выдаёт приглашения в клинику. Выдавать их может только
+// администратор (см. handlers.Require).
+type InviteService struct {
+	invites InviteRepository
+}
+
+func NewInviteService(invites InviteRepository) *InviteService {
+	return &InviteService{invites: invites}
+}
+
+// CreateInvite выдаёт одноразовое приглашение в организацию из ctx на
+// domain.InviteTTL и возвращает его вместе с токеном для регистрации.
+func (s *InviteService) CreateInvite(ctx context.Context, now time.Time) (*domain.Invite, string, error) {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, "", myerrors.ErrUnauthorized
+	}
+
+	token, err := auth.NewSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	invite := &domain.Invite{CreatedBy: actorID, ExpiresAt: now.Add(domain.InviteTTL)}
+	if err := s.invites.Create(ctx, invite, auth.HashSecret(token)); err != nil {
+		return nil, "", fmt.Errorf("failed to create invite: %w", err)
+	}
+	return invite, token, nil
+}
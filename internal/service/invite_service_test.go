@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInviteRepository struct {
+	mock.Mock
+}
+
+func (m *MockInviteRepository) Create(ctx context.Context, invite *domain.Invite, tokenHash string) error {
+	return m.Called(ctx, invite, tokenHash).Error(0)
+}
+
+func TestCreateInvite(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		ctx := actor(1)
+		invites := new(MockInviteRepository)
+		svc := service.NewInviteService(invites)
+
+		invites.On("Create", ctx, &domain.Invite{CreatedBy: 1, ExpiresAt: now.Add(domain.InviteTTL)},
+			mock.AnythingOfType("string")).Return(nil)
+
+		invite, token, err := svc.CreateInvite(ctx, now)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, now.Add(domain.InviteTTL), invite.ExpiresAt)
+		invites.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, token)
+		invites.AssertExpectations(t)
+	})
+
+	t.Run("Without actor", func(t *testing.T) {
+		svc := service.NewInviteService(new(MockInviteRepository))
+
+		_, _, err := svc.CreateInvite(context.Background(), now)
+		assert.ErrorIs(t, err, myerrors.ErrUnauthorized)
+	})
+}
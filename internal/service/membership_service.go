@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+)
+
+type MembershipRepository interface {
+	RoleLookup
+	Upsert(ctx context.Context, membership *domain.Membership) error
+	List(ctx context.Context) ([]domain.Membership, error)
+	Delete(ctx context.Context, userID int) error
+}
+
+// MembershipService назначает роли в клинике. Менять роли может только
+// администратор или владелец организации (см. handlers.Require), причём не
+// свою: иначе клиника может остаться без администратора.
+type MembershipService struct {
+	members MembershipRepository
+	users   UserLookup
+}
+
+func NewMembershipService(members MembershipRepository, users UserLookup) *MembershipService {
+	return &MembershipService{members: members, users: users}
+}
+
+// Role возвращает роль пользователя; domain.RoleNone — если он не состоит
+// в клинике.
+func (s *MembershipService) Role(ctx context.Context, userID int) (domain.Role, error) {
+	return s.members.Role(ctx, userID)
+}
+
+func (s *MembershipService) List(ctx context.Context) ([]domain.Membership, error) {
+	return s.members.List(ctx)
+}
+
+// Assign назначает учётной записи с адресом email роль или меняет её.
+func (s *MembershipService) Assign(ctx context.Context, email string, role domain.Role) (*domain.Membership, error) {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !inOrganization(ctx, user) {
+		return nil, fmt.Errorf("failed to find user: %w", myerrors.ErrUserNotFound)
+	}
+	if user.ID == actorID {
+		return nil, domain.ErrOwnMembership
+	}
+
+	membership := &domain.Membership{UserID: user.ID, Email: user.Email, Role: role}
+	if err := s.members.Upsert(ctx, membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// Remove исключает пользователя из клиники: дальше он ведёт свои
+// расписания сам.
+func (s *MembershipService) Remove(ctx context.Context, userID int) error {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return myerrors.ErrUnauthorized
+	}
+	if userID == actorID {
+		return domain.ErrOwnMembership
+	}
+	return s.members.Delete(ctx, userID)
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMembershipRepository struct {
+	mock.Mock
+}
+
+func (m *MockMembershipRepository) Role(ctx context.Context, userID int) (domain.Role, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Role), args.Error(1)
+}
+
+func (m *MockMembershipRepository) ListByRole(ctx context.Context, role domain.Role) ([]domain.Membership, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).([]domain.Membership), args.Error(1)
+}
+
+func (m *MockMembershipRepository) Upsert(ctx context.Context, membership *domain.Membership) error {
+	return m.Called(ctx, membership).Error(0)
+}
+
+func (m *MockMembershipRepository) List(ctx context.Context) ([]domain.Membership, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Membership), args.Error(1)
+}
+
+func (m *MockMembershipRepository) Delete(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func TestAssignMembership(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Success", func(t *testing.T) {
+		members := new(MockMembershipRepository)
+		users := new(MockUserRepository)
+		svc := service.NewMembershipService(members, users)
+
+		users.On("GetByEmail", ctx, "nurse@example.com").Return(&domain.User{ID: 2, OrganizationID: 1, Email: "nurse@example.com"}, nil)
+		members.On("Upsert", ctx, &domain.Membership{UserID: 2, Email: "nurse@example.com", Role: domain.RoleNurse}).Return(nil)
+
+		membership, err := svc.Assign(ctx, "Nurse@Example.com", domain.RoleNurse)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleNurse, membership.Role)
+		members.AssertExpectations(t)
+	})
+
+	t.Run("Invalid role", func(t *testing.T) {
+		svc := service.NewMembershipService(new(MockMembershipRepository), new(MockUserRepository))
+
+		_, err := svc.Assign(ctx, "nurse@example.com", domain.RoleNone)
+		assert.ErrorIs(t, err, domain.ErrInvalidRole)
+	})
+
+	t.Run("Own membership", func(t *testing.T) {
+		members := new(MockMembershipRepository)
+		users := new(MockUserRepository)
+		svc := service.NewMembershipService(members, users)
+
+		users.On("GetByEmail", ctx, "admin@example.com").Return(&domain.User{ID: 1, OrganizationID: 1, Email: "admin@example.com"}, nil)
+
+		_, err := svc.Assign(ctx, "admin@example.com", domain.RoleDoctor)
+		assert.ErrorIs(t, err, domain.ErrOwnMembership)
+		members.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other organization", func(t *testing.T) {
+		members := new(MockMembershipRepository)
+		users := new(MockUserRepository)
+		svc := service.NewMembershipService(members, users)
+
+		users.On("GetByEmail", ctx, "nurse@other.example.com").
+			Return(&domain.User{ID: 5, OrganizationID: 2, Email: "nurse@other.example.com"}, nil)
+
+		_, err := svc.Assign(ctx, "nurse@other.example.com", domain.RoleNurse)
+		assert.ErrorIs(t, err, myerrors.ErrUserNotFound)
+		members.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRemoveMembership(t *testing.T) {
+	ctx := actor(1)
+	members := new(MockMembershipRepository)
+	svc := service.NewMembershipService(members, nil)
+
+	members.On("Delete", ctx, 2).Return(nil)
+	members.On("Delete", ctx, 3).Return(myerrors.ErrMembershipNotFound)
+
+	assert.NoError(t, svc.Remove(ctx, 2))
+	assert.ErrorIs(t, svc.Remove(ctx, 3), myerrors.ErrMembershipNotFound)
+	assert.ErrorIs(t, svc.Remove(ctx, 1), domain.ErrOwnMembership)
+	assert.ErrorIs(t, svc.Remove(context.Background(), 2), myerrors.ErrUnauthorized)
+}
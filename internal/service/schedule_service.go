@@ -4,41 +4,83 @@ import (
 	"context"
 	"fmt"
 	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"slices"
 	"time"
 )
 
+// MaxTakingsRange ограничивает интервал, за который перечисляются приёмы.
+const MaxTakingsRange = 366 * 24 * time.Hour
+
 type ScheduleRepository interface {
 	Create(ctx context.Context, schedule *domain.Schedule) error
 	GetByIDs(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error)
 	GetByUserID(ctx context.Context, userID int) ([]domain.Schedule, error)
+	GetByUserIDs(ctx context.Context, userIDs []int) ([]domain.Schedule, error)
+	GetByUserIDInRange(ctx context.Context, userID int, from, to time.Time) ([]domain.Schedule, error)
+	GetByID(ctx context.Context, scheduleID int) (*domain.Schedule, error)
+	Update(ctx context.Context, schedule *domain.Schedule) error
+	// Pause и Resume меняют только состояние расписания, не создавая редакций.
+	Pause(ctx context.Context, scheduleID int, pausedAt time.Time) error
+	Resume(ctx context.Context, scheduleID int) error
+	Delete(ctx context.Context, scheduleID int) error
+	GetHistory(ctx context.Context, scheduleID int) ([]domain.ScheduleRevision, error)
+}
+
+// EventPublisher доставляет события об изменениях клиентам пользователя в
+// реальном времени.
+type EventPublisher interface {
+	Publish(event domain.StreamEvent)
 }
 
 type ScheduleService struct {
 	repo   ScheduleRepository
 	period time.Duration
+	// rounding — округление новых расписаний, в которых оно не задано.
+	rounding domain.Rounding
+	// events может быть nil: тогда события не публикуются.
+	events EventPublisher
+	access *Access
 }
 
-func New(repo ScheduleRepository, period time.Duration) *ScheduleService {
-	return &ScheduleService{repo: repo, period: period}
+func New(repo ScheduleRepository, period time.Duration, rounding domain.Rounding, events EventPublisher, access *Access) *ScheduleService {
+	return &ScheduleService{repo: repo, period: period, rounding: rounding, events: events, access: access}
 }
 
 func (s *ScheduleService) CreateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	if err := s.access.Check(ctx, schedule.UserID, domain.PermPrescribe); err != nil {
+		return err
+	}
 	if err := schedule.Validate(); err != nil {
 		return fmt.Errorf("invalid schedule: %w", err)
 	}
 
-	schedule.StartTime = time.Now().UTC()
-	if schedule.Duration > 0 {
-		schedule.EndTime = schedule.StartTime.Add(schedule.Duration)
-	} else {
-		// Для бессрочного приема
-		schedule.EndTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	if schedule.Kind == "" {
+		schedule.Kind = domain.KindInterval
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = time.UTC.String()
 	}
+	if schedule.Rounding.IsZero() {
+		schedule.Rounding = s.rounding
+	}
+
+	if schedule.StartTime.IsZero() {
+		schedule.StartTime = time.Now().UTC()
+	}
+	schedule.SetEndTime()
 
-	return s.repo.Create(ctx, schedule)
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		return err
+	}
+	s.publishChange(schedule)
+	return nil
 }
 
 func (s *ScheduleService) GetScheduleByIDs(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error) {
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
 	schedule, err := s.repo.GetByIDs(ctx, userID, scheduleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedule: %w", err)
@@ -50,27 +92,202 @@ func (s *ScheduleService) GetScheduleByIDs(ctx context.Context, userID, schedule
 }
 
 func (s *ScheduleService) GetSchedulesByUserID(ctx context.Context, userID int) ([]domain.Schedule, error) {
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByUserID(ctx, userID)
 }
 
 func (s *ScheduleService) GetNextTakings(ctx context.Context, userID int, now time.Time) ([]domain.Schedule, error) {
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
 	schedules, err := s.repo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.nextTakings(schedules, now), nil
+}
+
+// GetHouseholdTakings — GetNextTakings для нескольких человек сразу:
+// расписания всех читаются одним запросом, результат сгруппирован по
+// людям и упорядочен по времени. Без userIDs в сводку попадают сам
+// пользователь и все пациенты, к чьим данным у него есть доступ.
+func (s *ScheduleService) GetHouseholdTakings(ctx context.Context, userIDs []int, now time.Time) ([]domain.PersonTakings, error) {
+	var err error
+	if len(userIDs) == 0 {
+		userIDs, err = s.access.Patients(ctx, domain.PermView)
+	} else {
+		userIDs = slices.Clone(userIDs)
+		slices.Sort(userIDs)
+		userIDs = slices.Compact(userIDs)
+		err = s.access.CheckAll(ctx, userIDs, domain.PermView)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+
+	people := make([]domain.PersonTakings, len(userIDs))
+	index := make(map[int]int, len(userIDs))
+	for i, userID := range userIDs {
+		people[i].UserID = userID
+		index[userID] = i
+	}
+	for _, schedule := range s.nextTakings(schedules, now) {
+		i := index[schedule.UserID]
+		people[i].Schedules = append(people[i].Schedules, schedule)
+	}
+
+	domain.SortHousehold(people)
+	return people, nil
+}
+
+// nextTakings оставляет расписания, у которых есть приём в ближайший
+// период, и записывает этот приём в Takings.
+func (s *ScheduleService) nextTakings(schedules []domain.Schedule, now time.Time) []domain.Schedule {
 	periodEnd := now.Add(s.period)
-	fmt.Println(now, periodEnd)
 	result := make([]domain.Schedule, 0, len(schedules))
 
-	for i, schedule := range schedules {
+	for _, schedule := range schedules {
 		if taking, found := schedule.FindNextTaking(now, periodEnd); found {
+			schedule.Takings = append(schedule.Takings, taking)
 			result = append(result, schedule)
-			result[i].Takings = append(result[i].Takings, taking)
 		}
 	}
 
-	fmt.Println(result)
+	return result
+}
+
+// GetTakings возвращает все запланированные приёмы пользователя в
+// [from, to) по всем расписаниям, действовавшим в этом интервале.
+func (s *ScheduleService) GetTakings(ctx context.Context, userID int, from, to time.Time) ([]domain.Occurrence, error) {
+	if !from.Before(to) || to.Sub(from) > MaxTakingsRange {
+		return nil, myerrors.ErrInvalidTimeRange
+	}
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetByUserIDInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	return domain.Occurrences(schedules, from, to), nil
+}
+
+// UpdateSchedule применяет изменение к расписанию пользователя.
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, userID, scheduleID int, patch domain.SchedulePatch) (*domain.Schedule, error) {
+	schedule, err := s.getOwned(ctx, userID, scheduleID, domain.PermPrescribe)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Apply(patch)
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+	s.publishChange(schedule)
+	return schedule, nil
+}
+
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, userID, scheduleID int) error {
+	schedule, err := s.getOwned(ctx, userID, scheduleID, domain.PermPrescribe)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, scheduleID); err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	s.publishChange(schedule)
+	return nil
+}
+
+// PauseSchedule приостанавливает расписание с момента now: приёмы после
+// него не планируются. Повторная приостановка не сдвигает исходный момент.
+func (s *ScheduleService) PauseSchedule(ctx context.Context, userID, scheduleID int, now time.Time) (*domain.Schedule, error) {
+	schedule, err := s.getOwned(ctx, userID, scheduleID, domain.PermPrescribe)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsPaused() {
+		return schedule, nil
+	}
+
+	if err := s.repo.Pause(ctx, scheduleID, now); err != nil {
+		return nil, fmt.Errorf("failed to pause schedule: %w", err)
+	}
+	schedule.PausedAt = now
+	s.publishChange(schedule)
+	return schedule, nil
+}
 
-	return result, nil
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, userID, scheduleID int) (*domain.Schedule, error) {
+	schedule, err := s.getOwned(ctx, userID, scheduleID, domain.PermPrescribe)
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.IsPaused() {
+		return schedule, nil
+	}
+
+	if err := s.repo.Resume(ctx, scheduleID); err != nil {
+		return nil, fmt.Errorf("failed to resume schedule: %w", err)
+	}
+	schedule.PausedAt = time.Time{}
+	s.publishChange(schedule)
+	return schedule, nil
+}
+
+// GetScheduleHistory возвращает все редакции расписания пользователя.
+func (s *ScheduleService) GetScheduleHistory(ctx context.Context, userID, scheduleID int) ([]domain.ScheduleRevision, error) {
+	if _, err := s.getOwned(ctx, userID, scheduleID, domain.PermView); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetHistory(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule history: %w", err)
+	}
+	return history, nil
+}
+
+func (s *ScheduleService) publishChange(schedule *domain.Schedule) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(domain.StreamEvent{
+		Type:       domain.StreamScheduleChanged,
+		UserID:     schedule.UserID,
+		ScheduleID: schedule.ID,
+		Medication: schedule.Medication,
+		At:         time.Now().UTC(),
+	})
+}
+
+// getOwned проверяет доступ к данным userID, загружает расписание и
+// проверяет, что оно принадлежит userID.
+func (s *ScheduleService) getOwned(ctx context.Context, userID, scheduleID int, need domain.Permission) (*domain.Schedule, error) {
+	if err := s.access.Check(ctx, userID, need); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.repo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if schedule.UserID != userID {
+		return nil, myerrors.ErrForbidden
+	}
+	return schedule, nil
 }
@@ -2,7 +2,9 @@ package service_test
 
 import (
 	"context"
+	"medication-scheduler/internal/auth"
 	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
 	"medication-scheduler/internal/service"
 	"testing"
 	"time"
@@ -30,10 +32,54 @@ func (m *MockScheduleRepository) GetByUserID(ctx context.Context, userID int) ([
 	return args.Get(0).([]domain.Schedule), args.Error(1)
 }
 
+func (m *MockScheduleRepository) GetByUserIDs(ctx context.Context, userIDs []int) ([]domain.Schedule, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) GetByID(ctx context.Context, scheduleID int) (*domain.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	schedule, _ := args.Get(0).(*domain.Schedule)
+	return schedule, args.Error(1)
+}
+
+func (m *MockScheduleRepository) Update(ctx context.Context, schedule *domain.Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) Pause(ctx context.Context, scheduleID int, pausedAt time.Time) error {
+	args := m.Called(ctx, scheduleID, pausedAt)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) Resume(ctx context.Context, scheduleID int) error {
+	args := m.Called(ctx, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) Delete(ctx context.Context, scheduleID int) error {
+	args := m.Called(ctx, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) GetHistory(ctx context.Context, scheduleID int) ([]domain.ScheduleRevision, error) {
+	args := m.Called(ctx, scheduleID)
+	return args.Get(0).([]domain.ScheduleRevision), args.Error(1)
+}
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(event domain.StreamEvent) {
+	m.Called(event)
+}
+
 func TestCreateSchedule(t *testing.T) {
 	mockRepo := new(MockScheduleRepository)
-	svc := service.New(mockRepo, time.Hour)
-	ctx := context.Background()
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+	ctx := actor(1)
 
 	schedule := &domain.Schedule{
 		UserID:     1,
@@ -51,10 +97,101 @@ func TestCreateSchedule(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateSchedule_Rounding(t *testing.T) {
+	deployment := domain.Rounding{Mode: domain.RoundNearest, Step: 5 * time.Minute}
+	own := domain.Rounding{Mode: domain.RoundNone}
+
+	mockRepo := new(MockScheduleRepository)
+	svc := service.New(mockRepo, time.Hour, deployment, nil, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	inherited := &domain.Schedule{UserID: 1, Medication: "Amoxicillin", Frequency: 8 * time.Hour}
+	custom := &domain.Schedule{UserID: 1, Medication: "Insulin", Frequency: 8 * time.Hour, Rounding: own}
+
+	assert.NoError(t, svc.CreateSchedule(actor(1), inherited))
+	assert.NoError(t, svc.CreateSchedule(actor(1), custom))
+	assert.Equal(t, deployment, inherited.Rounding)
+	assert.Equal(t, own, custom.Rounding)
+}
+
+func TestCreateSchedule_InvalidDosage(t *testing.T) {
+	mockRepo := new(MockScheduleRepository)
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+	schedule := &domain.Schedule{
+		UserID:     1,
+		Medication: "Aspirin",
+		Dosage:     domain.Dosage{Amount: 500, Unit: "spoon"},
+		Frequency:  30 * time.Minute,
+	}
+
+	err := svc.CreateSchedule(actor(1), schedule)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidDosage)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateSchedule_FutureStart(t *testing.T) {
+	mockRepo := new(MockScheduleRepository)
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+	ctx := actor(1)
+
+	start := time.Now().UTC().Add(72 * time.Hour)
+	schedule := &domain.Schedule{
+		UserID:     1,
+		Medication: "Aspirin",
+		Frequency:  30 * time.Minute,
+		Duration:   24 * time.Hour,
+		StartTime:  start,
+	}
+
+	mockRepo.On("Create", ctx, schedule).Return(nil)
+	err := svc.CreateSchedule(ctx, schedule)
+
+	assert.NoError(t, err)
+	assert.Equal(t, start, schedule.StartTime)
+	assert.Equal(t, start.Add(24*time.Hour), schedule.EndTime)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateSchedule_ClinicStaff(t *testing.T) {
+	mockRepo := new(MockScheduleRepository)
+	mockGrants := new(MockGrantRepository)
+	mockMembers := new(MockMembershipRepository)
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, service.NewAccess(mockGrants, mockMembers))
+	nurse, doctor := actor(5), actor(6)
+
+	mockMembers.On("Role", mock.Anything, 1).Return(domain.RolePatient, nil)
+	mockMembers.On("Role", mock.Anything, 5).Return(domain.RoleNurse, nil)
+	mockMembers.On("Role", mock.Anything, 6).Return(domain.RoleDoctor, nil)
+	mockGrants.On("Get", nurse, 1, 5).Return(nil, myerrors.ErrGrantNotFound)
+	mockRepo.On("Create", doctor, mock.Anything).Return(nil)
+
+	t.Run("Nurse cannot prescribe", func(t *testing.T) {
+		schedule := &domain.Schedule{UserID: 1, Medication: "Aspirin", Frequency: 8 * time.Hour}
+
+		err := svc.CreateSchedule(nurse, schedule)
+
+		var permErr *myerrors.PermissionError
+		if assert.ErrorAs(t, err, &permErr) {
+			assert.Equal(t, domain.RoleNurse, permErr.Role)
+			assert.Equal(t, domain.PermPrescribe, permErr.Permission)
+		}
+		mockRepo.AssertNotCalled(t, "Create", nurse, mock.Anything)
+	})
+
+	t.Run("Doctor prescribes", func(t *testing.T) {
+		schedule := &domain.Schedule{UserID: 1, Medication: "Aspirin", Frequency: 8 * time.Hour}
+
+		assert.NoError(t, svc.CreateSchedule(doctor, schedule))
+		mockRepo.AssertCalled(t, "Create", doctor, schedule)
+	})
+}
+
 func TestGetScheduleByIDs(t *testing.T) {
 	mockRepo := new(MockScheduleRepository)
-	svc := service.New(mockRepo, time.Hour)
-	ctx := context.Background()
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+	ctx := actor(1)
 
 	schedule := &domain.Schedule{
 		ID:         1,
@@ -77,8 +214,8 @@ func TestGetScheduleByIDs(t *testing.T) {
 
 func TestGetSchedulesByUserID(t *testing.T) {
 	mockRepo := new(MockScheduleRepository)
-	svc := service.New(mockRepo, time.Hour)
-	ctx := context.Background()
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+	ctx := actor(1)
 
 	schedules := []domain.Schedule{{ID: 1, UserID: 1, Medication: "Aspirin"}}
 
@@ -92,8 +229,8 @@ func TestGetSchedulesByUserID(t *testing.T) {
 
 func TestGetNextTakings(t *testing.T) {
 	mockRepo := new(MockScheduleRepository)
-	svc := service.New(mockRepo, time.Hour)
-	ctx := context.Background()
+	svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+	ctx := actor(1)
 
 	schedules := []domain.Schedule{{ID: 1, UserID: 1, Medication: "Aspirin", Frequency: 30 * time.Minute}}
 
@@ -105,3 +242,323 @@ func TestGetNextTakings(t *testing.T) {
 	assert.Len(t, res, 1)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetHouseholdTakings(t *testing.T) {
+	ctx := actor(1)
+	now := time.Date(2025, 1, 1, 9, 15, 0, 0, time.UTC)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	schedules := []domain.Schedule{
+		{ID: 1, UserID: 1, Medication: "Aspirin", Frequency: 4 * time.Hour, Timezone: "UTC", StartTime: start},
+		{ID: 2, UserID: 3, Medication: "Insulin", Frequency: time.Hour, Timezone: "UTC", StartTime: start},
+	}
+	grants := []domain.Grant{
+		{PatientID: 3, CaregiverID: 1, Access: domain.AccessRead, Status: domain.GrantAccepted},
+		{PatientID: 4, CaregiverID: 1, Access: domain.AccessRead, Status: domain.GrantPending},
+	}
+
+	for _, tt := range []struct {
+		name    string
+		userIDs []int
+	}{
+		{"Selected people", []int{3, 1, 3}},
+		{"Everyone available", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockScheduleRepository)
+			mockGrants := new(MockGrantRepository)
+			mockMembers := new(MockMembershipRepository)
+			svc := service.New(mockRepo, 24*time.Hour, domain.DefaultRounding, nil, service.NewAccess(mockGrants, mockMembers))
+
+			mockMembers.On("Role", ctx, 1).Return(domain.RoleNone, nil)
+			mockGrants.On("ListByCaregiver", ctx, 1).Return(grants, nil)
+			mockRepo.On("GetByUserIDs", ctx, []int{1, 3}).Return(schedules, nil)
+
+			people, err := svc.GetHouseholdTakings(ctx, tt.userIDs, now)
+
+			assert.NoError(t, err)
+			if assert.Len(t, people, 2) {
+				assert.Equal(t, 3, people[0].UserID)
+				assert.Equal(t, []time.Time{start.Add(10 * time.Hour)}, people[0].Schedules[0].Takings)
+				assert.Equal(t, 1, people[1].UserID)
+				assert.Equal(t, []time.Time{start.Add(12 * time.Hour)}, people[1].Schedules[0].Takings)
+			}
+			mockRepo.AssertNumberOfCalls(t, "GetByUserIDs", 1)
+			mockGrants.AssertNumberOfCalls(t, "ListByCaregiver", 1)
+		})
+	}
+
+	t.Run("Patient without grant", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		mockGrants := new(MockGrantRepository)
+		mockMembers := new(MockMembershipRepository)
+		svc := service.New(mockRepo, 24*time.Hour, domain.DefaultRounding, nil, service.NewAccess(mockGrants, mockMembers))
+
+		mockMembers.On("Role", ctx, 1).Return(domain.RoleNone, nil)
+		mockGrants.On("ListByCaregiver", ctx, 1).Return(grants, nil)
+
+		_, err := svc.GetHouseholdTakings(ctx, []int{1, 4}, now)
+
+		assert.ErrorIs(t, err, myerrors.ErrAccessDenied)
+		mockRepo.AssertNotCalled(t, "GetByUserIDs", mock.Anything, mock.Anything)
+	})
+}
+
+// actor — контекст запроса от имени пользователя организации 1.
+func actor(userID int) context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{UserID: userID, OrganizationID: 1})
+}
+
+func ownedSchedule() *domain.Schedule {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Schedule{
+		ID:         7,
+		UserID:     1,
+		Medication: "Aspirin",
+		Kind:       domain.KindInterval,
+		Frequency:  4 * time.Hour,
+		Duration:   48 * time.Hour,
+		Timezone:   "UTC",
+		StartTime:  start,
+		EndTime:    start.Add(48 * time.Hour),
+	}
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Applies patch", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+		mockRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+		medication := "Ibuprofen"
+		duration := time.Duration(0)
+		res, err := svc.UpdateSchedule(ctx, 1, 7, domain.SchedulePatch{
+			Medication: &medication,
+			Regimen:    &domain.Regimen{Kind: domain.KindFixedTimes, TimesOfDay: []time.Duration{9 * time.Hour}},
+			Duration:   &duration,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Ibuprofen", res.Medication)
+		assert.Equal(t, domain.KindFixedTimes, res.Kind)
+		assert.Zero(t, res.Frequency)
+		assert.Equal(t, domain.PerpetualEndTime, res.EndTime)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid result", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+
+		timezone := "Mars/Olympus"
+		_, err := svc.UpdateSchedule(ctx, 1, 7, domain.SchedulePatch{Timezone: &timezone})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTimezone)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Foreign schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		foreign := actor(2)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", foreign, 7).Return(ownedSchedule(), nil)
+
+		_, err := svc.UpdateSchedule(foreign, 2, 7, domain.SchedulePatch{})
+
+		assert.ErrorIs(t, err, myerrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(nil, myerrors.ErrScheduleNotFound)
+
+		_, err := svc.UpdateSchedule(ctx, 1, 7, domain.SchedulePatch{})
+
+		assert.ErrorIs(t, err, myerrors.ErrScheduleNotFound)
+	})
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Owner", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+		mockRepo.On("Delete", ctx, 7).Return(nil)
+
+		assert.NoError(t, svc.DeleteSchedule(ctx, 1, 7))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Foreign schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		foreign := actor(2)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", foreign, 7).Return(ownedSchedule(), nil)
+
+		assert.ErrorIs(t, svc.DeleteSchedule(foreign, 2, 7), myerrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Publishes change", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		events := new(MockEventPublisher)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, events, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+		mockRepo.On("Delete", ctx, 7).Return(nil)
+		events.On("Publish", mock.MatchedBy(func(e domain.StreamEvent) bool {
+			return e.Type == domain.StreamScheduleChanged && e.UserID == 1 && e.ScheduleID == 7
+		})).Return()
+
+		assert.NoError(t, svc.DeleteSchedule(ctx, 1, 7))
+		events.AssertExpectations(t)
+	})
+}
+
+func TestPauseAndResumeSchedule(t *testing.T) {
+	ctx := actor(1)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Pause", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+		mockRepo.On("Pause", ctx, 7, now).Return(nil)
+
+		res, err := svc.PauseSchedule(ctx, 1, 7, now)
+
+		assert.NoError(t, err)
+		assert.True(t, res.IsPaused())
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pause is idempotent", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		paused := ownedSchedule()
+		paused.PausedAt = now.Add(-time.Hour)
+		mockRepo.On("GetByID", ctx, 7).Return(paused, nil)
+
+		res, err := svc.PauseSchedule(ctx, 1, 7, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(-time.Hour), res.PausedAt)
+		mockRepo.AssertNotCalled(t, "Pause", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		paused := ownedSchedule()
+		paused.PausedAt = now
+		mockRepo.On("GetByID", ctx, 7).Return(paused, nil)
+		mockRepo.On("Resume", ctx, 7).Return(nil)
+
+		res, err := svc.ResumeSchedule(ctx, 1, 7)
+
+		assert.NoError(t, err)
+		assert.False(t, res.IsPaused())
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Foreign schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		foreign := actor(2)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", foreign, 7).Return(ownedSchedule(), nil)
+
+		_, err := svc.PauseSchedule(foreign, 2, 7, now)
+		assert.ErrorIs(t, err, myerrors.ErrForbidden)
+	})
+}
+
+func TestGetScheduleHistory(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Owner", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		history := []domain.ScheduleRevision{{Number: 1}, {Number: 2}}
+		mockRepo.On("GetByID", ctx, 7).Return(ownedSchedule(), nil)
+		mockRepo.On("GetHistory", ctx, 7).Return(history, nil)
+
+		res, err := svc.GetScheduleHistory(ctx, 1, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, history, res)
+	})
+
+	t.Run("Foreign schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		foreign := actor(2)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		mockRepo.On("GetByID", foreign, 7).Return(ownedSchedule(), nil)
+
+		_, err := svc.GetScheduleHistory(foreign, 2, 7)
+
+		assert.ErrorIs(t, err, myerrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "GetHistory", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetTakings(t *testing.T) {
+	ctx := actor(1)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		schedules := []domain.Schedule{{
+			ID:         1,
+			Medication: "Aspirin",
+			Kind:       domain.KindFixedTimes,
+			TimesOfDay: []time.Duration{9 * time.Hour},
+			Timezone:   "UTC",
+			StartTime:  from,
+			EndTime:    domain.PerpetualEndTime,
+		}}
+		mockRepo.On("GetByUserIDInRange", ctx, 1, from, to).Return(schedules, nil)
+
+		occurrences, err := svc.GetTakings(ctx, 1, from, to)
+
+		assert.NoError(t, err)
+		if assert.Len(t, occurrences, 2) {
+			assert.Equal(t, 1, occurrences[0].ScheduleID)
+			assert.Equal(t, from.Add(9*time.Hour), occurrences[0].At)
+		}
+	})
+
+	t.Run("Range too long", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		svc := service.New(mockRepo, time.Hour, domain.DefaultRounding, nil, nil)
+
+		_, err := svc.GetTakings(ctx, 1, from, from.Add(service.MaxTakingsRange+time.Hour))
+
+		assert.ErrorIs(t, err, myerrors.ErrInvalidTimeRange)
+		mockRepo.AssertNotCalled(t, "GetByUserIDInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+)
+
+type SettingsRepository interface {
+	Get(ctx context.Context, userID int) (*domain.UserSettings, error)
+	Upsert(ctx context.Context, settings *domain.UserSettings) error
+}
+
+type SettingsService struct {
+	repo   SettingsRepository
+	access *Access
+}
+
+func NewSettingsService(repo SettingsRepository, access *Access) *SettingsService {
+	return &SettingsService{repo: repo, access: access}
+}
+
+func (s *SettingsService) GetSettings(ctx context.Context, userID int) (*domain.UserSettings, error) {
+	if err := s.access.Check(ctx, userID, domain.PermView); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.Get(ctx, userID)
+	if errors.Is(err, myerrors.ErrSettingsNotFound) {
+		return &domain.UserSettings{UserID: userID, Window: domain.DefaultDayWindow}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *SettingsService) UpdateSettings(ctx context.Context, settings *domain.UserSettings) error {
+	if err := s.access.Check(ctx, settings.UserID, domain.PermPrescribe); err != nil {
+		return err
+	}
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+	return s.repo.Upsert(ctx, settings)
+}
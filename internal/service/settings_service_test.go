@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettingsRepository) Get(ctx context.Context, userID int) (*domain.UserSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*domain.UserSettings), args.Error(1)
+}
+
+func (m *MockSettingsRepository) Upsert(ctx context.Context, settings *domain.UserSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func TestGetSettings_Default(t *testing.T) {
+	mockRepo := new(MockSettingsRepository)
+	svc := service.NewSettingsService(mockRepo, nil)
+	ctx := actor(1)
+
+	mockRepo.On("Get", ctx, 1).Return((*domain.UserSettings)(nil), myerrors.ErrSettingsNotFound)
+	res, err := svc.GetSettings(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DefaultDayWindow, res.Window)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	mockRepo := new(MockSettingsRepository)
+	svc := service.NewSettingsService(mockRepo, nil)
+	ctx := actor(1)
+
+	t.Run("Night shift window", func(t *testing.T) {
+		settings := &domain.UserSettings{
+			UserID: 1,
+			Window: domain.DayWindow{Start: 20 * time.Hour, End: 8 * time.Hour},
+		}
+
+		mockRepo.On("Upsert", ctx, settings).Return(nil).Once()
+		assert.NoError(t, svc.UpdateSettings(ctx, settings))
+	})
+
+	t.Run("Empty window", func(t *testing.T) {
+		settings := &domain.UserSettings{
+			UserID: 1,
+			Window: domain.DayWindow{Start: 9 * time.Hour, End: 9 * time.Hour},
+		}
+
+		err := svc.UpdateSettings(ctx, settings)
+		assert.ErrorIs(t, err, domain.ErrInvalidWindow)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
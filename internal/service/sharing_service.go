@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+)
+
+type GrantRepository interface {
+	GrantLookup
+	Upsert(ctx context.Context, grant *domain.Grant) error
+	GetByID(ctx context.Context, grantID int) (*domain.Grant, error)
+	ListByPatient(ctx context.Context, patientID int) ([]domain.Grant, error)
+	ListByCaregiver(ctx context.Context, caregiverID int) ([]domain.Grant, error)
+	Accept(ctx context.Context, grantID, caregiverID int, now time.Time) error
+	Delete(ctx context.Context, grantID int) error
+}
+
+type UserLookup interface {
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+type HouseholdTakingsProvider interface {
+	GetHouseholdTakings(ctx context.Context, userIDs []int, now time.Time) ([]domain.PersonTakings, error)
+}
+
+// SharingService управляет доступом доверенных лиц. Все методы действуют
+// от имени пользователя из ctx: пациент выдаёт и отзывает доступ,
+// доверенное лицо принимает приглашения и отказывается от них.
+type SharingService struct {
+	grants    GrantRepository
+	users     UserLookup
+	schedules HouseholdTakingsProvider
+}
+
+func NewSharingService(grants GrantRepository, users UserLookup, schedules HouseholdTakingsProvider) *SharingService {
+	return &SharingService{grants: grants, users: users, schedules: schedules}
+}
+
+// Invite приглашает учётную запись с адресом email. Если доступ уже
+// выдан, меняются только права.
+func (s *SharingService) Invite(ctx context.Context, email string, access domain.AccessLevel) (*domain.Grant, error) {
+	patientID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	if err := access.Validate(); err != nil {
+		return nil, err
+	}
+
+	caregiver, err := s.users.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find caregiver: %w", err)
+	}
+	if !inOrganization(ctx, caregiver) {
+		return nil, fmt.Errorf("failed to find caregiver: %w", myerrors.ErrUserNotFound)
+	}
+	if caregiver.ID == patientID {
+		return nil, domain.ErrSelfGrant
+	}
+
+	grant := &domain.Grant{
+		PatientID:      patientID,
+		CaregiverID:    caregiver.ID,
+		CaregiverEmail: caregiver.Email,
+		Access:         access,
+	}
+	if err := s.grants.Upsert(ctx, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// ListGranted возвращает доступы, выданные пользователем.
+func (s *SharingService) ListGranted(ctx context.Context) ([]domain.Grant, error) {
+	patientID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	return s.grants.ListByPatient(ctx, patientID)
+}
+
+// ListReceived возвращает доступы и приглашения, полученные пользователем.
+func (s *SharingService) ListReceived(ctx context.Context) ([]domain.Grant, error) {
+	caregiverID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	return s.grants.ListByCaregiver(ctx, caregiverID)
+}
+
+func (s *SharingService) Accept(ctx context.Context, grantID int, now time.Time) (*domain.Grant, error) {
+	caregiverID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return nil, myerrors.ErrUnauthorized
+	}
+	if err := s.grants.Accept(ctx, grantID, caregiverID, now); err != nil {
+		return nil, err
+	}
+	return s.grants.GetByID(ctx, grantID)
+}
+
+// Revoke удаляет доступ. Это может сделать пациент или само доверенное
+// лицо; для остальных доступ не существует.
+func (s *SharingService) Revoke(ctx context.Context, grantID int) error {
+	actorID, ok := auth.UserIDFrom(ctx)
+	if !ok {
+		return myerrors.ErrUnauthorized
+	}
+
+	grant, err := s.grants.GetByID(ctx, grantID)
+	if err != nil {
+		return err
+	}
+	if grant.PatientID != actorID && grant.CaregiverID != actorID {
+		return myerrors.ErrGrantNotFound
+	}
+	return s.grants.Delete(ctx, grantID)
+}
+
+// GetPatients возвращает пациентов, доступ к которым принят
+// пользователем, с ближайшими приёмами каждого, по времени ближайшего
+// приёма.
+func (s *SharingService) GetPatients(ctx context.Context, now time.Time) ([]domain.PatientTakings, error) {
+	grants, err := s.ListReceived(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make(map[int]domain.Grant, len(grants))
+	patientIDs := make([]int, 0, len(grants))
+	for _, grant := range grants {
+		if grant.Status == domain.GrantAccepted {
+			accepted[grant.PatientID] = grant
+			patientIDs = append(patientIDs, grant.PatientID)
+		}
+	}
+	if len(patientIDs) == 0 {
+		return []domain.PatientTakings{}, nil
+	}
+
+	people, err := s.schedules.GetHouseholdTakings(ctx, patientIDs, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next takings of patients: %w", err)
+	}
+
+	patients := make([]domain.PatientTakings, 0, len(people))
+	for _, person := range people {
+		patients = append(patients, domain.PatientTakings{Grant: accepted[person.UserID], Schedules: person.Schedules})
+	}
+	return patients, nil
+}
@@ -0,0 +1,189 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGrantRepository struct {
+	mock.Mock
+}
+
+func (m *MockGrantRepository) Get(ctx context.Context, patientID, caregiverID int) (*domain.Grant, error) {
+	args := m.Called(ctx, patientID, caregiverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) Upsert(ctx context.Context, grant *domain.Grant) error {
+	return m.Called(ctx, grant).Error(0)
+}
+
+func (m *MockGrantRepository) GetByID(ctx context.Context, grantID int) (*domain.Grant, error) {
+	args := m.Called(ctx, grantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) ListByPatient(ctx context.Context, patientID int) ([]domain.Grant, error) {
+	args := m.Called(ctx, patientID)
+	return args.Get(0).([]domain.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) ListByCaregiver(ctx context.Context, caregiverID int) ([]domain.Grant, error) {
+	args := m.Called(ctx, caregiverID)
+	return args.Get(0).([]domain.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) Accept(ctx context.Context, grantID, caregiverID int, now time.Time) error {
+	return m.Called(ctx, grantID, caregiverID, now).Error(0)
+}
+
+func (m *MockGrantRepository) Delete(ctx context.Context, grantID int) error {
+	return m.Called(ctx, grantID).Error(0)
+}
+
+type MockHouseholdTakings struct {
+	mock.Mock
+}
+
+func (m *MockHouseholdTakings) GetHouseholdTakings(ctx context.Context, userIDs []int, now time.Time) ([]domain.PersonTakings, error) {
+	args := m.Called(ctx, userIDs, now)
+	return args.Get(0).([]domain.PersonTakings), args.Error(1)
+}
+
+func TestInvite(t *testing.T) {
+	ctx := actor(1)
+
+	t.Run("Success", func(t *testing.T) {
+		grants := new(MockGrantRepository)
+		users := new(MockUserRepository)
+		svc := service.NewSharingService(grants, users, nil)
+
+		users.On("GetByEmail", ctx, "mom@example.com").Return(&domain.User{ID: 2, OrganizationID: 1, Email: "mom@example.com"}, nil)
+		grants.On("Upsert", ctx, mock.Anything).Return(nil)
+
+		grant, err := svc.Invite(ctx, " Mom@Example.com", domain.AccessRead)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, grant.PatientID)
+		assert.Equal(t, 2, grant.CaregiverID)
+		assert.Equal(t, domain.AccessRead, grant.Access)
+	})
+
+	t.Run("Invalid access", func(t *testing.T) {
+		svc := service.NewSharingService(new(MockGrantRepository), new(MockUserRepository), nil)
+
+		_, err := svc.Invite(ctx, "mom@example.com", "owner")
+		assert.ErrorIs(t, err, domain.ErrInvalidAccess)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		grants := new(MockGrantRepository)
+		users := new(MockUserRepository)
+		svc := service.NewSharingService(grants, users, nil)
+
+		users.On("GetByEmail", ctx, "me@example.com").Return(&domain.User{ID: 1, OrganizationID: 1, Email: "me@example.com"}, nil)
+
+		_, err := svc.Invite(ctx, "me@example.com", domain.AccessManage)
+		assert.ErrorIs(t, err, domain.ErrSelfGrant)
+		grants.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other organization", func(t *testing.T) {
+		grants := new(MockGrantRepository)
+		users := new(MockUserRepository)
+		svc := service.NewSharingService(grants, users, nil)
+
+		users.On("GetByEmail", ctx, "doctor@other.example.com").
+			Return(&domain.User{ID: 5, OrganizationID: 2, Email: "doctor@other.example.com"}, nil)
+
+		_, err := svc.Invite(ctx, "doctor@other.example.com", domain.AccessRead)
+		assert.ErrorIs(t, err, myerrors.ErrUserNotFound)
+		grants.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		users := new(MockUserRepository)
+		svc := service.NewSharingService(new(MockGrantRepository), users, nil)
+
+		users.On("GetByEmail", ctx, "nobody@example.com").Return(nil, myerrors.ErrUserNotFound)
+
+		_, err := svc.Invite(ctx, "nobody@example.com", domain.AccessRead)
+		assert.ErrorIs(t, err, myerrors.ErrUserNotFound)
+	})
+
+	t.Run("No actor", func(t *testing.T) {
+		svc := service.NewSharingService(new(MockGrantRepository), new(MockUserRepository), nil)
+
+		_, err := svc.Invite(context.Background(), "mom@example.com", domain.AccessRead)
+		assert.ErrorIs(t, err, myerrors.ErrUnauthorized)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	grant := &domain.Grant{ID: 5, PatientID: 1, CaregiverID: 2}
+
+	for _, actorID := range []int{1, 2} {
+		grants := new(MockGrantRepository)
+		svc := service.NewSharingService(grants, nil, nil)
+		ctx := actor(actorID)
+
+		grants.On("GetByID", ctx, 5).Return(grant, nil)
+		grants.On("Delete", ctx, 5).Return(nil)
+
+		assert.NoError(t, svc.Revoke(ctx, 5))
+		grants.AssertCalled(t, "Delete", ctx, 5)
+	}
+
+	t.Run("Stranger", func(t *testing.T) {
+		grants := new(MockGrantRepository)
+		svc := service.NewSharingService(grants, nil, nil)
+		ctx := actor(3)
+
+		grants.On("GetByID", ctx, 5).Return(grant, nil)
+
+		assert.ErrorIs(t, svc.Revoke(ctx, 5), myerrors.ErrGrantNotFound)
+		grants.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetPatients(t *testing.T) {
+	ctx := actor(2)
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	grants := new(MockGrantRepository)
+	schedules := new(MockHouseholdTakings)
+	svc := service.NewSharingService(grants, nil, schedules)
+
+	grants.On("ListByCaregiver", ctx, 2).Return([]domain.Grant{
+		{ID: 1, PatientID: 1, CaregiverID: 2, Access: domain.AccessRead, Status: domain.GrantAccepted},
+		{ID: 2, PatientID: 3, CaregiverID: 2, Status: domain.GrantPending},
+		{ID: 3, PatientID: 4, CaregiverID: 2, Access: domain.AccessManage, Status: domain.GrantAccepted},
+	}, nil)
+	schedules.On("GetHouseholdTakings", ctx, []int{1, 4}, now).Return([]domain.PersonTakings{
+		{UserID: 4, Schedules: []domain.Schedule{{ID: 9, UserID: 4}}},
+		{UserID: 1},
+	}, nil)
+
+	patients, err := svc.GetPatients(ctx, now)
+
+	assert.NoError(t, err)
+	if assert.Len(t, patients, 2) {
+		assert.Equal(t, 3, patients[0].Grant.ID)
+		assert.Equal(t, 9, patients[0].Schedules[0].ID)
+		assert.Equal(t, domain.AccessRead, patients[1].Grant.Access)
+	}
+	schedules.AssertNumberOfCalls(t, "GetHouseholdTakings", 1)
+}
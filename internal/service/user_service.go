@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserRepository interface {
+	CreateWithOrganization(ctx context.Context, user *domain.User) error
+	CreateInvited(ctx context.Context, user *domain.User, inviteHash string, now time.Time) error
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+type TokenIssuer interface {
+	Issue(identity auth.Identity, now time.Time) (string, time.Time, error)
+}
+
+type UserService struct {
+	repo   UserRepository
+	tokens TokenIssuer
+}
+
+func NewUserService(repo UserRepository, tokens TokenIssuer) *UserService {
+	return &UserService{repo: repo, tokens: tokens}
+}
+
+// dummyHash сравнивается с паролем, когда адрес не найден, чтобы по времени
+// ответа нельзя было узнать, зарегистрирован ли адрес.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// Register создаёт учётную запись. С приглашением invite пользователь
+// попадает в организацию, выдавшую приглашение; без него — в собственную
+// новую организацию, где он ведёт свои расписания и управляет участниками
+// (domain.RoleOwner). Чужую клинику без приглашения
+// выбрать нельзя.
+func (s *UserService) Register(ctx context.Context, email, password, invite string, now time.Time) (*domain.User, error) {
+	email = domain.NormalizeEmail(email)
+	if err := domain.ValidateCredentials(email, password); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &domain.User{Email: email, PasswordHash: hash}
+	if invite == "" {
+		err = s.repo.CreateWithOrganization(ctx, user)
+	} else {
+		err = s.repo.CreateInvited(ctx, user, auth.HashSecret(invite), now)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// Login проверяет пароль и выдаёт токен. Неизвестный адрес и неверный
+// пароль неразличимы для клиента: оба возвращают ErrInvalidCredentials.
+func (s *UserService) Login(ctx context.Context, email, password string, now time.Time) (*domain.Session, error) {
+	user, err := s.repo.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if errors.Is(err, myerrors.ErrUserNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, myerrors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)); err != nil {
+		return nil, myerrors.ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := s.tokens.Issue(auth.Identity{UserID: user.ID, OrganizationID: user.OrganizationID}, now)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Session{
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		Token:          token,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	myerrors "medication-scheduler/internal/errors"
+	"medication-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateWithOrganization(ctx context.Context, user *domain.User) error {
+	return m.Called(ctx, user).Error(0)
+}
+
+func (m *MockUserRepository) CreateInvited(ctx context.Context, user *domain.User, inviteHash string, now time.Time) error {
+	return m.Called(ctx, user, inviteHash, now).Error(0)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+type MockTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockTokenIssuer) Issue(identity auth.Identity, now time.Time) (string, time.Time, error) {
+	args := m.Called(identity, now)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Own organization without invite", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		repo.On("CreateWithOrganization", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "patient@example.com" && u.OrganizationID == 0 &&
+				bcrypt.CompareHashAndPassword(u.PasswordHash, []byte("correct horse")) == nil
+		})).Return(nil)
+
+		user, err := svc.Register(ctx, " Patient@Example.com", "correct horse", "", now)
+		assert.NoError(t, err)
+		assert.Equal(t, "patient@example.com", user.Email)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "CreateInvited", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invite", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		repo.On("CreateInvited", ctx, mock.Anything, auth.HashSecret("invite-token"), now).Return(nil)
+
+		_, err := svc.Register(ctx, "patient@example.com", "correct horse", "invite-token", now)
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "CreateWithOrganization", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid invite", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		repo.On("CreateInvited", ctx, mock.Anything, mock.Anything, now).Return(myerrors.ErrInvalidInvite)
+
+		_, err := svc.Register(ctx, "patient@example.com", "correct horse", "stale", now)
+		assert.ErrorIs(t, err, myerrors.ErrInvalidInvite)
+	})
+
+	t.Run("Weak password", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		_, err := svc.Register(ctx, "patient@example.com", "short", "", now)
+		assert.ErrorIs(t, err, domain.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "CreateWithOrganization", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Email taken", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		repo.On("CreateWithOrganization", ctx, mock.Anything).Return(myerrors.ErrEmailTaken)
+
+		_, err := svc.Register(ctx, "patient@example.com", "correct horse", "", now)
+		assert.ErrorIs(t, err, myerrors.ErrEmailTaken)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	user := &domain.User{ID: 7, OrganizationID: 2, Email: "patient@example.com", PasswordHash: hash}
+
+	t.Run("Issues token", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		tokens := new(MockTokenIssuer)
+		svc := service.NewUserService(repo, tokens)
+
+		repo.On("GetByEmail", ctx, "patient@example.com").Return(user, nil)
+		tokens.On("Issue", auth.Identity{UserID: 7, OrganizationID: 2}, now).Return("token", now.Add(time.Hour), nil)
+
+		session, err := svc.Login(ctx, "Patient@example.com", "correct horse", now)
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.Session{UserID: 7, OrganizationID: 2, Token: "token", ExpiresAt: now.Add(time.Hour)}, session)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		tokens := new(MockTokenIssuer)
+		svc := service.NewUserService(repo, tokens)
+
+		repo.On("GetByEmail", ctx, "patient@example.com").Return(user, nil)
+
+		_, err := svc.Login(ctx, "patient@example.com", "wrong horse", now)
+		assert.ErrorIs(t, err, myerrors.ErrInvalidCredentials)
+		tokens.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		svc := service.NewUserService(repo, new(MockTokenIssuer))
+
+		repo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, myerrors.ErrUserNotFound)
+
+		_, err := svc.Login(ctx, "nobody@example.com", "correct horse", now)
+		assert.ErrorIs(t, err, myerrors.ErrInvalidCredentials)
+	})
+}
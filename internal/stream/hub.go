@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	// Interval — как часто проверяются наступившие приёмы.
+	Interval time.Duration
+	// Heartbeat — период комментариев, не дающих прокси закрыть соединение.
+	Heartbeat time.Duration
+	// History — сколько последних событий пользователя хранится для
+	// переподключения с Last-Event-ID.
+	History int
+}
+
+// subscriberBuffer — очередь событий одного подключения. Подключение, не
+// успевающее её разбирать, закрывается: клиент переподключится с
+// Last-Event-ID и получит пропущенное из истории.
+const subscriberBuffer = 32
+
+// Hub раздаёт события подключённым клиентам пользователя и хранит
+// последние события для переподключения. Состояние живёт в памяти
+// процесса: после перезапуска история начинается заново.
+type Hub struct {
+	mu          sync.Mutex
+	lastID      int64
+	history     int
+	recent      map[int][]domain.StreamEvent
+	subscribers map[int]map[*Subscription]struct{}
+	// organizations — организация подключённого пользователя: Watcher
+	// проверяет его приёмы в её пределах.
+	organizations map[int]int
+	closed        bool
+}
+
+// Subscription — подключение клиента. Replay — события после Last-Event-ID,
+// которые клиент пропустил; Events закрывается при отписке, остановке Hub
+// или переполнении очереди.
+type Subscription struct {
+	Replay []domain.StreamEvent
+	Events <-chan domain.StreamEvent
+
+	hub    *Hub
+	userID int
+	events chan domain.StreamEvent
+}
+
+func NewHub(cfg Config) *Hub {
+	return &Hub{
+		// Идентификаторы продолжают расти и после перезапуска, поэтому
+		// старый Last-Event-ID не спутается с новыми событиями.
+		lastID:        time.Now().UnixMilli(),
+		history:       cfg.History,
+		recent:        make(map[int][]domain.StreamEvent),
+		subscribers:   make(map[int]map[*Subscription]struct{}),
+		organizations: make(map[int]int),
+	}
+}
+
+// Publish присваивает событию ID, сохраняет его в истории пользователя и
+// рассылает подключённым клиентам.
+func (h *Hub) Publish(event domain.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+
+	h.lastID++
+	event.ID = h.lastID
+
+	recent := append(h.recent[event.UserID], event)
+	if len(recent) > h.history {
+		recent = recent[len(recent)-h.history:]
+	}
+	h.recent[event.UserID] = recent
+
+	for sub := range h.subscribers[event.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe подключает клиента пользователя. Если lastEventID не нулевой,
+// в Replay попадают сохранённые события после него.
+func (h *Hub) Subscribe(identity auth.Identity, lastEventID int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userID := identity.UserID
+	events := make(chan domain.StreamEvent, subscriberBuffer)
+	sub := &Subscription{Events: events, hub: h, userID: userID, events: events}
+	if h.closed {
+		close(events)
+		return sub
+	}
+
+	if lastEventID > 0 {
+		for _, event := range h.recent[userID] {
+			if event.ID > lastEventID {
+				sub.Replay = append(sub.Replay, event)
+			}
+		}
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.organizations[userID] = identity.OrganizationID
+	return sub
+}
+
+// Close отписывает клиента; повторный вызов безопасен.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Users возвращает пользователей, у которых есть подключённые клиенты.
+func (h *Hub) Users() []auth.Identity {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	users := make([]auth.Identity, 0, len(h.subscribers))
+	for userID := range h.subscribers {
+		users = append(users, auth.Identity{UserID: userID, OrganizationID: h.organizations[userID]})
+	}
+	return users
+}
+
+// Close закрывает все подключения, чтобы сервер мог завершиться, не
+// дожидаясь клиентов.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove вызывается под h.mu.
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+		delete(h.organizations, sub.userID)
+	}
+}
@@ -0,0 +1,130 @@
+package stream_test
+
+import (
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHub() *stream.Hub {
+	return stream.NewHub(stream.Config{Interval: time.Minute, Heartbeat: time.Minute, History: 3})
+}
+
+// identity — пользователь организации 1.
+func identity(userID int) auth.Identity {
+	return auth.Identity{UserID: userID, OrganizationID: 1}
+}
+
+func changed(userID, scheduleID int) domain.StreamEvent {
+	return domain.StreamEvent{Type: domain.StreamScheduleChanged, UserID: userID, ScheduleID: scheduleID}
+}
+
+func TestHubPublish(t *testing.T) {
+	t.Run("delivers only to the user's subscribers", func(t *testing.T) {
+		hub := newHub()
+		own := hub.Subscribe(identity(1), 0)
+		defer own.Close()
+		other := hub.Subscribe(identity(2), 0)
+		defer other.Close()
+
+		hub.Publish(changed(1, 10))
+
+		select {
+		case event := <-own.Events:
+			assert.Equal(t, 10, event.ScheduleID)
+			assert.NotZero(t, event.ID)
+		default:
+			t.Fatal("event was not delivered")
+		}
+		assert.Empty(t, other.Events)
+	})
+
+	t.Run("ids grow", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(1), 0)
+		defer sub.Close()
+
+		hub.Publish(changed(1, 10))
+		hub.Publish(changed(1, 11))
+
+		first, second := <-sub.Events, <-sub.Events
+		assert.Greater(t, second.ID, first.ID)
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(1), 0)
+
+		for i := 0; i <= 32; i++ {
+			hub.Publish(changed(1, i))
+		}
+
+		received := 0
+		for range sub.Events {
+			received++
+		}
+		assert.Equal(t, 32, received)
+		assert.Empty(t, hub.Users())
+		sub.Close()
+	})
+}
+
+func TestHubReplay(t *testing.T) {
+	hub := newHub()
+	first := hub.Subscribe(identity(1), 0)
+	for i := 1; i <= 5; i++ {
+		hub.Publish(changed(1, i))
+	}
+	hub.Publish(changed(2, 100))
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		ids = append(ids, (<-first.Events).ID)
+	}
+	first.Close()
+
+	t.Run("events after Last-Event-ID", func(t *testing.T) {
+		sub := hub.Subscribe(identity(1), ids[3])
+		defer sub.Close()
+
+		require.Len(t, sub.Replay, 1)
+		assert.Equal(t, 5, sub.Replay[0].ScheduleID)
+	})
+
+	t.Run("history is limited", func(t *testing.T) {
+		sub := hub.Subscribe(identity(1), ids[0])
+		defer sub.Close()
+
+		require.Len(t, sub.Replay, 3)
+		assert.Equal(t, 3, sub.Replay[0].ScheduleID)
+	})
+
+	t.Run("no replay without Last-Event-ID", func(t *testing.T) {
+		sub := hub.Subscribe(identity(1), 0)
+		defer sub.Close()
+
+		assert.Empty(t, sub.Replay)
+	})
+}
+
+func TestHubClose(t *testing.T) {
+	hub := newHub()
+	sub := hub.Subscribe(identity(1), 0)
+
+	hub.Close()
+
+	_, ok := <-sub.Events
+	assert.False(t, ok)
+	sub.Close()
+
+	late := hub.Subscribe(identity(1), 0)
+	_, ok = <-late.Events
+	assert.False(t, ok)
+	assert.Empty(t, hub.Users())
+}
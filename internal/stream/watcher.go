@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"time"
+)
+
+// TakingsService — тот же расчёт запланированных приёмов за интервал, что
+// отдаёт /takings.
+type TakingsService interface {
+	GetTakings(ctx context.Context, userID int, from, to time.Time) ([]domain.Occurrence, error)
+}
+
+// Watcher периодически проверяет приёмы пользователей, у которых открыт
+// поток, и публикует taking_due, когда время приёма наступило.
+type Watcher struct {
+	schedules TakingsService
+	hub       *Hub
+	interval  time.Duration
+	logger    *slog.Logger
+	// checked — момент последней проверки по пользователю: приёмы до него
+	// уже опубликованы.
+	checked map[int]time.Time
+}
+
+func NewWatcher(schedules TakingsService, hub *Hub, cfg Config, logger *slog.Logger) *Watcher {
+	return &Watcher{
+		schedules: schedules,
+		hub:       hub,
+		interval:  cfg.Interval,
+		logger:    logger,
+		checked:   make(map[int]time.Time),
+	}
+}
+
+// Run работает до отмены ctx.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Stream watcher started", "interval", w.interval)
+	for {
+		w.Check(ctx, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Stream watcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check публикует все приёмы, наступившие после предыдущей проверки и не
+// позже now, и возвращает их число: если расписание чаще интервала
+// проверки, за одну проверку может наступить несколько его приёмов. Для
+// только что подключившихся пользователей проверка начинается с now: о
+// прошедших приёмах поток не сообщает.
+func (w *Watcher) Check(ctx context.Context, now time.Time) int {
+	users := w.hub.Users()
+	active := make(map[int]time.Time, len(users))
+	published := 0
+
+	for _, identity := range users {
+		userID := identity.UserID
+		since, ok := w.checked[userID]
+		if !ok {
+			active[userID] = now
+			continue
+		}
+
+		// Проверка идёт от имени самого пользователя и в его организации:
+		// поток у него открыт.
+		// GetTakings перечисляет приёмы в [from, to), а нужны (since, now].
+		takings, err := w.schedules.GetTakings(auth.WithIdentity(ctx, identity), userID,
+			since.Add(time.Nanosecond), now.Add(time.Nanosecond))
+		if err != nil {
+			// Проверка повторится со старой отметкой, приёмы не потеряются.
+			w.logger.Error("Failed to check due takings", "userID", userID, "error", err)
+			active[userID] = since
+			continue
+		}
+
+		for _, taking := range takings {
+			w.hub.Publish(domain.StreamEvent{
+				Type:       domain.StreamTakingDue,
+				UserID:     userID,
+				ScheduleID: taking.ScheduleID,
+				Medication: taking.Medication,
+				At:         taking.At,
+				Dosage:     taking.Dosage,
+			})
+			published++
+		}
+		active[userID] = now
+	}
+
+	w.checked = active
+	return published
+}
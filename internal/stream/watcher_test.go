@@ -0,0 +1,138 @@
+package stream_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"medication-scheduler/internal/auth"
+	"medication-scheduler/internal/domain"
+	"medication-scheduler/internal/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTakingsService struct {
+	mock.Mock
+}
+
+func (m *MockTakingsService) GetTakings(ctx context.Context, userID int, from, to time.Time) ([]domain.Occurrence, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]domain.Occurrence), args.Error(1)
+}
+
+// plannedTakings считает приёмы по настоящим расписаниям, как
+// ScheduleService.GetTakings.
+type plannedTakings []domain.Schedule
+
+func (p plannedTakings) GetTakings(_ context.Context, _ int, from, to time.Time) ([]domain.Occurrence, error) {
+	return domain.Occurrences(p, from, to), nil
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestWatcherCheck(t *testing.T) {
+	start := time.Date(2025, 1, 2, 8, 59, 0, 0, time.UTC)
+	now := start.Add(2 * time.Minute)
+	taking := domain.Occurrence{
+		ScheduleID: 1,
+		Medication: "Aspirin",
+		At:         time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+		Dosage:     domain.Dosage{Amount: 1, Unit: domain.UnitTablet},
+	}
+	from, to := start.Add(time.Nanosecond), now.Add(time.Nanosecond)
+
+	t.Run("publishes takings that became due", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(42), 0)
+		defer sub.Close()
+
+		svc := new(MockTakingsService)
+		inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+			organizationID, ok := auth.OrganizationIDFrom(ctx)
+			return ok && organizationID == 1
+		})
+		svc.On("GetTakings", inOrganization, 42, from, to).Return([]domain.Occurrence{taking}, nil).Once()
+		watcher := stream.NewWatcher(svc, hub, stream.Config{Interval: time.Minute}, discardLogger())
+
+		assert.Equal(t, 0, watcher.Check(context.Background(), start))
+		assert.Equal(t, 1, watcher.Check(context.Background(), now))
+
+		event := <-sub.Events
+		assert.Equal(t, domain.StreamTakingDue, event.Type)
+		assert.Equal(t, 1, event.ScheduleID)
+		assert.Equal(t, "Aspirin", event.Medication)
+		assert.Equal(t, taking.At, event.At)
+		assert.Equal(t, taking.Dosage, event.Dosage)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("retries from the last mark on error", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(42), 0)
+		defer sub.Close()
+
+		svc := new(MockTakingsService)
+		svc.On("GetTakings", mock.Anything, 42, from, to).Return([]domain.Occurrence(nil), errors.New("db down")).Once()
+		svc.On("GetTakings", mock.Anything, 42, from, to.Add(time.Minute)).Return([]domain.Occurrence{taking}, nil).Once()
+		watcher := stream.NewWatcher(svc, hub, stream.Config{Interval: time.Minute}, discardLogger())
+
+		watcher.Check(context.Background(), start)
+		assert.Equal(t, 0, watcher.Check(context.Background(), now))
+		assert.Equal(t, 1, watcher.Check(context.Background(), now.Add(time.Minute)))
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("forgets disconnected users", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(42), 0)
+
+		svc := new(MockTakingsService)
+		watcher := stream.NewWatcher(svc, hub, stream.Config{Interval: time.Minute}, discardLogger())
+
+		watcher.Check(context.Background(), start)
+		sub.Close()
+		assert.Equal(t, 0, watcher.Check(context.Background(), now))
+		svc.AssertNotCalled(t, "GetTakings", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("publishes every taking of a frequent schedule", func(t *testing.T) {
+		hub := newHub()
+		sub := hub.Subscribe(identity(42), 0)
+		defer sub.Close()
+
+		begin := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+		frequent := plannedTakings{{
+			ID:         1,
+			UserID:     42,
+			Medication: "Aspirin",
+			Kind:       domain.KindInterval,
+			Frequency:  15 * time.Minute,
+			Timezone:   "UTC",
+			StartTime:  begin,
+			EndTime:    begin.Add(24 * time.Hour),
+		}}
+		watcher := stream.NewWatcher(frequent, hub, stream.Config{Interval: time.Hour}, discardLogger())
+
+		watcher.Check(context.Background(), begin)
+		assert.Equal(t, 3, watcher.Check(context.Background(), begin.Add(45*time.Minute)))
+		assert.Equal(t, 1, watcher.Check(context.Background(), begin.Add(time.Hour)))
+
+		var due []time.Time
+		for len(due) < 4 {
+			due = append(due, (<-sub.Events).At)
+		}
+		assert.Equal(t, []time.Time{
+			begin.Add(15 * time.Minute),
+			begin.Add(30 * time.Minute),
+			begin.Add(45 * time.Minute),
+			begin.Add(time.Hour),
+		}, due)
+	})
+}
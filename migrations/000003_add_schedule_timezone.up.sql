@@ -0,0 +1,3 @@
+-- Часовой пояс пациента (IANA), в котором строится окно приёма
+ALTER TABLE schedules
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
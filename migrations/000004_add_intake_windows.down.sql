@@ -0,0 +1,4 @@
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS window_start,
+    DROP COLUMN IF EXISTS window_end;
+DROP TABLE IF EXISTS user_settings;
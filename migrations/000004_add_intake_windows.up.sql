@@ -0,0 +1,10 @@
+-- Персональное окно приёма пользователя (минуты от местной полуночи)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INT PRIMARY KEY,
+    window_start INT NOT NULL,
+    window_end INT NOT NULL
+);
+-- Окно конкретного расписания; NULL — наследуется из user_settings
+ALTER TABLE schedules
+    ADD COLUMN window_start INT,
+    ADD COLUMN window_end INT;
@@ -0,0 +1,3 @@
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS kind,
+    DROP COLUMN IF EXISTS times_of_day;
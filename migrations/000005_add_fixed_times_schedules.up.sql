@@ -0,0 +1,4 @@
+-- Вид расписания: interval (каждые frequency) или fixed_times (в заданное время суток)
+ALTER TABLE schedules
+    ADD COLUMN kind TEXT NOT NULL DEFAULT 'interval',
+    ADD COLUMN times_of_day INT[] NOT NULL DEFAULT '{}';
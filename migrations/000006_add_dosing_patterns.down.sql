@@ -0,0 +1,4 @@
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS weekdays,
+    DROP COLUMN IF EXISTS cycle_on_days,
+    DROP COLUMN IF EXISTS cycle_off_days;
@@ -0,0 +1,6 @@
+-- Маска дней недели (бит 0 — воскресенье; 0 — каждый день)
+-- и цикл приёма/перерыва в днях (0/0 — без цикла)
+ALTER TABLE schedules
+    ADD COLUMN weekdays SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN cycle_on_days INT NOT NULL DEFAULT 0,
+    ADD COLUMN cycle_off_days INT NOT NULL DEFAULT 0;
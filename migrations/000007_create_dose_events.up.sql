@@ -0,0 +1,14 @@
+-- Отметки о фактическом приёме: одна запись на запланированный приём
+CREATE TABLE IF NOT EXISTS dose_events (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    schedule_id INT NOT NULL REFERENCES schedules (id) ON DELETE CASCADE,
+    planned_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    snoozed_until TIMESTAMPTZ,
+    taken_at TIMESTAMPTZ,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (schedule_id, planned_at)
+);
+CREATE INDEX IF NOT EXISTS dose_events_user_planned_idx ON dose_events (user_id, planned_at);
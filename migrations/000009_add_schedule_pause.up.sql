@@ -0,0 +1,2 @@
+-- Момент приостановки расписания; NULL — расписание действует
+ALTER TABLE schedules ADD COLUMN paused_at TIMESTAMPTZ;
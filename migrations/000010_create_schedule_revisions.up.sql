@@ -0,0 +1,30 @@
+-- Редакции расписаний: каждое изменение закрывает действующую редакцию и
+-- открывает новую, чтобы прошлые приёмы считались по прежним правилам
+CREATE TABLE IF NOT EXISTS schedule_revisions (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES schedules (id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL,
+    effective_to TIMESTAMPTZ,
+    medication TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    frequency BIGINT NOT NULL,
+    times_of_day INT[] NOT NULL DEFAULT '{}',
+    duration BIGINT NOT NULL,
+    timezone TEXT NOT NULL,
+    window_start INT,
+    window_end INT,
+    weekdays SMALLINT NOT NULL DEFAULT 0,
+    cycle_on_days INT NOT NULL DEFAULT 0,
+    cycle_off_days INT NOT NULL DEFAULT 0,
+    end_time TIMESTAMPTZ NOT NULL,
+    paused_at TIMESTAMPTZ,
+    UNIQUE (schedule_id, revision)
+);
+-- Существующие расписания получают первую редакцию
+INSERT INTO schedule_revisions
+    (schedule_id, revision, effective_from, medication, kind, frequency, times_of_day, duration, timezone,
+    window_start, window_end, weekdays, cycle_on_days, cycle_off_days, end_time, paused_at)
+SELECT id, 1, start_time, medication, kind, frequency, times_of_day, duration, timezone,
+    window_start, window_end, weekdays, cycle_on_days, cycle_off_days, end_time, paused_at
+FROM schedules;
@@ -0,0 +1,11 @@
+ALTER TABLE schedule_revisions
+    DROP COLUMN IF EXISTS dose_amount,
+    DROP COLUMN IF EXISTS dose_unit,
+    DROP COLUMN IF EXISTS dose_form,
+    DROP COLUMN IF EXISTS dose_route;
+
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS dose_amount,
+    DROP COLUMN IF EXISTS dose_unit,
+    DROP COLUMN IF EXISTS dose_form,
+    DROP COLUMN IF EXISTS dose_route;
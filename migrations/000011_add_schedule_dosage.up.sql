@@ -0,0 +1,13 @@
+-- Доза на один приём: количество, единица, лекарственная форма и путь
+-- введения; пустые значения — доза не указана
+ALTER TABLE schedules
+    ADD COLUMN dose_amount NUMERIC(12, 3) NOT NULL DEFAULT 0,
+    ADD COLUMN dose_unit TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dose_form TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dose_route TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE schedule_revisions
+    ADD COLUMN dose_amount NUMERIC(12, 3) NOT NULL DEFAULT 0,
+    ADD COLUMN dose_unit TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dose_form TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dose_route TEXT NOT NULL DEFAULT '';
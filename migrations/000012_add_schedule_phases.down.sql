@@ -0,0 +1,2 @@
+ALTER TABLE schedule_revisions DROP COLUMN IF EXISTS phases;
+ALTER TABLE schedules DROP COLUMN IF EXISTS phases;
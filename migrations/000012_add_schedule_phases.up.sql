@@ -0,0 +1,4 @@
+-- Этапы ступенчатого расписания (снижение или наращивание дозы) в порядке
+-- следования; пустой массив — расписание без этапов
+ALTER TABLE schedules ADD COLUMN phases JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE schedule_revisions ADD COLUMN phases JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,7 @@
+ALTER TABLE schedule_revisions
+    DROP COLUMN IF EXISTS min_interval,
+    DROP COLUMN IF EXISTS max_per_day;
+
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS min_interval,
+    DROP COLUMN IF EXISTS max_per_day;
@@ -0,0 +1,9 @@
+-- Приём по требованию (kind = 'as_needed'): минимальный интервал между
+-- приёмами в миллисекундах и максимум приёмов за сутки (0 — без ограничения)
+ALTER TABLE schedules
+    ADD COLUMN min_interval BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN max_per_day INT NOT NULL DEFAULT 0;
+
+ALTER TABLE schedule_revisions
+    ADD COLUMN min_interval BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN max_per_day INT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE schedule_revisions DROP COLUMN IF EXISTS anchors;
+ALTER TABLE schedules DROP COLUMN IF EXISTS anchors;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS events;
@@ -0,0 +1,8 @@
+-- Время событий дня пациента (завтрак, обед, ужин, отход ко сну) в минутах
+-- от полуночи; не заданные события берутся по умолчанию
+ALTER TABLE user_settings ADD COLUMN events JSONB NOT NULL DEFAULT '{}';
+
+-- Привязки приёмов к событиям дня (kind = 'event_relative'): событие и
+-- смещение от него в минутах
+ALTER TABLE schedules ADD COLUMN anchors JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE schedule_revisions ADD COLUMN anchors JSONB NOT NULL DEFAULT '[]';
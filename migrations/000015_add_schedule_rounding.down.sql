@@ -0,0 +1,7 @@
+ALTER TABLE schedule_revisions
+    DROP COLUMN IF EXISTS rounding_mode,
+    DROP COLUMN IF EXISTS rounding_step;
+
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS rounding_mode,
+    DROP COLUMN IF EXISTS rounding_step;
@@ -0,0 +1,10 @@
+-- Округление приёмов интервального расписания: режим (none, nearest, up,
+-- down) и шаг сетки в миллисекундах. Существующие расписания сохраняют
+-- прежнее поведение — вверх до 15 минут
+ALTER TABLE schedules
+    ADD COLUMN rounding_mode TEXT NOT NULL DEFAULT 'up',
+    ADD COLUMN rounding_step BIGINT NOT NULL DEFAULT 900000;
+
+ALTER TABLE schedule_revisions
+    ADD COLUMN rounding_mode TEXT NOT NULL DEFAULT 'up',
+    ADD COLUMN rounding_step BIGINT NOT NULL DEFAULT 900000;
@@ -0,0 +1,17 @@
+-- Учётные записи. Внешний ключ на users из schedules и других таблиц не
+-- добавляется: в них уже есть user_id, выданные до появления учётных
+-- записей
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Новые пользователи получают ID после уже занятых user_id, чтобы не
+-- получить доступ к чужим данным
+SELECT setval(pg_get_serial_sequence('users', 'id'),
+              GREATEST((SELECT COALESCE(MAX(user_id), 0) FROM schedules),
+                       (SELECT COALESCE(MAX(user_id), 0) FROM user_settings),
+                       (SELECT COALESCE(MAX(user_id), 0) FROM dose_events)) + 1,
+              false);
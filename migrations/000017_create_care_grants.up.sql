@@ -0,0 +1,14 @@
+-- Доступ доверенных лиц к данным пациента. Приглашение создаётся со
+-- статусом pending и даёт права только после принятия
+CREATE TABLE IF NOT EXISTS care_grants (
+    id SERIAL PRIMARY KEY,
+    patient_id INT NOT NULL,
+    caregiver_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    access TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    UNIQUE (patient_id, caregiver_id)
+);
+
+CREATE INDEX IF NOT EXISTS care_grants_caregiver_idx ON care_grants (caregiver_id);
@@ -0,0 +1,10 @@
+-- Роли учётных записей в клинике. Пользователь без записи ведёт свои
+-- расписания сам
+CREATE TABLE IF NOT EXISTS memberships (
+    user_id INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('patient', 'nurse', 'doctor', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS memberships_role_idx ON memberships (role);
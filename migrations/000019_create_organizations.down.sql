@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS schedules_organization_user_idx;
+ALTER TABLE schedules DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organizations;
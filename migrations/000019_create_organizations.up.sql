@@ -0,0 +1,26 @@
+-- Организации (клиники), обслуживаемые одним развёртыванием. Уже
+-- существующие учётные записи и расписания попадают в организацию default
+CREATE TABLE IF NOT EXISTS organizations (
+    id SERIAL PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organizations (slug, name) VALUES ('default', 'Default') ON CONFLICT (slug) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE slug = 'default')
+WHERE organization_id IS NULL;
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+
+-- Организация расписания совпадает с организацией владельца; у расписаний,
+-- созданных до учётных записей, — default
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE schedules s
+SET organization_id = COALESCE((SELECT u.organization_id FROM users u WHERE u.id = s.user_id),
+                               (SELECT id FROM organizations WHERE slug = 'default'))
+WHERE organization_id IS NULL;
+ALTER TABLE schedules ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS schedules_organization_user_idx ON schedules (organization_id, user_id);
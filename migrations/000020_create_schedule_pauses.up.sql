@@ -0,0 +1,9 @@
+-- Завершённые приостановки расписаний. Пауза не меняет назначение и не
+-- создаёт редакцию, но приёмы за её время не должны считаться пропущенными
+CREATE TABLE IF NOT EXISTS schedule_pauses (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES schedules (id) ON DELETE CASCADE,
+    paused_at TIMESTAMPTZ NOT NULL,
+    resumed_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS schedule_pauses_schedule_idx ON schedule_pauses (schedule_id);
@@ -0,0 +1,7 @@
+-- Подписки на календарь: календарные приложения не передают заголовков,
+-- поэтому пользователя определяет токен в ссылке. Хранится только его хеш
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+-- Приглашения в организацию: зарегистрироваться в клинике можно только по
+-- одноразовому токену, выданному её администратором. Хранится хеш токена
+CREATE TABLE IF NOT EXISTS organization_invites (
+    id SERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_by INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_by INT REFERENCES users (id) ON DELETE SET NULL,
+    used_at TIMESTAMPTZ
+);
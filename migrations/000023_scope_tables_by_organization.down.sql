@@ -0,0 +1,11 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS care_grants_organization_caregiver_idx;
+ALTER TABLE care_grants DROP COLUMN IF EXISTS organization_id;
+CREATE INDEX IF NOT EXISTS care_grants_caregiver_idx ON care_grants (caregiver_id);
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS dose_events_organization_user_planned_idx;
+ALTER TABLE dose_events DROP COLUMN IF EXISTS organization_id;
+CREATE INDEX IF NOT EXISTS dose_events_user_planned_idx ON dose_events (user_id, planned_at);
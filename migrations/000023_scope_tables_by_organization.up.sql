@@ -0,0 +1,35 @@
+-- Отметки о приёмах, очередь напоминаний, доступы доверенных лиц и
+-- настройки пользователей тоже принадлежат организации, чтобы изоляция
+-- клиник не держалась только на проверках в сервисах
+ALTER TABLE dose_events ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE dose_events d SET organization_id = s.organization_id
+FROM schedules s
+WHERE s.id = d.schedule_id AND d.organization_id IS NULL;
+ALTER TABLE dose_events ALTER COLUMN organization_id SET NOT NULL;
+DROP INDEX IF EXISTS dose_events_user_planned_idx;
+CREATE INDEX IF NOT EXISTS dose_events_organization_user_planned_idx
+    ON dose_events (organization_id, user_id, planned_at);
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE outbox o SET organization_id = s.organization_id
+FROM schedules s
+WHERE s.id = o.schedule_id AND o.organization_id IS NULL;
+ALTER TABLE outbox ALTER COLUMN organization_id SET NOT NULL;
+
+-- Доступ выдаётся в организации пациента; пациенты без учётной записи
+-- остаются в default
+ALTER TABLE care_grants ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE care_grants g
+SET organization_id = COALESCE((SELECT u.organization_id FROM users u WHERE u.id = g.patient_id),
+                               (SELECT id FROM organizations WHERE slug = 'default'))
+WHERE organization_id IS NULL;
+ALTER TABLE care_grants ALTER COLUMN organization_id SET NOT NULL;
+DROP INDEX IF EXISTS care_grants_caregiver_idx;
+CREATE INDEX IF NOT EXISTS care_grants_organization_caregiver_idx ON care_grants (organization_id, caregiver_id);
+
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations (id);
+UPDATE user_settings us
+SET organization_id = COALESCE((SELECT u.organization_id FROM users u WHERE u.id = us.user_id),
+                               (SELECT id FROM organizations WHERE slug = 'default'))
+WHERE organization_id IS NULL;
+ALTER TABLE user_settings ALTER COLUMN organization_id SET NOT NULL;
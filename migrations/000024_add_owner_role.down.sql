@@ -0,0 +1,5 @@
+UPDATE memberships SET role = 'admin', updated_at = NOW() WHERE role = 'owner';
+
+ALTER TABLE memberships DROP CONSTRAINT IF EXISTS memberships_role_check;
+ALTER TABLE memberships ADD CONSTRAINT memberships_role_check
+    CHECK (role IN ('patient', 'nurse', 'doctor', 'admin'));
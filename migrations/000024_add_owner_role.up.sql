@@ -0,0 +1,12 @@
+-- Создатель собственной организации получает роль owner: права
+-- пользователя вне клиники и управление участниками. Раньше при регистрации
+-- выдавалась роль admin, у которой нет права вести свои расписания; такие
+-- создатели узнаются по организации, названной их адресом
+ALTER TABLE memberships DROP CONSTRAINT IF EXISTS memberships_role_check;
+ALTER TABLE memberships ADD CONSTRAINT memberships_role_check
+    CHECK (role IN ('patient', 'nurse', 'doctor', 'admin', 'owner'));
+
+UPDATE memberships m SET role = 'owner', updated_at = NOW()
+FROM users u
+JOIN organizations o ON o.id = u.organization_id
+WHERE u.id = m.user_id AND m.role = 'admin' AND o.slug = u.email;
@@ -0,0 +1,2 @@
+-- Прежние slug и названия не восстанавливаются: в них был адрес пользователя
+SELECT 1;
@@ -0,0 +1,7 @@
+-- Собственные организации, созданные при регистрации, назывались адресом
+-- пользователя. Адрес видят участники организации, поэтому slug заменяется
+-- случайным, а название — нейтральным
+UPDATE organizations o
+SET slug = 'org-' || substr(md5(random()::text || o.id::text), 1, 16), name = 'Personal'
+FROM users u
+WHERE u.organization_id = o.id AND o.slug = u.email;
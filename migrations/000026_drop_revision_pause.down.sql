@@ -0,0 +1 @@
+ALTER TABLE schedule_revisions ADD COLUMN IF NOT EXISTS paused_at TIMESTAMPTZ;
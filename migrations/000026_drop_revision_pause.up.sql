@@ -0,0 +1,15 @@
+-- Приостановка не входит в назначение: прошлые паузы хранятся только в
+-- schedule_pauses. Паузы, записанные в редакции до её появления,
+-- переносятся туда; пауза, которая уже есть в schedule_pauses или ещё
+-- действует, не дублируется
+INSERT INTO schedule_pauses (schedule_id, paused_at, resumed_at)
+SELECT r.schedule_id, r.paused_at, MAX(r.effective_to)
+FROM schedule_revisions r
+JOIN schedules s ON s.id = r.schedule_id
+WHERE r.paused_at IS NOT NULL AND r.effective_to IS NOT NULL
+  AND s.paused_at IS DISTINCT FROM r.paused_at
+  AND NOT EXISTS (
+      SELECT 1 FROM schedule_pauses p WHERE p.schedule_id = r.schedule_id AND p.paused_at = r.paused_at)
+GROUP BY r.schedule_id, r.paused_at;
+
+ALTER TABLE schedule_revisions DROP COLUMN IF EXISTS paused_at;
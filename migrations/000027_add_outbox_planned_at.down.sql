@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS outbox_schedule_planned_idx;
+ALTER TABLE outbox DROP COLUMN IF EXISTS kind;
+ALTER TABLE outbox DROP COLUMN IF EXISTS planned_at;
@@ -0,0 +1,16 @@
+-- Напоминание знает, о каком приёме оно и какого оно вида: отметка о приёме
+-- снимает с очереди все его напоминания, включая повторное после отсрочки
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS planned_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'scheduled' CHECK (kind IN ('scheduled', 'snooze'));
+
+UPDATE outbox SET kind = 'snooze' WHERE idempotency_key LIKE '%:snooze';
+UPDATE outbox o SET planned_at = COALESCE(
+    (SELECT MAX(d.planned_at) FROM dose_events d
+     WHERE d.schedule_id = o.schedule_id AND d.snoozed_until = o.due_at),
+    o.due_at)
+WHERE o.kind = 'snooze' AND o.planned_at IS NULL;
+UPDATE outbox SET planned_at = due_at WHERE planned_at IS NULL;
+ALTER TABLE outbox ALTER COLUMN planned_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_schedule_planned_idx ON outbox (schedule_id, planned_at) WHERE status = 'pending';